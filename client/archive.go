@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"tenders/models"
+)
+
+// PurgeArchive окончательно удаляет тендеры, предложения и отзывы, которые
+// находятся в архиве дольше retentionDays дней. Доступно администраторам площадки.
+func (c *Client) PurgeArchive(ctx context.Context, retentionDays int) (*models.PurgeResult, error) {
+	q := url.Values{}
+	q.Set("retentionDays", strconv.Itoa(retentionDays))
+	var result models.PurgeResult
+	if err := c.do(ctx, request{method: http.MethodDelete, path: path("archive"), query: q}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"tenders/models"
+)
+
+// BidPatch - частичная правка предложения: передаются только заданные поля
+type BidPatch struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// CreateBid создает предложение по тендеру. Из b используются name,
+// description, tenderId, organizationId и creatorUsername.
+func (c *Client) CreateBid(ctx context.Context, b models.Bid) (*models.Bid, error) {
+	return c.bid(ctx, request{method: http.MethodPost, path: "/bids/new", body: b})
+}
+
+// GetUserBids возвращает страницу предложений пользователя, от новых к старым
+func (c *Client) GetUserBids(ctx context.Context, page Page) ([]models.Bid, *PageInfo, error) {
+	q := url.Values{}
+	page.apply(q)
+	return c.bids(ctx, request{method: http.MethodGet, path: "/bids/my", query: q})
+}
+
+// GetBidsForTender возвращает страницу предложений по тендеру, от новых к старым
+func (c *Client) GetBidsForTender(ctx context.Context, tenderID string, page Page) ([]models.Bid, *PageInfo, error) {
+	q := url.Values{}
+	page.apply(q)
+	return c.bids(ctx, request{method: http.MethodGet, path: path("bids", tenderID, "list"), query: q})
+}
+
+// GetBidStatus возвращает текущий статус предложения
+func (c *Client) GetBidStatus(ctx context.Context, bidID string) (string, error) {
+	var status string
+	err := c.do(ctx, request{method: http.MethodGet, path: path("bids", bidID, "status")}, &status)
+	return status, err
+}
+
+// UpdateBidStatus переводит предложение в статус status
+func (c *Client) UpdateBidStatus(ctx context.Context, bidID string, status string) (*models.Bid, error) {
+	return c.bid(ctx, request{
+		method: http.MethodPut,
+		path:   path("bids", bidID, "status"),
+		query:  url.Values{"status": {status}},
+	})
+}
+
+// EditBid меняет поля предложения версии version (If-Match). Если предложение
+// уже изменено, сервер вернет ErrVersionConflict.
+func (c *Client) EditBid(ctx context.Context, bidID string, patch BidPatch, version int) (*models.Bid, error) {
+	return c.bid(ctx, request{
+		method: http.MethodPatch,
+		path:   path("bids", bidID, "edit"),
+		body:   patch,
+		header: http.Header{"If-Match": {strconv.Quote(strconv.Itoa(version))}},
+	})
+}
+
+// SubmitBidDecision отдает голос Approved или Rejected по предложению.
+// Возвращает предложение со статусом после подсчета голосов по политике.
+func (c *Client) SubmitBidDecision(ctx context.Context, bidID string, decision, comment string) (*models.Bid, error) {
+	q := url.Values{"decision": {decision}}
+	if comment != "" {
+		q.Set("comment", comment)
+	}
+	return c.bid(ctx, request{method: http.MethodPut, path: path("bids", bidID, "submit_decision"), query: q})
+}
+
+// GetBidDecisions возвращает действующие голоса по предложению
+func (c *Client) GetBidDecisions(ctx context.Context, bidID string) ([]models.BidDecision, error) {
+	var decisions []models.BidDecision
+	err := c.do(ctx, request{method: http.MethodGet, path: path("bids", bidID, "decisions")}, &decisions)
+	return decisions, err
+}
+
+// RevokeBidDecision отзывает голос пользователя, пока по предложению нет решения
+func (c *Client) RevokeBidDecision(ctx context.Context, bidID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("bids", bidID, "decisions")}, nil)
+}
+
+// ArchiveBid перемещает предложение в архив
+func (c *Client) ArchiveBid(ctx context.Context, bidID string) (*models.Bid, error) {
+	return c.bid(ctx, request{method: http.MethodPut, path: path("bids", bidID, "archive")})
+}
+
+// RestoreBid возвращает предложение из архива
+func (c *Client) RestoreBid(ctx context.Context, bidID string) (*models.Bid, error) {
+	return c.bid(ctx, request{method: http.MethodPut, path: path("bids", bidID, "restore")})
+}
+
+// GetBidDecisionHistory возвращает переписанные и отозванные голоса
+func (c *Client) GetBidDecisionHistory(ctx context.Context, bidID string) ([]models.BidDecisionHistory, error) {
+	var history []models.BidDecisionHistory
+	err := c.do(ctx, request{method: http.MethodGet, path: path("bids", bidID, "decisions", "history")}, &history)
+	return history, err
+}
+
+// SubmitBidFeedback оставляет отзыв на предложение и возвращает созданный отзыв
+func (c *Client) SubmitBidFeedback(ctx context.Context, bidID string, feedback string) (*models.BidReview, error) {
+	var review models.BidReview
+	err := c.do(ctx, request{
+		method: http.MethodPut,
+		path:   path("bids", bidID, "feedback"),
+		query:  url.Values{"bidFeedback": {feedback}},
+	}, &review)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// RollbackBid восстанавливает параметры предложения из версии version.
+// Откат создает новую версию.
+func (c *Client) RollbackBid(ctx context.Context, bidID string, version int) (*models.Bid, error) {
+	return c.bid(ctx, request{method: http.MethodPut, path: path("bids", bidID, "rollback", version)})
+}
+
+// GetBidVersions возвращает историю версий предложения, от последней к первой
+func (c *Client) GetBidVersions(ctx context.Context, bidID string, page Page) ([]models.Version, *PageInfo, error) {
+	return c.versions(ctx, path("bids", bidID, "versions"), page)
+}
+
+// GetBidVersionsDiff возвращает поля предложения, которые отличаются между версиями from и to
+func (c *Client) GetBidVersionsDiff(ctx context.Context, bidID string, from, to int) (*models.VersionDiff, error) {
+	return c.versionDiff(ctx, path("bids", bidID, "versions", "diff"), from, to)
+}
+
+// GetBidReviews возвращает отзывы на предложения автора authorUsername по тендеру
+func (c *Client) GetBidReviews(ctx context.Context, tenderID string, authorUsername string, page Page) ([]models.BidReview, error) {
+	q := url.Values{"authorUsername": {authorUsername}}
+	page.apply(q)
+	var reviews []models.BidReview
+	err := c.do(ctx, request{method: http.MethodGet, path: path("bids", tenderID, "reviews"), query: q}, &reviews)
+	return reviews, err
+}
+
+func (c *Client) bid(ctx context.Context, req request) (*models.Bid, error) {
+	var b models.Bid
+	if err := c.do(ctx, req, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (c *Client) bids(ctx context.Context, req request) ([]models.Bid, *PageInfo, error) {
+	var bids []models.Bid
+	req.page = &PageInfo{}
+	if err := c.do(ctx, req, &bids); err != nil {
+		return nil, nil, err
+	}
+	return bids, req.page, nil
+}
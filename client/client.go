@@ -0,0 +1,212 @@
+// Package client - типизированный клиент API тендеров. Методы соответствуют
+// операциям openapi.yml, принимают и возвращают типы пакета models, а ошибки
+// API возвращают как *Error, которые сравниваются через errors.Is:
+//
+//	c := client.New("http://localhost:8080/api", client.WithToken(token))
+//	tender, err := c.EditTender(ctx, tenderID, client.TenderPatch{Name: &name}, 3)
+//	if errors.Is(err, client.ErrVersionConflict) {
+//		// тендер изменили параллельно, нужно перечитать
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client обращается к API по базовому адресу вида http://host:8080/api.
+// Безопасен для одновременного использования.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	token      string
+}
+
+// Option настраивает Client
+type Option func(*Client)
+
+// WithHTTPClient задает HTTP клиент (таймауты, транспорт). По умолчанию http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithToken задает токен доступа, который передается в заголовке Authorization
+func WithToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// New создает клиент для API по адресу baseURL
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Page - параметры пагинации списков. Нулевые значения не передаются,
+// и сервер применяет свои значения по умолчанию. Cursor берется из PageInfo
+// предыдущего ответа и не сочетается с Offset.
+type Page struct {
+	Limit  int
+	Offset int
+	Cursor string
+}
+
+func (p Page) apply(q url.Values) {
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Cursor != "" {
+		q.Set("cursor", p.Cursor)
+	}
+}
+
+// PageInfo - сведения о странице списка из заголовков X-Total-Count и Link
+type PageInfo struct {
+	// Total - число объектов во всем списке
+	Total int
+	// Next и Prev - курсоры соседних страниц, пустые, если страницы нет
+	Next string
+	Prev string
+}
+
+// NextPage возвращает параметры следующей страницы того же размера
+// и false, если текущая страница последняя
+func (i *PageInfo) NextPage(current Page) (Page, bool) {
+	return Page{Limit: current.Limit, Cursor: i.Next}, i.Next != ""
+}
+
+// parsePageInfo читает заголовки ответа со страницей списка
+func parsePageInfo(h http.Header) *PageInfo {
+	info := &PageInfo{}
+	info.Total, _ = strconv.Atoi(h.Get("X-Total-Count"))
+	for _, link := range strings.Split(h.Get("Link"), ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok {
+			continue
+		}
+		u, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+		if err != nil {
+			continue
+		}
+		switch strings.TrimSpace(params) {
+		case `rel="next"`:
+			info.Next = u.Query().Get("cursor")
+		case `rel="prev"`:
+			info.Prev = u.Query().Get("cursor")
+		}
+	}
+	return info
+}
+
+// request - один вызов API
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	header http.Header
+	body   interface{}
+	// page, если задан, заполняется из заголовков ответа со страницей списка
+	page *PageInfo
+}
+
+// do выполняет запрос и декодирует JSON ответа в out (если out не nil).
+// Ответ со статусом не 2xx возвращается как *Error.
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var body io.Reader
+	if req.body != nil {
+		data, err := json.Marshal(req.body)
+		if err != nil {
+			return fmt.Errorf("encode %s %s body: %w", req.method, req.path, err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, body)
+	if err != nil {
+		return err
+	}
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+	if req.body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return decodeError(resp)
+	}
+	if req.page != nil {
+		*req.page = *parsePageInfo(resp.Header)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s response: %w", req.method, req.path, err)
+	}
+	return nil
+}
+
+// path собирает путь из частей, экранируя значения
+func path(parts ...interface{}) string {
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteByte('/')
+		switch v := part.(type) {
+		case string:
+			b.WriteString(url.PathEscape(v))
+		case int:
+			b.WriteString(strconv.Itoa(v))
+		default:
+			panic(fmt.Sprintf("client: unsupported path part %T", part))
+		}
+	}
+	return b.String()
+}
+
+// Ping проверяет, что сервер готов обрабатывать запросы
+func (c *Client) Ping(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/ping", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,417 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"tenders/client"
+	"tenders/db"
+	"tenders/db/memory"
+	"tenders/internal/auth"
+	"tenders/internal/handlers"
+	"tenders/internal/validate"
+	"tenders/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// newServer поднимает API с настоящими обработчиками, проверкой токенов и
+// хранилищем в памяти. Возвращает идентификаторы организаций по имени их
+// ответственного и клиентов от имени alice, bob и анонимного клиента.
+func newServer(t *testing.T) (store *memory.Storage, orgs map[string]string, asAlice, asBob, anonymous *client.Client) {
+	t.Helper()
+	ctx := context.Background()
+	store = memory.New()
+	employees := map[string]*db.Employee{}
+	orgs = map[string]string{}
+	for _, username := range []string{"alice", "bob"} {
+		e := &db.Employee{Username: username}
+		require.NoError(t, store.CreateEmployee(ctx, e))
+		org := &db.Organization{Name: username + " LLC", Type: db.OrganizationLLC}
+		require.NoError(t, store.CreateOrganization(ctx, org))
+		require.NoError(t, store.AddResponsible(ctx, org.ID, e.ID, "member"))
+		employees[username] = e
+		orgs[username] = org.ID
+	}
+
+	issuer, err := auth.NewHMACIssuer([]byte("0123456789abcdef0123456789abcdef"), time.Hour)
+	require.NoError(t, err)
+	router := handlers.NewRouter(handlers.NewHandler(store), auth.Middleware(issuer, store, auth.Options{}))
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	as := func(e *db.Employee) *client.Client {
+		token, _, err := issuer.Issue(e)
+		require.NoError(t, err)
+		return client.New(server.URL+"/api", client.WithToken(token))
+	}
+	return store, orgs, as(employees["alice"]), as(employees["bob"]), client.New(server.URL + "/api")
+}
+
+func TestTenderLifecycle(t *testing.T) {
+	ctx := context.Background()
+	_, orgs, asAlice, _, anonymous := newServer(t)
+	require.NoError(t, anonymous.Ping(ctx))
+
+	tender, err := asAlice.CreateTender(ctx, models.Tender{
+		Name: "Доставка", Description: "Доставить оборудование", ServiceType: "Delivery", OrganizationID: orgs["alice"],
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Created", tender.Status)
+	require.Equal(t, 1, tender.Version)
+
+	tenders, info, err := asAlice.GetTenders(ctx, []string{"Delivery"}, client.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, tenders, 1)
+	require.Equal(t, 1, info.Total)
+	tenders, info, err = asAlice.GetTenders(ctx, []string{"Construction"}, client.Page{})
+	require.NoError(t, err)
+	require.Empty(t, tenders)
+	require.Zero(t, info.Total)
+
+	tender, err = asAlice.UpdateTenderStatus(ctx, tender.ID, "Published")
+	require.NoError(t, err)
+	require.Equal(t, 2, tender.Version)
+
+	name := "Доставка в Казань"
+	edited, err := asAlice.EditTender(ctx, tender.ID, client.TenderPatch{Name: &name}, tender.Version)
+	require.NoError(t, err)
+	require.Equal(t, name, edited.Name)
+	require.Equal(t, 3, edited.Version)
+
+	// Правка по устаревшей версии
+	_, err = asAlice.EditTender(ctx, tender.ID, client.TenderPatch{Name: &name}, tender.Version)
+	require.ErrorIs(t, err, client.ErrVersionConflict)
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+	versions, info, err := asAlice.GetTenderVersions(ctx, tender.ID, client.Page{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 3, info.Total)
+	require.Len(t, versions, 2)
+	require.Equal(t, 3, versions[0].Version)
+
+	next, ok := info.NextPage(client.Page{Limit: 2})
+	require.True(t, ok)
+	versions, _, err = asAlice.GetTenderVersions(ctx, tender.ID, next)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Equal(t, 1, versions[0].Version)
+
+	diff, err := asAlice.GetTenderVersionsDiff(ctx, tender.ID, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, diff.Changes, 1)
+	require.Equal(t, "name", diff.Changes[0].Field)
+
+	rolledBack, err := asAlice.RollbackTender(ctx, tender.ID, 2)
+	require.NoError(t, err)
+	require.Equal(t, "Доставка", rolledBack.Name)
+	require.Equal(t, 4, rolledBack.Version)
+
+	// Откат к версии со статусом Created - недопустимый переход
+	_, err = asAlice.RollbackTender(ctx, tender.ID, 1)
+	require.ErrorIs(t, err, client.ErrInvalidTransition)
+	require.ErrorAs(t, err, &apiErr)
+	require.NotEmpty(t, apiErr.AllowedStatuses)
+
+	_, err = anonymous.GetTenderStatus(ctx, tender.ID)
+	require.ErrorIs(t, err, client.ErrUnauthorized)
+	status, err := asAlice.GetTenderStatus(ctx, tender.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Published", status)
+}
+
+func TestBidDecision(t *testing.T) {
+	ctx := context.Background()
+	store, orgs, asAlice, asBob, _ := newServer(t)
+
+	tender, err := asAlice.CreateTender(ctx, models.Tender{
+		Name: "Стройка", Description: "Построить склад", ServiceType: "Construction", OrganizationID: orgs["alice"],
+	})
+	require.NoError(t, err)
+	_, err = asAlice.UpdateTenderStatus(ctx, tender.ID, "Published")
+	require.NoError(t, err)
+
+	bid, err := asBob.CreateBid(ctx, models.Bid{
+		Name: "Склад за месяц", Description: "Построим", TenderID: tender.ID, OrganizationID: orgs["bob"], CreatorUsername: "bob",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Created", bid.Status)
+
+	bid, err = asBob.UpdateBidStatus(ctx, bid.ID, "Published")
+	require.NoError(t, err)
+	require.Equal(t, "Published", bid.Status)
+
+	// Решение принимают ответственные организации тендера
+	_, err = asBob.SubmitBidDecision(ctx, bid.ID, "Approved", "")
+	require.ErrorIs(t, err, client.ErrForbidden)
+
+	bid, err = asAlice.SubmitBidDecision(ctx, bid.ID, "Approved", "Подходит")
+	require.NoError(t, err)
+	require.Equal(t, "Approved", bid.Status)
+
+	decisions, err := asBob.GetBidDecisions(ctx, bid.ID)
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	require.Equal(t, "Подходит", decisions[0].Comment)
+
+	review, err := asBob.SubmitBidFeedback(ctx, bid.ID, "Хорошее предложение")
+	require.NoError(t, err)
+	require.Equal(t, bid.ID, review.BidID)
+	require.Equal(t, "Хорошее предложение", review.Description)
+
+	// Одобрение закрывает тендер
+	closed, err := store.GetTender(ctx, tender.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Closed", closed.Status)
+}
+
+func TestArchive(t *testing.T) {
+	ctx := context.Background()
+	store, orgs, asAlice, asBob, _ := newServer(t)
+
+	tender, err := asAlice.CreateTender(ctx, models.Tender{
+		Name: "Ремонт", Description: "Отремонтировать офис", ServiceType: "Construction", OrganizationID: orgs["alice"],
+	})
+	require.NoError(t, err)
+	_, err = asAlice.UpdateTenderStatus(ctx, tender.ID, "Published")
+	require.NoError(t, err)
+	bid, err := asBob.CreateBid(ctx, models.Bid{
+		Name: "Ремонт за неделю", Description: "Сделаем", TenderID: tender.ID, OrganizationID: orgs["bob"], CreatorUsername: "bob",
+	})
+	require.NoError(t, err)
+
+	// Архивировать предложение может только ответственный его организации
+	_, err = asAlice.ArchiveBid(ctx, bid.ID)
+	require.ErrorIs(t, err, client.ErrForbidden)
+	archivedBid, err := asBob.ArchiveBid(ctx, bid.ID)
+	require.NoError(t, err)
+	require.NotNil(t, archivedBid.DeletedAt)
+	bids, _, err := asBob.GetUserBids(ctx, client.Page{})
+	require.NoError(t, err)
+	require.Empty(t, bids)
+
+	archived, err := asAlice.ArchiveTender(ctx, tender.ID)
+	require.NoError(t, err)
+	require.NotNil(t, archived.DeletedAt)
+	require.NotNil(t, archived.DeletedBy)
+
+	// Тендер из архива пропадает из списков, скрыт от посторонних и не меняется
+	tenders, info, err := asBob.GetTenders(ctx, nil, client.Page{})
+	require.NoError(t, err)
+	require.Empty(t, tenders)
+	require.Zero(t, info.Total)
+	_, err = asBob.GetTenderStatus(ctx, tender.ID)
+	require.ErrorIs(t, err, client.ErrNotFound)
+	name := "Ремонт склада"
+	_, err = asAlice.EditTender(ctx, tender.ID, client.TenderPatch{Name: &name}, archived.Version)
+	require.ErrorIs(t, err, client.ErrInvalidState)
+	_, err = asAlice.ArchiveTender(ctx, tender.ID)
+	require.ErrorIs(t, err, client.ErrInvalidState)
+
+	restored, err := asAlice.RestoreTender(ctx, tender.ID)
+	require.NoError(t, err)
+	require.Nil(t, restored.DeletedAt)
+	tenders, _, err = asBob.GetTenders(ctx, nil, client.Page{})
+	require.NoError(t, err)
+	require.Len(t, tenders, 1)
+
+	// Очистка архива доступна только администратору площадки
+	_, err = asAlice.PurgeArchive(ctx, 0)
+	require.ErrorIs(t, err, client.ErrForbidden)
+	alice, err := store.GetEmployeeByUsername(ctx, "alice")
+	require.NoError(t, err)
+	require.NoError(t, store.SetPlatformRole(ctx, alice.ID, "admin"))
+
+	result, err := asAlice.PurgeArchive(ctx, 30)
+	require.NoError(t, err)
+	require.Equal(t, models.PurgeResult{}, *result)
+	result, err = asAlice.PurgeArchive(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, models.PurgeResult{Bids: 1}, *result)
+
+	_, err = asBob.RestoreBid(ctx, bid.ID)
+	require.ErrorIs(t, err, client.ErrNotFound)
+	status, err := asBob.GetTenderStatus(ctx, tender.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Published", status)
+
+	// Записи закупок не удаляются вместе с организацией
+	err = asAlice.DeleteOrganization(ctx, orgs["alice"])
+	require.ErrorIs(t, err, client.ErrInvalidState)
+	_, err = asBob.GetTenderStatus(ctx, tender.ID)
+	require.NoError(t, err)
+}
+
+func TestPagination(t *testing.T) {
+	ctx := context.Background()
+	_, orgs, asAlice, _, _ := newServer(t)
+	for _, name := range []string{"E", "A", "D", "B", "C"} {
+		_, err := asAlice.CreateTender(ctx, models.Tender{
+			Name: name, Description: "Тендер " + name, ServiceType: "Manufacture", OrganizationID: orgs["alice"],
+		})
+		require.NoError(t, err)
+	}
+
+	// Листаем вперед по курсорам
+	var names []string
+	page := client.Page{Limit: 2}
+	for {
+		tenders, info, err := asAlice.GetTenders(ctx, nil, page)
+		require.NoError(t, err)
+		require.Equal(t, 5, info.Total)
+		for _, tender := range tenders {
+			names = append(names, tender.Name)
+		}
+		next, ok := info.NextPage(page)
+		if !ok {
+			break
+		}
+		page = next
+	}
+	require.Equal(t, []string{"A", "B", "C", "D", "E"}, names)
+
+	// Первая страница через offset дает курсоры в обе стороны
+	tenders, info, err := asAlice.GetTenders(ctx, nil, client.Page{Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	require.Equal(t, "C", tenders[0].Name)
+	require.NotEmpty(t, info.Next)
+	require.NotEmpty(t, info.Prev)
+
+	tenders, info, err = asAlice.GetTenders(ctx, nil, client.Page{Limit: 2, Cursor: info.Prev})
+	require.NoError(t, err)
+	require.Equal(t, "A", tenders[0].Name)
+	require.Equal(t, "B", tenders[1].Name)
+	require.Empty(t, info.Prev)
+	require.NotEmpty(t, info.Next)
+
+	_, _, err = asAlice.GetTenders(ctx, nil, client.Page{Limit: 51})
+	require.ErrorIs(t, err, client.ErrInvalidRequest)
+	_, _, err = asAlice.GetTenders(ctx, nil, client.Page{Cursor: "bad"})
+	require.ErrorIs(t, err, client.ErrInvalidRequest)
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	_, orgs, asAlice, _, _ := newServer(t)
+
+	_, err := asAlice.GetTenderStatus(ctx, uuid.NewString())
+	require.ErrorIs(t, err, client.ErrNotFound)
+	require.NotErrorIs(t, err, client.ErrConflict)
+
+	_, err = asAlice.CreateTender(ctx, models.Tender{Name: "Без описания", ServiceType: "Repair", OrganizationID: orgs["alice"]})
+	require.ErrorIs(t, err, client.ErrValidationFailed)
+	var apiErr *client.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	fields := make([]string, len(apiErr.Errors))
+	for i, fe := range apiErr.Errors {
+		fields[i] = fe.Field
+	}
+	require.Equal(t, []string{"description", "serviceType"}, fields)
+
+	// Ответ не в формате errorResponse
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	_, _, err = client.New(server.URL).GetTenders(ctx, nil, client.Page{})
+	require.True(t, errors.Is(err, client.ErrNotFound), "%v", err)
+}
+
+// Коды клиента должны совпадать с кодами сервера. Константы читаются из
+// исходников, поэтому новый код сервера без пары в клиенте роняет тест.
+func TestCodesMatchServer(t *testing.T) {
+	require.Equal(t, codeValues(t, "../internal/apierr/apierr.go"), codeValues(t, "errors.go"))
+	require.Equal(t, jsonFields(reflect.TypeOf(validate.FieldError{})), jsonFields(reflect.TypeOf(client.FieldError{})))
+}
+
+// codeValues возвращает значения констант типа Code из файла path
+func codeValues(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	require.NoError(t, err)
+	var codes []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		if typ, ok := spec.Type.(*ast.Ident); !ok || typ.Name != "Code" {
+			return false
+		}
+		for _, v := range spec.Values {
+			lit, ok := v.(*ast.BasicLit)
+			require.True(t, ok, "code %s is not a string literal", spec.Names[0])
+			code, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			codes = append(codes, code)
+		}
+		return false
+	})
+	require.NotEmpty(t, codes, path)
+	slices.Sort(codes)
+	return codes
+}
+
+// Модели клиента должны сериализоваться в JSON так же, как структуры db,
+// которые отдает сервер
+func TestModelsMatchServer(t *testing.T) {
+	pairs := []struct {
+		server, client interface{}
+		// extra - поля модели, которых нет в ответах сервера
+		extra []string
+	}{
+		{db.Tender{}, models.Tender{}, nil},
+		// AuthorType и AuthorID оставлены в модели для совместимости со схемой
+		{db.Bid{}, models.Bid{}, []string{"authorType", "authorId"}},
+		{db.BidReview{}, models.BidReview{}, nil},
+		{db.Employee{}, models.Employee{}, nil},
+		{db.Organization{}, models.Organization{}, nil},
+		{db.Responsible{}, models.Responsible{}, nil},
+		{db.Version{}, models.Version{}, nil},
+		{db.BidDecision{}, models.BidDecision{}, nil},
+		{db.BidDecisionHistory{}, models.BidDecisionHistory{}, nil},
+		{db.ApprovalPolicy{}, models.ApprovalPolicy{}, nil},
+		{db.PurgeResult{}, models.PurgeResult{}, nil},
+	}
+	for _, p := range pairs {
+		want := jsonFields(reflect.TypeOf(p.server))
+		got := jsonFields(reflect.TypeOf(p.client))
+		for _, name := range p.extra {
+			delete(got, name)
+		}
+		require.Equal(t, want, got, "%T", p.client)
+	}
+}
+
+// jsonFields возвращает поля структуры, которые попадают в JSON, с их тегом
+// и видом значения. Поля с тегом json:"-" пропускаются.
+func jsonFields(t reflect.Type) map[string]string {
+	fields := map[string]string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		kind := f.Type.Kind().String()
+		if f.Type.Kind() == reflect.Pointer {
+			kind = "*" + f.Type.Elem().Kind().String()
+		}
+		fields[name] = tag + " " + kind
+	}
+	return fields
+}
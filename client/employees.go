@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"tenders/models"
+)
+
+// EmployeeFilter - условия поиска сотрудников
+type EmployeeFilter struct {
+	// Search - подстрока username, имени или фамилии
+	Search string
+	// IncludeInactive - включать деактивированных сотрудников
+	IncludeInactive bool
+}
+
+// EmployeePatch - частичная правка сотрудника: передаются только заданные поля
+type EmployeePatch struct {
+	FirstName *string `json:"firstName,omitempty"`
+	LastName  *string `json:"lastName,omitempty"`
+}
+
+// ListEmployees возвращает сотрудников по фильтру
+func (c *Client) ListEmployees(ctx context.Context, filter EmployeeFilter, page Page) ([]models.Employee, *PageInfo, error) {
+	q := url.Values{}
+	if filter.Search != "" {
+		q.Set("search", filter.Search)
+	}
+	if filter.IncludeInactive {
+		q.Set("includeInactive", "true")
+	}
+	page.apply(q)
+	var employees []models.Employee
+	req := request{method: http.MethodGet, path: "/employees", query: q, page: &PageInfo{}}
+	if err := c.do(ctx, req, &employees); err != nil {
+		return nil, nil, err
+	}
+	return employees, req.page, nil
+}
+
+// CreateEmployee создает сотрудника. Из e используются username, firstName и lastName.
+func (c *Client) CreateEmployee(ctx context.Context, e models.Employee) (*models.Employee, error) {
+	return c.employee(ctx, request{method: http.MethodPost, path: "/employees", body: e})
+}
+
+// GetEmployee возвращает сотрудника по username
+func (c *Client) GetEmployee(ctx context.Context, username string) (*models.Employee, error) {
+	return c.employee(ctx, request{method: http.MethodGet, path: path("employees", username)})
+}
+
+// UpdateEmployee меняет имя и фамилию сотрудника
+func (c *Client) UpdateEmployee(ctx context.Context, username string, patch EmployeePatch) (*models.Employee, error) {
+	return c.employee(ctx, request{method: http.MethodPatch, path: path("employees", username), body: patch})
+}
+
+// DeactivateEmployee деактивирует сотрудника: его токены перестают приниматься
+func (c *Client) DeactivateEmployee(ctx context.Context, username string) (*models.Employee, error) {
+	return c.employee(ctx, request{method: http.MethodPost, path: path("employees", username, "deactivate")})
+}
+
+func (c *Client) employee(ctx context.Context, req request) (*models.Employee, error) {
+	var e models.Employee
+	if err := c.do(ctx, req, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
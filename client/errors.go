@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// Code - машиночитаемый код ошибки API (поле code в errorResponse)
+type Code string
+
+// Коды ошибок API. Значения стабильны между версиями.
+const (
+	CodeInvalidRequest       Code = "invalid_request"
+	CodeValidationFailed     Code = "validation_failed"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeConflict             Code = "conflict"
+	CodeVersionConflict      Code = "version_conflict"
+	CodePreconditionRequired Code = "precondition_required"
+	CodeAlreadyExists        Code = "already_exists"
+	CodeInvalidState         Code = "invalid_state"
+	CodeInvalidTransition    Code = "invalid_transition"
+	CodeInvalidReference     Code = "invalid_reference"
+	CodeInternal             Code = "internal_error"
+)
+
+// Образцы для errors.Is: ошибка совпадает с образцом, если совпадает код
+var (
+	ErrInvalidRequest       = &Error{Code: CodeInvalidRequest}
+	ErrValidationFailed     = &Error{Code: CodeValidationFailed}
+	ErrUnauthorized         = &Error{Code: CodeUnauthorized}
+	ErrForbidden            = &Error{Code: CodeForbidden}
+	ErrNotFound             = &Error{Code: CodeNotFound}
+	ErrConflict             = &Error{Code: CodeConflict}
+	ErrVersionConflict      = &Error{Code: CodeVersionConflict}
+	ErrPreconditionRequired = &Error{Code: CodePreconditionRequired}
+	ErrAlreadyExists        = &Error{Code: CodeAlreadyExists}
+	ErrInvalidState         = &Error{Code: CodeInvalidState}
+	ErrInvalidTransition    = &Error{Code: CodeInvalidTransition}
+	ErrInvalidReference     = &Error{Code: CodeInvalidReference}
+	ErrInternal             = &Error{Code: CodeInternal}
+)
+
+// FieldError - неверное поле тела запроса (ответ validation_failed)
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error - ответ API со статусом не 2xx
+type Error struct {
+	// StatusCode - HTTP статус ответа
+	StatusCode int    `json:"-"`
+	Code       Code   `json:"code"`
+	Reason     string `json:"reason"`
+	// Errors - неверные поля, только для CodeValidationFailed
+	Errors []FieldError `json:"errors,omitempty"`
+	// AllowedStatuses - допустимые статусы, только для CodeInvalidTransition
+	AllowedStatuses []string `json:"allowedStatuses,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.Reason
+}
+
+// Is сравнивает ошибки по коду, чтобы работал errors.Is(err, client.ErrNotFound)
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// decodeError читает тело ответа с ошибкой. Если тело не в формате
+// errorResponse (например, ответ прокси), код выводится из статуса.
+func decodeError(resp *http.Response) error {
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if json.Unmarshal(data, apiErr) != nil || apiErr.Code == "" {
+		apiErr.Code = statusCode(resp.StatusCode)
+		apiErr.Reason = http.StatusText(resp.StatusCode)
+	}
+	return apiErr
+}
+
+func statusCode(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionRequired:
+		return CodePreconditionRequired
+	}
+	return CodeInternal
+}
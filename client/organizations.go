@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"tenders/models"
+)
+
+// OrganizationInput - данные новой организации
+type OrganizationInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Type - IE, LLC или JSC
+	Type string `json:"type"`
+	// Responsibles - username первых ответственных, хотя бы один
+	Responsibles []string `json:"responsibles"`
+}
+
+// OrganizationPatch - частичная правка организации: передаются только заданные поля
+type OrganizationPatch struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Type        *string `json:"type,omitempty"`
+}
+
+// ListOrganizations возвращает организации, в названии которых есть search
+func (c *Client) ListOrganizations(ctx context.Context, search string, page Page) ([]models.Organization, *PageInfo, error) {
+	q := url.Values{}
+	if search != "" {
+		q.Set("search", search)
+	}
+	page.apply(q)
+	var organizations []models.Organization
+	req := request{method: http.MethodGet, path: "/organizations", query: q, page: &PageInfo{}}
+	if err := c.do(ctx, req, &organizations); err != nil {
+		return nil, nil, err
+	}
+	return organizations, req.page, nil
+}
+
+// CreateOrganization создает организацию с ответственными
+func (c *Client) CreateOrganization(ctx context.Context, input OrganizationInput) (*models.Organization, error) {
+	return c.organization(ctx, request{method: http.MethodPost, path: "/organizations", body: input})
+}
+
+// GetOrganization возвращает организацию
+func (c *Client) GetOrganization(ctx context.Context, organizationID string) (*models.Organization, error) {
+	return c.organization(ctx, request{method: http.MethodGet, path: path("organizations", organizationID)})
+}
+
+// UpdateOrganization меняет поля организации
+func (c *Client) UpdateOrganization(ctx context.Context, organizationID string, patch OrganizationPatch) (*models.Organization, error) {
+	return c.organization(ctx, request{method: http.MethodPatch, path: path("organizations", organizationID), body: patch})
+}
+
+// DeleteOrganization удаляет организацию. Организацию с тендерами или
+// предложениями удалить нельзя (ErrInvalidState).
+func (c *Client) DeleteOrganization(ctx context.Context, organizationID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("organizations", organizationID)}, nil)
+}
+
+// GetOrganizationResponsibles возвращает ответственных за организацию
+func (c *Client) GetOrganizationResponsibles(ctx context.Context, organizationID string) ([]models.Responsible, error) {
+	var responsibles []models.Responsible
+	err := c.do(ctx, request{method: http.MethodGet, path: path("organizations", organizationID, "responsibles")}, &responsibles)
+	return responsibles, err
+}
+
+// AddOrganizationResponsible назначает сотрудника ответственным с ролью role
+// (member, если role пуста) или меняет роль уже назначенного
+func (c *Client) AddOrganizationResponsible(ctx context.Context, organizationID string, username, role string) (*models.Responsible, error) {
+	body := struct {
+		Username string `json:"username"`
+		Role     string `json:"role,omitempty"`
+	}{username, role}
+	var responsible models.Responsible
+	err := c.do(ctx, request{method: http.MethodPost, path: path("organizations", organizationID, "responsibles"), body: body}, &responsible)
+	if err != nil {
+		return nil, err
+	}
+	return &responsible, nil
+}
+
+// RemoveOrganizationResponsible снимает сотрудника с ответственных.
+// Последнего ответственного снять нельзя (ErrConflict).
+func (c *Client) RemoveOrganizationResponsible(ctx context.Context, organizationID string, username string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("organizations", organizationID, "responsibles", username)}, nil)
+}
+
+func (c *Client) organization(ctx context.Context, req request) (*models.Organization, error) {
+	var o models.Organization
+	if err := c.do(ctx, req, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"tenders/models"
+)
+
+// ApprovalPolicyInput - параметры политики принятия решений
+type ApprovalPolicyInput struct {
+	// Kind - fixed, majority, unanimous или weighted
+	Kind            string         `json:"kind"`
+	Required        int            `json:"required,omitempty"`
+	RejectThreshold int            `json:"rejectThreshold,omitempty"`
+	RoleWeights     map[string]int `json:"roleWeights,omitempty"`
+}
+
+// GetOrganizationApprovalPolicy возвращает политику организации
+func (c *Client) GetOrganizationApprovalPolicy(ctx context.Context, organizationID string) (*models.ApprovalPolicy, error) {
+	return c.policy(ctx, request{method: http.MethodGet, path: path("organizations", organizationID, "approval_policy")})
+}
+
+// PutOrganizationApprovalPolicy задает политику организации
+func (c *Client) PutOrganizationApprovalPolicy(ctx context.Context, organizationID string, input ApprovalPolicyInput) (*models.ApprovalPolicy, error) {
+	return c.policy(ctx, request{method: http.MethodPut, path: path("organizations", organizationID, "approval_policy"), body: input})
+}
+
+// DeleteOrganizationApprovalPolicy удаляет политику организации, после чего действует политика по умолчанию
+func (c *Client) DeleteOrganizationApprovalPolicy(ctx context.Context, organizationID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("organizations", organizationID, "approval_policy")}, nil)
+}
+
+// GetTenderApprovalPolicy возвращает политику, заданную для тендера
+func (c *Client) GetTenderApprovalPolicy(ctx context.Context, tenderID string) (*models.ApprovalPolicy, error) {
+	return c.policy(ctx, request{method: http.MethodGet, path: path("tenders", tenderID, "approval_policy")})
+}
+
+// PutTenderApprovalPolicy задает политику тендера вместо политики организации
+func (c *Client) PutTenderApprovalPolicy(ctx context.Context, tenderID string, input ApprovalPolicyInput) (*models.ApprovalPolicy, error) {
+	return c.policy(ctx, request{method: http.MethodPut, path: path("tenders", tenderID, "approval_policy"), body: input})
+}
+
+// DeleteTenderApprovalPolicy удаляет политику тендера, после чего действует политика организации
+func (c *Client) DeleteTenderApprovalPolicy(ctx context.Context, tenderID string) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: path("tenders", tenderID, "approval_policy")}, nil)
+}
+
+func (c *Client) policy(ctx context.Context, req request) (*models.ApprovalPolicy, error) {
+	var p models.ApprovalPolicy
+	if err := c.do(ctx, req, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
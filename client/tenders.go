@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"tenders/models"
+)
+
+// TenderPatch - частичная правка тендера: передаются только заданные поля
+type TenderPatch struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	ServiceType *string `json:"serviceType,omitempty"`
+}
+
+// GetTenders возвращает страницу тендеров, отсортированных по названию, с
+// фильтром по видам услуг (все виды, если serviceTypes пуст)
+func (c *Client) GetTenders(ctx context.Context, serviceTypes []string, page Page) ([]models.Tender, *PageInfo, error) {
+	q := url.Values{"service_type": serviceTypes}
+	page.apply(q)
+	return c.tenders(ctx, request{method: http.MethodGet, path: "/tenders", query: q})
+}
+
+// CreateTender создает тендер. Из t используются name, description,
+// serviceType и organizationId.
+func (c *Client) CreateTender(ctx context.Context, t models.Tender) (*models.Tender, error) {
+	return c.tender(ctx, request{method: http.MethodPost, path: "/tenders/new", body: t})
+}
+
+// GetUserTenders возвращает страницу тендеров организаций, за которые отвечает пользователь
+func (c *Client) GetUserTenders(ctx context.Context, page Page) ([]models.Tender, *PageInfo, error) {
+	q := url.Values{}
+	page.apply(q)
+	return c.tenders(ctx, request{method: http.MethodGet, path: "/tenders/my", query: q})
+}
+
+// GetTenderStatus возвращает текущий статус тендера
+func (c *Client) GetTenderStatus(ctx context.Context, tenderID string) (string, error) {
+	var status string
+	err := c.do(ctx, request{method: http.MethodGet, path: path("tenders", tenderID, "status")}, &status)
+	return status, err
+}
+
+// UpdateTenderStatus переводит тендер в статус status
+func (c *Client) UpdateTenderStatus(ctx context.Context, tenderID string, status string) (*models.Tender, error) {
+	return c.tender(ctx, request{
+		method: http.MethodPut,
+		path:   path("tenders", tenderID, "status"),
+		query:  url.Values{"status": {status}},
+	})
+}
+
+// EditTender меняет поля тендера версии version (If-Match). Если тендер уже
+// изменен, сервер вернет ErrVersionConflict.
+func (c *Client) EditTender(ctx context.Context, tenderID string, patch TenderPatch, version int) (*models.Tender, error) {
+	return c.tender(ctx, request{
+		method: http.MethodPatch,
+		path:   path("tenders", tenderID, "edit"),
+		body:   patch,
+		header: http.Header{"If-Match": {strconv.Quote(strconv.Itoa(version))}},
+	})
+}
+
+// RollbackTender восстанавливает параметры тендера из версии version.
+// Откат создает новую версию.
+func (c *Client) RollbackTender(ctx context.Context, tenderID string, version int) (*models.Tender, error) {
+	return c.tender(ctx, request{method: http.MethodPut, path: path("tenders", tenderID, "rollback", version)})
+}
+
+// ArchiveTender перемещает тендер в архив
+func (c *Client) ArchiveTender(ctx context.Context, tenderID string) (*models.Tender, error) {
+	return c.tender(ctx, request{method: http.MethodPut, path: path("tenders", tenderID, "archive")})
+}
+
+// RestoreTender возвращает тендер из архива
+func (c *Client) RestoreTender(ctx context.Context, tenderID string) (*models.Tender, error) {
+	return c.tender(ctx, request{method: http.MethodPut, path: path("tenders", tenderID, "restore")})
+}
+
+// GetTenderVersions возвращает историю версий тендера, от последней к первой
+func (c *Client) GetTenderVersions(ctx context.Context, tenderID string, page Page) ([]models.Version, *PageInfo, error) {
+	return c.versions(ctx, path("tenders", tenderID, "versions"), page)
+}
+
+// GetTenderVersionsDiff возвращает поля тендера, которые отличаются между версиями from и to
+func (c *Client) GetTenderVersionsDiff(ctx context.Context, tenderID string, from, to int) (*models.VersionDiff, error) {
+	return c.versionDiff(ctx, path("tenders", tenderID, "versions", "diff"), from, to)
+}
+
+func (c *Client) tender(ctx context.Context, req request) (*models.Tender, error) {
+	var t models.Tender
+	if err := c.do(ctx, req, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (c *Client) tenders(ctx context.Context, req request) ([]models.Tender, *PageInfo, error) {
+	var tenders []models.Tender
+	req.page = &PageInfo{}
+	if err := c.do(ctx, req, &tenders); err != nil {
+		return nil, nil, err
+	}
+	return tenders, req.page, nil
+}
+
+func (c *Client) versions(ctx context.Context, p string, page Page) ([]models.Version, *PageInfo, error) {
+	q := url.Values{}
+	page.apply(q)
+	var versions []models.Version
+	req := request{method: http.MethodGet, path: p, query: q, page: &PageInfo{}}
+	if err := c.do(ctx, req, &versions); err != nil {
+		return nil, nil, err
+	}
+	return versions, req.page, nil
+}
+
+func (c *Client) versionDiff(ctx context.Context, p string, from, to int) (*models.VersionDiff, error) {
+	q := url.Values{"from": {strconv.Itoa(from)}, "to": {strconv.Itoa(to)}}
+	var diff models.VersionDiff
+	if err := c.do(ctx, request{method: http.MethodGet, path: p, query: q}, &diff); err != nil {
+		return nil, err
+	}
+	return &diff, nil
+}
@@ -1,61 +1,135 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"tenders"
 	"tenders/db"
+	"tenders/db/memory"
 	"tenders/db/migrations"
+	"tenders/internal/auth"
 	"tenders/internal/handlers"
+	"tenders/internal/openapi"
+	"time"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/pressly/goose/v3"
 )
 
 func main() {
-	connString := os.Getenv("POSTGRES_CONN")
-	if connString == "" {
-		log.Fatal("POSTGRES_CONN env variable is not set")
+	keyFile := flag.String("auth-key-file", os.Getenv("AUTH_KEY_FILE"),
+		"файл с ключом подписи токенов: PEM с Ed25519 ключом или HMAC секрет (от 32 байт)")
+	tokenTTL := flag.Duration("auth-token-ttl", 12*time.Hour, "срок действия выпускаемых токенов")
+	insecureUsername := flag.Bool("insecure-username-param", false,
+		"НЕБЕЗОПАСНО, только для разработки: принимать пользователя из query параметра username без токена")
+	specValidation := flag.String("openapi-validation", "off",
+		"проверка по openapi.yml: off, requests (только запросы), responses (и ответы, расхождения в лог), strict (расхождение в ответе - 500)")
+	migrateMode := flag.String("migrations", "auto",
+		"миграции при запуске: auto (применить недостающие), check (не запускаться, если схема отстает)")
+	storageKind := flag.String("storage", "postgres",
+		"хранилище: postgres (база из POSTGRES_CONN) или memory (в памяти, данные теряются при остановке)")
+	memoryAdmin := flag.String("memory-admin", "admin",
+		"с -storage=memory: имя администратора площадки, который создается при запуске")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [token <username> | migrate up|down|status|redo]\n", os.Args[0])
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	dbConn, err := sqlx.Connect("postgres", connString)
-	if err != nil {
-		log.Fatalf("Cannot connect to DB: %v", err)
-	}
-	defer dbConn.Close()
-
-	// Вызов функции миграций
-	migrations.Run()
-
-	store := db.NewStorage(dbConn)
-	h := handlers.NewHandler(store)
-
-	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
-
-	r.Route("/api", func(r chi.Router) {
-		r.Get("/ping", h.PingHandler)
-		// тендеры
-		r.Post("/tenders/new", h.CreateTenderHandler)
-		r.Get("/tenders", h.GetTendersHandler)
-		r.Get("/tenders/my", h.GetUserTendersHandler)
-		r.Put("/tenders/{tenderId}/status", h.ChangeTenderStatusHandler)
-		r.Put("/tenders/{tenderId}/rollback/{version}", h.RollbackTenderHandler)
-		// предложения (bids)
-		r.Post("/bids/new", h.CreateBidHandler)
-		r.Get("/bids/my", h.GetUserBidsHandler)
-		r.Put("/bids/{tenderId}/list", h.GetBidsForTenderHandler)
-		r.Patch("/bids/{bidId}/edit", h.EditBidHandler)
-		r.Put("/bids/{bidId}/status", h.UpdateBidStatusHandler)
-		r.Put("/bids/{bidId}/rollback/{version}", h.RollbackBidHandler)
-		r.Put("/bids/{bidId}/submit_decision", h.SubmitBidDecisionHandler)
-		r.Get("/bids/{tenderId}/reviews", h.GetBidReviewsHandler)
-		r.Put("/bids/{bidId}/feedback", h.CreateBidFeedbackHandler)
-
-	})
+	var issuer *auth.Issuer
+	if *keyFile != "" {
+		var err error
+		issuer, err = auth.LoadIssuer(*keyFile, *tokenTTL)
+		if err != nil {
+			log.Fatalf("Cannot load auth key: %v", err)
+		}
+	}
+
+	var (
+		store    handlers.StorageInterface
+		migrator *migrations.Migrator
+	)
+	switch *storageKind {
+	case "postgres":
+		connString := os.Getenv("POSTGRES_CONN")
+		if connString == "" {
+			log.Fatal("POSTGRES_CONN env variable is not set")
+		}
+		dbConn, err := sqlx.Connect("postgres", connString)
+		if err != nil {
+			log.Fatalf("Cannot connect to DB: %v", err)
+		}
+		defer dbConn.Close()
+
+		store = db.NewStorage(dbConn)
+		migrator, err = migrations.New(dbConn.DB)
+		if err != nil {
+			log.Fatalf("Cannot load migrations: %v", err)
+		}
+	case "memory":
+		mem := memory.New()
+		seedMemory(mem, issuer, *memoryAdmin)
+		store = mem
+	default:
+		log.Fatalf("Unknown -storage %q", *storageKind)
+	}
+
+	switch flag.Arg(0) {
+	case "token":
+		// tender-server token <username> выпускает токен доступа для сотрудника
+		issueToken(store, issuer, flag.Arg(1))
+		return
+	case "migrate":
+		// tender-server migrate up|down|status|redo управляет схемой базы
+		if migrator == nil {
+			log.Fatal("migrate works only with -storage=postgres")
+		}
+		migrate(migrator, flag.Arg(1))
+		return
+	}
+
+	if migrator != nil {
+		switch *migrateMode {
+		case "auto":
+			if _, err := migrator.Up(context.Background()); err != nil {
+				log.Fatalf("Cannot apply migrations: %v", err)
+			}
+		case "check":
+			if err := migrator.Check(context.Background()); err != nil {
+				log.Fatalf("Refusing to start: %v (run tender-server migrate up)", err)
+			}
+		default:
+			log.Fatalf("Unknown -migrations mode %q", *migrateMode)
+		}
+	}
+
+	if issuer == nil && !*insecureUsername {
+		log.Fatal("Auth key is not configured: set -auth-key-file (AUTH_KEY_FILE) or -insecure-username-param for development")
+	}
+	if *insecureUsername {
+		log.Print("WARNING: -insecure-username-param is on, any client can act as any user")
+	}
+
+	middlewares := []func(http.Handler) http.Handler{
+		middleware.Logger,
+		middleware.Recoverer,
+		auth.Middleware(issuer, store, auth.Options{InsecureUsernameParam: *insecureUsername}),
+	}
+	if *specValidation != "off" {
+		validator, err := newSpecValidator(*specValidation)
+		if err != nil {
+			log.Fatalf("Cannot enable OpenAPI validation: %v", err)
+		}
+		middlewares = append(middlewares, validator.Middleware)
+	}
+	r := handlers.NewRouter(handlers.NewHandler(store), middlewares...)
 
 	serverAddr := os.Getenv("SERVER_ADDRESS")
 	if serverAddr == "" {
@@ -65,3 +139,104 @@ func main() {
 	log.Printf("Starting server on %s", serverAddr)
 	log.Fatal(http.ListenAndServe(serverAddr, r))
 }
+
+// newSpecValidator загружает встроенную спецификацию в режиме mode
+func newSpecValidator(mode string) (*openapi.Validator, error) {
+	var opts openapi.Options
+	switch mode {
+	case "requests":
+	case "responses":
+		opts.Responses = true
+	case "strict":
+		opts.Strict = true
+	default:
+		return nil, fmt.Errorf("unknown mode %q", mode)
+	}
+	return openapi.New(tenders.OpenAPISpec, opts)
+}
+
+// migrate выполняет команду миграций и печатает результат
+func migrate(migrator *migrations.Migrator, command string) {
+	ctx := context.Background()
+	var (
+		results []*goose.MigrationResult
+		err     error
+	)
+	switch command {
+	case "up":
+		results, err = migrator.Up(ctx)
+	case "down":
+		var result *goose.MigrationResult
+		if result, err = migrator.Down(ctx); result != nil {
+			results = append(results, result)
+		}
+	case "redo":
+		results, err = migrator.Redo(ctx)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Cannot read migration status: %v", err)
+		}
+		for _, st := range statuses {
+			appliedAt := "Pending"
+			if st.State == goose.StateApplied {
+				appliedAt = st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-25s %s\n", appliedAt, filepath.Base(st.Source.Path))
+		}
+		return
+	default:
+		log.Fatal("Usage: tender-server migrate up|down|status|redo")
+	}
+
+	for _, result := range results {
+		log.Print(result)
+	}
+	if err != nil {
+		log.Fatalf("Migrate %s failed: %v", command, err)
+	}
+	if len(results) == 0 {
+		log.Print("No migrations to apply")
+	}
+}
+
+// seedMemory создает в пустом хранилище администратора площадки, чтобы
+// через API можно было завести сотрудников и организации. Если настроен
+// ключ подписи, печатает токен администратора.
+func seedMemory(store *memory.Storage, issuer *auth.Issuer, username string) {
+	ctx := context.Background()
+	admin := &db.Employee{Username: username, FirstName: "Platform", LastName: "Admin"}
+	if err := store.CreateEmployee(ctx, admin); err != nil {
+		log.Fatalf("Cannot create admin %q: %v", username, err)
+	}
+	if err := store.SetPlatformRole(ctx, admin.ID, "admin"); err != nil {
+		log.Fatalf("Cannot grant admin role to %q: %v", username, err)
+	}
+	log.Printf("In-memory storage: data is lost on exit, platform admin is %q", username)
+	if issuer != nil {
+		issueToken(store, issuer, username)
+	}
+}
+
+// issueToken печатает токен доступа для сотрудника username
+func issueToken(store handlers.StorageInterface, issuer *auth.Issuer, username string) {
+	if issuer == nil {
+		log.Fatal("Auth key is not configured: set -auth-key-file (AUTH_KEY_FILE)")
+	}
+	if username == "" {
+		log.Fatal("Usage: tender-server token <username>")
+	}
+	employee, err := store.GetEmployeeByUsername(context.Background(), username)
+	if err != nil {
+		log.Fatalf("Cannot find employee %q: %v", username, err)
+	}
+	if !employee.Active() {
+		log.Fatalf("Employee %q is deactivated", username)
+	}
+	token, expiresAt, err := issuer.Issue(employee)
+	if err != nil {
+		log.Fatalf("Cannot issue token: %v", err)
+	}
+	log.Printf("Token for %s expires at %s", username, expiresAt.Format(time.RFC3339))
+	fmt.Println(token)
+}
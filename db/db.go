@@ -2,7 +2,12 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,50 +22,214 @@ func NewStorage(db *sqlx.DB) *Storage {
 	return &Storage{db: db}
 }
 
+// txKey - ключ контекста, под которым WithTx хранит открытую транзакцию
+type txKey struct{}
+
+// queryer - общие методы *sqlx.DB и *sqlx.Tx, которыми пользуется Storage
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// conn возвращает транзакцию из контекста, если она открыта, иначе пул соединений.
+// Ошибки запросов переводятся в ошибки хранилища (ErrNotFound и т.д.).
+func (s *Storage) conn(ctx context.Context) translatingConn {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return translatingConn{tx}
+	}
+	return translatingConn{s.db}
+}
+
+// WithTx выполняет fn в транзакции: все методы Storage, вызванные с переданным
+// в fn контекстом, работают в ней. Если fn вернула ошибку или упала с паникой,
+// транзакция откатывается. Вложенный вызов переиспользует внешнюю транзакцию.
+func (s *Storage) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", translateError(err))
+	}
+	return nil
+}
+
+// authorKey - ключ контекста, под которым WithAuthor хранит автора изменения
+type authorKey struct{}
+
+// WithAuthor запоминает в контексте сотрудника, от имени которого выполняются
+// изменения. SaveTenderVersion записывает его автором сохраняемой версии.
+func WithAuthor(ctx context.Context, employeeID int) context.Context {
+	return context.WithValue(ctx, authorKey{}, employeeID)
+}
+
+// AuthorFrom возвращает автора изменения из контекста или nil, если он не задан.
+// Нужен другим реализациям хранилища, которые пишут версии.
+func AuthorFrom(ctx context.Context) *int {
+	if id, ok := ctx.Value(authorKey{}).(int); ok {
+		return &id
+	}
+	return nil
+}
+
+// Таблицы, у записей которых до перехода на UUID были целые идентификаторы.
+// Прежний идентификатор хранится в колонке legacy_id (миграция 0013).
+const (
+	OrganizationTable = "organization"
+	TenderTable       = "tender"
+	BidTable          = "bid"
+)
+
+// ResolveLegacyID возвращает UUID записи таблицы table по ее прежнему целому
+// идентификатору. У записей, созданных после перехода на UUID, прежнего
+// идентификатора нет. Если запись не найдена, возвращает ErrNotFound.
+func (s *Storage) ResolveLegacyID(ctx context.Context, table string, legacyID int) (string, error) {
+	switch table {
+	case OrganizationTable, TenderTable, BidTable:
+	default:
+		return "", fmt.Errorf("table %q has no legacy ids", table)
+	}
+	var id string
+	err := s.conn(ctx).GetContext(ctx, &id, `SELECT id FROM `+table+` WHERE legacy_id=$1`, legacyID)
+	return id, err
+}
+
 // Employee (Пользователь)
 type Employee struct {
-	ID        int       `db:"id" json:"id"`
-	Username  string    `db:"username" json:"username"`
-	FirstName string    `db:"first_name" json:"firstName"`
-	LastName  string    `db:"last_name" json:"lastName"`
-	CreatedAt time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
+	ID            int        `db:"id" json:"id"`
+	Username      string     `db:"username" json:"username"`
+	FirstName     string     `db:"first_name" json:"firstName"`
+	LastName      string     `db:"last_name" json:"lastName"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updatedAt"`
+	DeactivatedAt *time.Time `db:"deactivated_at" json:"deactivatedAt,omitempty"`
+}
+
+// Active сообщает, может ли сотрудник работать в системе
+func (e *Employee) Active() bool {
+	return e.DeactivatedAt == nil
 }
 
+// employeeColumns - колонки employee; имена могут быть NULL в таблицах,
+// созданных до миграций
+const employeeColumns = `
+        id, username, COALESCE(first_name, '') AS first_name, COALESCE(last_name, '') AS last_name,
+        COALESCE(created_at, NOW()) AS created_at, COALESCE(updated_at, NOW()) AS updated_at, deactivated_at`
+
 func (s *Storage) CreateEmployee(ctx context.Context, e *Employee) error {
 	query := `
         INSERT INTO employee (username, first_name, last_name)
         VALUES ($1, $2, $3)
         RETURNING id, created_at, updated_at`
-	return s.db.QueryRowContext(ctx, query, e.Username, e.FirstName, e.LastName).
+	return s.conn(ctx).QueryRowContext(ctx, query, e.Username, e.FirstName, e.LastName).
 		Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
 }
 
 func (s *Storage) GetEmployeeByUsername(ctx context.Context, username string) (*Employee, error) {
 	e := &Employee{}
-	query := `SELECT * FROM employee WHERE username=$1`
-	err := s.db.GetContext(ctx, e, query, username)
+	query := `SELECT ` + employeeColumns + ` FROM employee WHERE username=$1`
+	err := s.conn(ctx).GetContext(ctx, e, query, username)
 	return e, err
 }
 
+// ListEmployees возвращает страницу сотрудников, отсортированных по username,
+// с подстрокой search в username, имени или фамилии, и общее число таких
+// сотрудников. Деактивированные выбираются, только если includeInactive.
+func (s *Storage) ListEmployees(ctx context.Context, search string, includeInactive bool, page Page) ([]Employee, int, error) {
+	const where = `
+        WHERE ($1 = '' OR username ILIKE '%' || $1 || '%'
+                       OR first_name ILIKE '%' || $1 || '%'
+                       OR last_name ILIKE '%' || $1 || '%')
+          AND ($2 OR deactivated_at IS NULL)`
+	args := []interface{}{search, includeInactive}
+
+	var total int
+	if err := s.conn(ctx).GetContext(ctx, &total, "SELECT COUNT(*) FROM employee"+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query, args, reversed := employeeOrder.paginate("SELECT "+employeeColumns+" FROM employee"+where, args, page)
+	employees := []Employee{}
+	if err := s.conn(ctx).SelectContext(ctx, &employees, query, args...); err != nil {
+		return nil, 0, err
+	}
+	if reversed {
+		slices.Reverse(employees)
+	}
+	return employees, total, nil
+}
+
 func (s *Storage) UpdateEmployee(ctx context.Context, e *Employee) error {
 	query := `
         UPDATE employee
         SET first_name = $1, last_name = $2, updated_at = NOW()
-        WHERE username = $3`
-	_, err := s.db.ExecContext(ctx, query, e.FirstName, e.LastName, e.Username)
-	return err
+        WHERE username = $3
+        RETURNING updated_at`
+	return s.conn(ctx).QueryRowContext(ctx, query, e.FirstName, e.LastName, e.Username).Scan(&e.UpdatedAt)
 }
 
+// DeactivateEmployee запрещает сотруднику работать в системе, не удаляя его
+// из истории. Повторная деактивация ничего не меняет.
+func (s *Storage) DeactivateEmployee(ctx context.Context, username string) error {
+	query := `
+        UPDATE employee
+        SET deactivated_at = COALESCE(deactivated_at, NOW()), updated_at = NOW()
+        WHERE username = $1`
+	res, err := s.conn(ctx).ExecContext(ctx, query, username)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteEmployee удаляет сотрудника. Сотрудника с голосами или историей
+// голосов удалить нельзя (ErrForeignKey): его деактивируют через
+// DeactivateEmployee.
 func (s *Storage) DeleteEmployee(ctx context.Context, username string) error {
 	query := `DELETE FROM employee WHERE username = $1`
-	_, err := s.db.ExecContext(ctx, query, username)
-	return err
+	res, err := s.conn(ctx).ExecContext(ctx, query, username)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
 
 // Organization (Организация)
 type Organization struct {
-	ID          int       `db:"id" json:"id"`
+	ID          string    `db:"id" json:"id"`
 	Name        string    `db:"name" json:"name"`
 	Description string    `db:"description" json:"description"`
 	Type        string    `db:"type" json:"type"`
@@ -68,169 +237,467 @@ type Organization struct {
 	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
 }
 
+// Типы организаций (enum organization_type)
+const (
+	OrganizationIE  = "IE"
+	OrganizationLLC = "LLC"
+	OrganizationJSC = "JSC"
+)
+
+// organizationColumns - колонки organization; description и type могут быть NULL
+// в таблицах, созданных до миграций
+const organizationColumns = `
+        id, name, COALESCE(description, '') AS description, COALESCE(type::text, '') AS type,
+        COALESCE(created_at, NOW()) AS created_at, COALESCE(updated_at, NOW()) AS updated_at`
+
 func (s *Storage) CreateOrganization(ctx context.Context, o *Organization) error {
 	query := `
         INSERT INTO organization (name, description, type)
         VALUES ($1, $2, $3)
         RETURNING id, created_at, updated_at`
-	return s.db.QueryRowContext(ctx, query, o.Name, o.Description, o.Type).
+	return s.conn(ctx).QueryRowContext(ctx, query, o.Name, o.Description, o.Type).
 		Scan(&o.ID, &o.CreatedAt, &o.UpdatedAt)
 }
 
-func (s *Storage) GetOrganization(ctx context.Context, id int) (*Organization, error) {
+func (s *Storage) GetOrganization(ctx context.Context, id string) (*Organization, error) {
 	o := &Organization{}
-	query := `SELECT * FROM organization WHERE id=$1`
-	err := s.db.GetContext(ctx, o, query, id)
+	query := `SELECT ` + organizationColumns + ` FROM organization WHERE id=$1`
+	err := s.conn(ctx).GetContext(ctx, o, query, id)
 	return o, err
 }
 
+// LockOrganization читает организацию и блокирует строку до конца транзакции.
+// Используется, чтобы параллельные изменения состава ответственных шли по очереди.
+func (s *Storage) LockOrganization(ctx context.Context, id string) (*Organization, error) {
+	o := &Organization{}
+	query := `SELECT ` + organizationColumns + ` FROM organization WHERE id=$1 FOR UPDATE`
+	err := s.conn(ctx).GetContext(ctx, o, query, id)
+	return o, err
+}
+
+// ListOrganizations возвращает страницу организаций, отсортированных по
+// названию, с подстрокой search в названии, и общее число таких организаций
+func (s *Storage) ListOrganizations(ctx context.Context, search string, page Page) ([]Organization, int, error) {
+	const where = ` WHERE ($1 = '' OR name ILIKE '%' || $1 || '%')`
+	args := []interface{}{search}
+
+	var total int
+	if err := s.conn(ctx).GetContext(ctx, &total, "SELECT COUNT(*) FROM organization"+where, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query, args, reversed := organizationOrder.paginate("SELECT "+organizationColumns+" FROM organization"+where, args, page)
+	organizations := []Organization{}
+	if err := s.conn(ctx).SelectContext(ctx, &organizations, query, args...); err != nil {
+		return nil, 0, err
+	}
+	if reversed {
+		slices.Reverse(organizations)
+	}
+	return organizations, total, nil
+}
+
 func (s *Storage) UpdateOrganization(ctx context.Context, o *Organization) error {
 	query := `
         UPDATE organization
         SET name=$1, description=$2, type=$3, updated_at=NOW()
-        WHERE id=$4`
-	_, err := s.db.ExecContext(ctx, query, o.Name, o.Description, o.Type, o.ID)
-	return err
+        WHERE id=$4
+        RETURNING updated_at`
+	return s.conn(ctx).QueryRowContext(ctx, query, o.Name, o.Description, o.Type, o.ID).Scan(&o.UpdatedAt)
 }
 
-func (s *Storage) DeleteOrganization(ctx context.Context, id int) error {
+// DeleteOrganization удаляет организацию вместе с ответственными и политиками.
+// Организацию с тендерами или предложениями (в том числе из архива) удалить
+// нельзя: ErrForeignKey.
+func (s *Storage) DeleteOrganization(ctx context.Context, id string) error {
 	query := `DELETE FROM organization WHERE id=$1`
-	_, err := s.db.ExecContext(ctx, query, id)
+	res, err := s.conn(ctx).ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddResponsible назначает сотрудника ответственным за организацию.
+// Повторное назначение меняет только роль.
+func (s *Storage) AddResponsible(ctx context.Context, orgID string, userID int, role string) error {
+	query := `
+        INSERT INTO organization_responsible (organization_id, user_id, role)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (organization_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+	_, err := s.conn(ctx).ExecContext(ctx, query, orgID, userID, role)
 	return err
 }
 
-func (s *Storage) IsUserResponsibleForOrganization(ctx context.Context, userID int, orgID int) (bool, error) {
+// RemoveResponsible снимает сотрудника с ответственности. ErrNotFound, если он не был назначен.
+func (s *Storage) RemoveResponsible(ctx context.Context, orgID string, userID int) error {
+	query := `DELETE FROM organization_responsible WHERE organization_id=$1 AND user_id=$2`
+	res, err := s.conn(ctx).ExecContext(ctx, query, orgID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *Storage) IsUserResponsibleForOrganization(ctx context.Context, userID int, orgID string) (bool, error) {
 	var count int
 	query := `SELECT COUNT(1) FROM organization_responsible WHERE user_id=$1 AND organization_id=$2`
-	err := s.db.GetContext(ctx, &count, query, userID, orgID)
+	err := s.conn(ctx).GetContext(ctx, &count, query, userID, orgID)
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
+// IsUserMemberOfOrganization проверяет, что сотрудник состоит в организации.
+// Ответственные считаются сотрудниками организации.
+func (s *Storage) IsUserMemberOfOrganization(ctx context.Context, userID int, orgID string) (bool, error) {
+	var member bool
+	query := `
+        SELECT EXISTS (SELECT 1 FROM organization_member WHERE user_id=$1 AND organization_id=$2)
+            OR EXISTS (SELECT 1 FROM organization_responsible WHERE user_id=$1 AND organization_id=$2)`
+	err := s.conn(ctx).GetContext(ctx, &member, query, userID, orgID)
+	return member, err
+}
+
+// GetPlatformRole возвращает роль сотрудника на площадке ("admin", "auditor")
+// или пустую строку, если роли нет.
+func (s *Storage) GetPlatformRole(ctx context.Context, userID int) (string, error) {
+	var role string
+	query := `SELECT role FROM employee_platform_role WHERE user_id=$1`
+	err := s.conn(ctx).GetContext(ctx, &role, query, userID)
+	if errors.Is(err, ErrNotFound) {
+		return "", nil
+	}
+	return role, err
+}
+
+// Responsible - сотрудник, ответственный за организацию, и его роль в ней
+type Responsible struct {
+	UserID         int    `db:"user_id" json:"userId"`
+	Username       string `db:"username" json:"username"`
+	OrganizationID string `db:"organization_id" json:"organizationId"`
+	Role           string `db:"role" json:"role"`
+}
+
+func (s *Storage) GetResponsibles(ctx context.Context, orgID string) ([]Responsible, error) {
+	responsibles := []Responsible{}
+	query := `
+        SELECT orr.user_id, COALESCE(e.username, '') AS username, orr.organization_id, orr.role
+        FROM organization_responsible orr
+        LEFT JOIN employee e ON e.id = orr.user_id
+        WHERE orr.organization_id=$1
+        ORDER BY orr.user_id`
+	err := s.conn(ctx).SelectContext(ctx, &responsibles, query, orgID)
+	return responsibles, err
+}
+
+// ApprovalPolicy (Политика принятия решений по предложениям).
+// Политика с TenderID == nil действует для всех тендеров организации.
+type ApprovalPolicy struct {
+	ID              int         `db:"id" json:"id"`
+	OrganizationID  string      `db:"organization_id" json:"organizationId"`
+	TenderID        *string     `db:"tender_id" json:"tenderId"`
+	Kind            string      `db:"kind" json:"kind"`
+	Required        int         `db:"required" json:"required"`
+	RejectThreshold int         `db:"reject_threshold" json:"rejectThreshold"`
+	RoleWeights     RoleWeights `db:"role_weights" json:"roleWeights"`
+	CreatedAt       time.Time   `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time   `db:"updated_at" json:"updatedAt"`
+}
+
+// RoleWeights - веса голосов по ролям, хранятся в JSONB
+type RoleWeights map[string]int
+
+func (rw RoleWeights) Value() (driver.Value, error) {
+	if rw == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(rw)
+}
+
+func (rw *RoleWeights) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*rw = RoleWeights{}
+		return nil
+	default:
+		return fmt.Errorf("unsupported role_weights type %T", src)
+	}
+	return json.Unmarshal(data, rw)
+}
+
+// GetApprovalPolicy возвращает политику тендера, а если ее нет - политику организации.
+// Если tenderID == nil, ищется только политика организации. Без политики - ErrNotFound.
+func (s *Storage) GetApprovalPolicy(ctx context.Context, orgID string, tenderID *string) (*ApprovalPolicy, error) {
+	p := &ApprovalPolicy{}
+	query := `
+        SELECT * FROM approval_policy
+        WHERE organization_id = $1 AND (tender_id IS NULL OR tender_id = $2)
+        ORDER BY tender_id NULLS LAST
+        LIMIT 1`
+	err := s.conn(ctx).GetContext(ctx, p, query, orgID, tenderID)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SaveApprovalPolicy создает или заменяет политику организации (или тендера, если задан TenderID)
+func (s *Storage) SaveApprovalPolicy(ctx context.Context, p *ApprovalPolicy) error {
+	conflict := `(organization_id) WHERE tender_id IS NULL`
+	if p.TenderID != nil {
+		conflict = `(tender_id) WHERE tender_id IS NOT NULL`
+	}
+	query := `
+        INSERT INTO approval_policy
+            (organization_id, tender_id, kind, required, reject_threshold, role_weights)
+        VALUES
+            ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT ` + conflict + ` DO UPDATE SET
+            kind = EXCLUDED.kind,
+            required = EXCLUDED.required,
+            reject_threshold = EXCLUDED.reject_threshold,
+            role_weights = EXCLUDED.role_weights,
+            updated_at = NOW()
+        RETURNING id, created_at, updated_at`
+	return s.conn(ctx).QueryRowContext(ctx, query,
+		p.OrganizationID, p.TenderID, p.Kind, p.Required, p.RejectThreshold, p.RoleWeights).
+		Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+}
+
+// DeleteApprovalPolicy удаляет политику организации (tenderID == nil) или тендера
+func (s *Storage) DeleteApprovalPolicy(ctx context.Context, orgID string, tenderID *string) error {
+	query := `DELETE FROM approval_policy WHERE organization_id=$1 AND tender_id IS NULL`
+	args := []interface{}{orgID}
+	if tenderID != nil {
+		query = `DELETE FROM approval_policy WHERE organization_id=$1 AND tender_id=$2`
+		args = append(args, *tenderID)
+	}
+	_, err := s.conn(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
 // Tender (Тендер)
 type Tender struct {
-	ID             int       `db:"id" json:"id"`
-	Name           string    `db:"name" json:"name"`
-	Description    string    `db:"description" json:"description"`
-	ServiceType    string    `db:"service_type" json:"serviceType"`
-	Status         string    `db:"status" json:"status"`
-	OrganizationID int       `db:"organization_id" json:"organizationId"`
-	Version        int       `db:"version" json:"version"`
-	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
+	ID             string     `db:"id" json:"id"`
+	Name           string     `db:"name" json:"name"`
+	Description    string     `db:"description" json:"description"`
+	ServiceType    string     `db:"service_type" json:"serviceType"`
+	Status         string     `db:"status" json:"status"`
+	OrganizationID string     `db:"organization_id" json:"organizationId"`
+	Version        int        `db:"version" json:"version"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"-"`
+	DeletedAt      *time.Time `db:"deleted_at" json:"deletedAt,omitempty"`
+	DeletedBy      *int       `db:"deleted_by" json:"deletedBy,omitempty"`
 }
 
+// tenderColumns - колонки tender в порядке полей Tender
+const tenderColumns = `id, name, description, service_type, status, organization_id, version, created_at, updated_at, deleted_at, deleted_by`
+
 func (s *Storage) CreateTender(ctx context.Context, t *Tender) error {
 	query := `
         INSERT INTO tender
             (name, description, service_type, status, organization_id, version)
         VALUES
             ($1, $2, $3, $4, $5, 1)
-        RETURNING id, created_at`
-	err := s.db.QueryRowContext(ctx, query,
-		t.Name, t.Description, t.ServiceType, t.Status, t.OrganizationID).
-		Scan(&t.ID, &t.CreatedAt)
-	if err != nil {
-		return err
-	}
-	// Сохраняем первую версию
-	return s.SaveTenderVersion(ctx, t)
+        RETURNING id, version, created_at`
+	return s.WithTx(ctx, func(ctx context.Context) error {
+		err := s.conn(ctx).QueryRowContext(ctx, query,
+			t.Name, t.Description, t.ServiceType, t.Status, t.OrganizationID).
+			Scan(&t.ID, &t.Version, &t.CreatedAt)
+		if err != nil {
+			return err
+		}
+		// Сохраняем первую версию
+		return s.SaveTenderVersion(ctx, t)
+	})
+}
+
+func (s *Storage) GetTender(ctx context.Context, id string) (*Tender, error) {
+	t := &Tender{}
+	query := `SELECT ` + tenderColumns + ` FROM tender WHERE id=$1`
+	err := s.conn(ctx).GetContext(ctx, t, query, id)
+	return t, err
 }
 
-func (s *Storage) GetTender(ctx context.Context, id int) (*Tender, error) {
+// LockTender читает тендер и блокирует строку до конца транзакции (SELECT ... FOR UPDATE).
+// Имеет смысл только внутри WithTx.
+func (s *Storage) LockTender(ctx context.Context, id string) (*Tender, error) {
 	t := &Tender{}
-	query := `SELECT * FROM tender WHERE id=$1`
-	err := s.db.GetContext(ctx, t, query, id)
+	query := `
+        SELECT ` + tenderColumns + `
+        FROM tender WHERE id=$1
+        FOR UPDATE`
+	err := s.conn(ctx).GetContext(ctx, t, query, id)
 	return t, err
 }
 
+// UpdateTender сохраняет тендер, если его версия в БД все еще равна t.Version,
+// и увеличивает версию. Иначе возвращает ErrVersionConflict.
 func (s *Storage) UpdateTender(ctx context.Context, t *Tender) error {
-	t.Version++
 	query := `
         UPDATE tender
-        SET name=$1, description=$2, service_type=$3, status=$4, version=$5
-        WHERE id=$6`
-	_, err := s.db.ExecContext(ctx, query,
-		t.Name, t.Description, t.ServiceType, t.Status, t.Version, t.ID)
-	if err != nil {
-		return err
-	}
-	// Сохраняем новую версию
-	return s.SaveTenderVersion(ctx, t)
+        SET name=$1, description=$2, service_type=$3, status=$4, version=version+1
+        WHERE id=$5 AND version=$6`
+	return s.WithTx(ctx, func(ctx context.Context) error {
+		res, err := s.conn(ctx).ExecContext(ctx, query,
+			t.Name, t.Description, t.ServiceType, t.Status, t.ID, t.Version)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrVersionConflict
+		}
+		t.Version++
+		// Сохраняем новую версию
+		return s.SaveTenderVersion(ctx, t)
+	})
 }
 
-func (s *Storage) DeleteTender(ctx context.Context, id int) error {
-	query := `DELETE FROM tender WHERE id=$1`
-	_, err := s.db.ExecContext(ctx, query, id)
-	return err
+// ArchiveTender помечает тендер удаленным: он пропадает из списков, но остается
+// в БД вместе с версиями и предложениями до PurgeArchived. Кто удалил тендер,
+// берется из контекста (WithAuthor). ErrNotFound, если тендера нет или он уже в архиве.
+func (s *Storage) ArchiveTender(ctx context.Context, t *Tender) error {
+	query := `
+        UPDATE tender SET deleted_at=NOW(), deleted_by=$2
+        WHERE id=$1 AND deleted_at IS NULL
+        RETURNING deleted_at, deleted_by`
+	return s.conn(ctx).QueryRowContext(ctx, query, t.ID, AuthorFrom(ctx)).Scan(&t.DeletedAt, &t.DeletedBy)
 }
 
-func (s *Storage) GetTenders(ctx context.Context, serviceTypes []string, limit, offset int) ([]Tender, error) {
-	baseQuery := "SELECT id, name, description, service_type, status, organization_id, version, created_at FROM tender"
-	var args []interface{}
-	filter := ""
+// RestoreTender возвращает тендер из архива. ErrNotFound, если тендера в архиве нет.
+func (s *Storage) RestoreTender(ctx context.Context, t *Tender) error {
+	query := `
+        UPDATE tender SET deleted_at=NULL, deleted_by=NULL
+        WHERE id=$1 AND deleted_at IS NOT NULL
+        RETURNING deleted_at, deleted_by`
+	return s.conn(ctx).QueryRowContext(ctx, query, t.ID).Scan(&t.DeletedAt, &t.DeletedBy)
+}
 
+// GetTenders возвращает страницу тендеров, отсортированных по названию, с
+// фильтром по видам услуг, и общее число тендеров под фильтром. Тендеры в
+// архиве не выбираются.
+func (s *Storage) GetTenders(ctx context.Context, serviceTypes []string, page Page) ([]Tender, int, error) {
+	where := " WHERE deleted_at IS NULL"
+	var args []interface{}
 	if len(serviceTypes) > 0 {
 		placeholders := make([]string, len(serviceTypes))
-		for i := range serviceTypes {
+		for i, v := range serviceTypes {
 			placeholders[i] = fmt.Sprintf("$%d", i+1)
-		}
-		filter = fmt.Sprintf(" WHERE service_type IN (%s)", strings.Join(placeholders, ", "))
-		for _, v := range serviceTypes {
 			args = append(args, v)
 		}
+		where += fmt.Sprintf(" AND service_type IN (%s)", strings.Join(placeholders, ", "))
 	}
 
-	query := baseQuery + filter + " ORDER BY name ASC"
-	query += fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+	var total int
+	if err := s.conn(ctx).GetContext(ctx, &total, "SELECT COUNT(*) FROM tender"+where, args...); err != nil {
+		return nil, 0, err
+	}
 
+	query, args, reversed := tenderOrder.paginate("SELECT "+tenderColumns+" FROM tender"+where, args, page)
 	tenders := []Tender{}
-	err := s.db.SelectContext(ctx, &tenders, query, args...)
-	if err != nil {
-		return nil, err
+	if err := s.conn(ctx).SelectContext(ctx, &tenders, query, args...); err != nil {
+		return nil, 0, err
+	}
+	if reversed {
+		slices.Reverse(tenders)
 	}
-	return tenders, nil
+	return tenders, total, nil
 }
 
-func (s *Storage) GetUserTenders(ctx context.Context, username string, limit, offset int) ([]Tender, error) {
-	query := `
-        SELECT t.id, t.name, t.description, t.service_type, t.status, t.organization_id, t.version, t.created_at
+// GetUserTenders возвращает страницу тендеров организаций, за которые отвечает
+// пользователь, отсортированных по названию, и общее число таких тендеров
+func (s *Storage) GetUserTenders(ctx context.Context, username string, page Page) ([]Tender, int, error) {
+	const from = `
         FROM tender t
         JOIN organization_responsible orr ON t.organization_id = orr.organization_id
         JOIN employee e ON orr.user_id = e.id
-        WHERE e.username = $1
-        ORDER BY t.name ASC
-        LIMIT $2 OFFSET $3
-    `
+        WHERE e.username = $1 AND t.deleted_at IS NULL`
+	args := []interface{}{username}
+
+	var total int
+	if err := s.conn(ctx).GetContext(ctx, &total, "SELECT COUNT(*)"+from, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query, args, reversed := tenderOrder.on("t").paginate(`
+        SELECT t.id, t.name, t.description, t.service_type, t.status, t.organization_id, t.version, t.created_at, t.updated_at, t.deleted_at, t.deleted_by`+from,
+		args, page)
 	tenders := []Tender{}
-	err := s.db.SelectContext(ctx, &tenders, query, username, limit, offset)
-	if err != nil {
-		return nil, err
+	if err := s.conn(ctx).SelectContext(ctx, &tenders, query, args...); err != nil {
+		return nil, 0, err
+	}
+	if reversed {
+		slices.Reverse(tenders)
 	}
-	return tenders, nil
+	return tenders, total, nil
 }
 
+// SaveTenderVersion сохраняет снимок тендера. Автор версии берется из контекста (WithAuthor).
 func (s *Storage) SaveTenderVersion(ctx context.Context, t *Tender) error {
 	query := `
         INSERT INTO tender_versions
-            (tender_id, name, description, service_type, status, organization_id, version, created_at)
+            (tender_id, name, description, service_type, status, organization_id, version, author_id, created_at)
         VALUES
-            ($1, $2, $3, $4, $5, $6, $7, NOW())
+            ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
     `
-	_, err := s.db.ExecContext(ctx, query,
-		t.ID, t.Name, t.Description, t.ServiceType, t.Status, t.OrganizationID, t.Version)
+	_, err := s.conn(ctx).ExecContext(ctx, query,
+		t.ID, t.Name, t.Description, t.ServiceType, t.Status, t.OrganizationID, t.Version, AuthorFrom(ctx))
 	return err
 }
 
-func (s *Storage) GetTenderVersion(ctx context.Context, tenderID, version int) (*Tender, error) {
+// Version - запись истории версий тендера или предложения
+type Version struct {
+	// ID - строка истории, нужна только для курсора страницы
+	ID             int       `db:"id" json:"-"`
+	Version        int       `db:"version" json:"version"`
+	Status         string    `db:"status" json:"status"`
+	AuthorID       *int      `db:"author_id" json:"authorId"`
+	AuthorUsername *string   `db:"author_username" json:"authorUsername"`
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
+}
+
+// GetTenderVersions возвращает страницу истории версий тендера, начиная с
+// последней, и общее число записей истории
+func (s *Storage) GetTenderVersions(ctx context.Context, tenderID string, page Page) ([]Version, int, error) {
+	return s.selectVersions(ctx, "tender_versions", "tender_id", tenderID, page)
+}
+
+func (s *Storage) GetTenderVersion(ctx context.Context, tenderID string, version int) (*Tender, error) {
 	var t Tender
 	query := `
         SELECT tender_id AS id, name, description, service_type, status, organization_id, version, created_at
         FROM tender_versions
         WHERE tender_id = $1 AND version = $2
+        ORDER BY id DESC
+        LIMIT 1
     `
-	err := s.db.GetContext(ctx, &t, query, tenderID, version)
+	err := s.conn(ctx).GetContext(ctx, &t, query, tenderID, version)
 	if err != nil {
 		return nil, err
 	}
@@ -240,59 +707,112 @@ func (s *Storage) GetTenderVersion(ctx context.Context, tenderID, version int) (
 // Bid (Предложение)
 
 type Bid struct {
-	ID              int       `db:"id" json:"id"`
-	Name            string    `db:"name" json:"name" validate:"required,max=100"`
-	Description     string    `db:"description" json:"description" validate:"required,max=500"`
-	Status          string    `db:"status" json:"status" validate:"required,oneof=Created Published Canceled Approved Rejected"`
-	TenderID        int       `db:"tender_id" json:"tenderId" validate:"required"`
-	OrganizationID  int       `db:"organization_id" json:"organizationId"`   // используйте это поле вместо AuthorID
-	CreatorUsername string    `db:"creator_username" json:"creatorUsername"` // вместо AuthorType
-	Version         int       `db:"version" json:"version"`
-	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt       time.Time `db:"updated_at" json:"-"`
+	ID              string     `db:"id" json:"id"`
+	Name            string     `db:"name" json:"name" validate:"required,max=100"`
+	Description     string     `db:"description" json:"description" validate:"required,max=500"`
+	Status          string     `db:"status" json:"status" validate:"required,oneof=Created Published Canceled Approved Rejected"`
+	TenderID        string     `db:"tender_id" json:"tenderId" validate:"required"`
+	OrganizationID  string     `db:"organization_id" json:"organizationId"`   // используйте это поле вместо AuthorID
+	CreatorUsername string     `db:"creator_username" json:"creatorUsername"` // вместо AuthorType
+	Version         int        `db:"version" json:"version"`
+	CreatedAt       time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"-"`
+	DeletedAt       *time.Time `db:"deleted_at" json:"deletedAt,omitempty"`
+	DeletedBy       *int       `db:"deleted_by" json:"deletedBy,omitempty"`
 }
 
+// bidColumns - колонки bid в порядке полей Bid
+const bidColumns = `id, name, description, status, tender_id, organization_id, creator_username, version, created_at, updated_at, deleted_at, deleted_by`
+
 func (s *Storage) CreateBid(ctx context.Context, b *Bid) error {
 	query := `
         INSERT INTO bid
             (name, description, status, tender_id, organization_id, creator_username, version)
         VALUES
             ($1, $2, $3, $4, $5, $6, 1)
-        RETURNING id, created_at`
-	return s.db.QueryRowContext(ctx, query,
-		b.Name, b.Description, b.Status, b.TenderID, b.OrganizationID, b.CreatorUsername).
-		Scan(&b.ID, &b.CreatedAt)
+        RETURNING id, version, created_at`
+	return s.WithTx(ctx, func(ctx context.Context) error {
+		err := s.conn(ctx).QueryRowContext(ctx, query,
+			b.Name, b.Description, b.Status, b.TenderID, b.OrganizationID, b.CreatorUsername).
+			Scan(&b.ID, &b.Version, &b.CreatedAt)
+		if err != nil {
+			return err
+		}
+		// Сохраняем первую версию
+		return s.SaveBidVersion(ctx, b)
+	})
+}
+
+func (s *Storage) GetBid(ctx context.Context, id string) (*Bid, error) {
+	b := &Bid{}
+	query := `SELECT ` + bidColumns + ` FROM bid WHERE id=$1`
+	err := s.conn(ctx).GetContext(ctx, b, query, id)
+	return b, err
 }
 
-func (s *Storage) GetBid(ctx context.Context, id int) (*Bid, error) {
+// LockBid читает предложение и блокирует строку до конца транзакции (SELECT ... FOR UPDATE).
+// Имеет смысл только внутри WithTx.
+func (s *Storage) LockBid(ctx context.Context, id string) (*Bid, error) {
 	b := &Bid{}
-	query := `SELECT * FROM bid WHERE id=$1`
-	err := s.db.GetContext(ctx, b, query, id)
+	query := `SELECT ` + bidColumns + ` FROM bid WHERE id=$1 FOR UPDATE`
+	err := s.conn(ctx).GetContext(ctx, b, query, id)
 	return b, err
 }
 
+// UpdateBid сохраняет предложение, если его версия в БД все еще равна b.Version,
+// увеличивает версию и записывает снимок в bid_versions. Иначе возвращает ErrVersionConflict.
 func (s *Storage) UpdateBid(ctx context.Context, b *Bid) error {
-	b.Version++
 	query := `
         UPDATE bid
-        SET name=$1, description=$2, status=$3, version=$4
-        WHERE id=$5`
-	_, err := s.db.ExecContext(ctx, query, b.Name, b.Description, b.Status, b.Version, b.ID)
-	return err
+        SET name=$1, description=$2, status=$3, version=version+1, updated_at=NOW()
+        WHERE id=$4 AND version=$5`
+	return s.WithTx(ctx, func(ctx context.Context) error {
+		res, err := s.conn(ctx).ExecContext(ctx, query, b.Name, b.Description, b.Status, b.ID, b.Version)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrVersionConflict
+		}
+		b.Version++
+		// Сохраняем новую версию
+		return s.SaveBidVersion(ctx, b)
+	})
 }
 
-func (s *Storage) DeleteBid(ctx context.Context, id int) error {
-	query := `DELETE FROM bid WHERE id=$1`
-	_, err := s.db.ExecContext(ctx, query, id)
-	return err
+// ArchiveBid помечает предложение удаленным: оно пропадает из списков, но
+// остается в БД вместе с версиями, голосами и отзывами до PurgeArchived. Кто
+// удалил предложение, берется из контекста (WithAuthor). ErrNotFound, если
+// предложения нет или оно уже в архиве.
+func (s *Storage) ArchiveBid(ctx context.Context, b *Bid) error {
+	query := `
+        UPDATE bid SET deleted_at=NOW(), deleted_by=$2
+        WHERE id=$1 AND deleted_at IS NULL
+        RETURNING deleted_at, deleted_by`
+	return s.conn(ctx).QueryRowContext(ctx, query, b.ID, AuthorFrom(ctx)).Scan(&b.DeletedAt, &b.DeletedBy)
+}
+
+// RestoreBid возвращает предложение из архива. ErrNotFound, если предложения в архиве нет.
+func (s *Storage) RestoreBid(ctx context.Context, b *Bid) error {
+	query := `
+        UPDATE bid SET deleted_at=NULL, deleted_by=NULL
+        WHERE id=$1 AND deleted_at IS NOT NULL
+        RETURNING deleted_at, deleted_by`
+	return s.conn(ctx).QueryRowContext(ctx, query, b.ID).Scan(&b.DeletedAt, &b.DeletedBy)
 }
 
 // BidReview (Отзыв)
 type BidReview struct {
-	ID          int       `db:"id" json:"id"`
-	BidID       int       `db:"bid_id" json:"bidId"`
-	Description string    `db:"description" json:"description"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	ID          int        `db:"id" json:"id"`
+	BidID       string     `db:"bid_id" json:"bidId"`
+	Description string     `db:"description" json:"description"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+	DeletedAt   *time.Time `db:"deleted_at" json:"-"`
+	DeletedBy   *int       `db:"deleted_by" json:"-"`
 }
 
 func (s *Storage) CreateBidReview(ctx context.Context, r *BidReview) error {
@@ -300,95 +820,294 @@ func (s *Storage) CreateBidReview(ctx context.Context, r *BidReview) error {
         INSERT INTO bid_review (bid_id, description)
         VALUES ($1, $2)
         RETURNING id, created_at`
-	return s.db.QueryRowContext(ctx, query, r.BidID, r.Description).Scan(&r.ID, &r.CreatedAt)
+	return s.conn(ctx).QueryRowContext(ctx, query, r.BidID, r.Description).Scan(&r.ID, &r.CreatedAt)
 }
 
-func (s *Storage) GetBidReviewsByBidID(ctx context.Context, bidID int) ([]BidReview, error) {
+func (s *Storage) GetBidReviewsByBidID(ctx context.Context, bidID string) ([]BidReview, error) {
 	var reviews []BidReview
-	query := `SELECT * FROM bid_review WHERE bid_id=$1`
-	err := s.db.SelectContext(ctx, &reviews, query, bidID)
+	query := `SELECT * FROM bid_review WHERE bid_id=$1 AND deleted_at IS NULL`
+	err := s.conn(ctx).SelectContext(ctx, &reviews, query, bidID)
 	return reviews, err
 }
 
-func (s *Storage) DeleteBidReview(ctx context.Context, id int) error {
-	query := `DELETE FROM bid_review WHERE id=$1`
-	_, err := s.db.ExecContext(ctx, query, id)
-	return err
+// ArchiveBidReview помечает отзыв удаленным. Кто удалил отзыв, берется из
+// контекста (WithAuthor). ErrNotFound, если отзыва нет или он уже в архиве.
+func (s *Storage) ArchiveBidReview(ctx context.Context, id int) error {
+	query := `UPDATE bid_review SET deleted_at=NOW(), deleted_by=$2 WHERE id=$1 AND deleted_at IS NULL`
+	res, err := s.conn(ctx).ExecContext(ctx, query, id, AuthorFrom(ctx))
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
-func (s *Storage) GetUserBids(ctx context.Context, username string, limit, offset int) ([]Bid, error) {
-	query := `
-        SELECT * FROM bid
-        WHERE creator_username = $1
-        ORDER BY created_at DESC
-        LIMIT $2 OFFSET $3`
-	bids := []Bid{}
-	err := s.db.SelectContext(ctx, &bids, query, username, limit, offset)
-	return bids, err
+
+// GetUserBids возвращает страницу предложений пользователя, от новых к старым,
+// и общее число его предложений
+func (s *Storage) GetUserBids(ctx context.Context, username string, page Page) ([]Bid, int, error) {
+	return s.selectBids(ctx, `FROM bid b WHERE b.creator_username = $1 AND b.deleted_at IS NULL`, []interface{}{username}, page)
 }
 
-func (s *Storage) GetBidsForTender(ctx context.Context, tenderID int, username string, limit, offset int) ([]Bid, error) {
-	query := `
-        SELECT b.* FROM bid b
+// GetBidsForTender возвращает страницу предложений по тендеру, от новых к
+// старым, и общее число таких предложений
+func (s *Storage) GetBidsForTender(ctx context.Context, tenderID string, username string, page Page) ([]Bid, int, error) {
+	from := `
+        FROM bid b
         JOIN employee e ON b.creator_username = e.username
-        WHERE b.tender_id = $1
-        AND (e.username = $2 OR (SELECT COUNT(1) FROM organization_responsible WHERE organization_id = b.organization_id AND user_id = e.id) > 0)
-        ORDER BY b.created_at DESC
-        LIMIT $3 OFFSET $4
-    `
+        WHERE b.tender_id = $1 AND b.deleted_at IS NULL
+        AND (e.username = $2 OR (SELECT COUNT(1) FROM organization_responsible WHERE organization_id = b.organization_id AND user_id = e.id) > 0)`
+	return s.selectBids(ctx, from, []interface{}{tenderID, username}, page)
+}
+
+// selectBids выбирает страницу предложений b из from (FROM ... WHERE ...) и
+// считает все предложения, подходящие под условие
+func (s *Storage) selectBids(ctx context.Context, from string, args []interface{}, page Page) ([]Bid, int, error) {
+	var total int
+	if err := s.conn(ctx).GetContext(ctx, &total, "SELECT COUNT(*) "+from, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query, args, reversed := bidOrder.on("b").paginate(`
+        SELECT b.id, b.name, b.description, b.status, b.tender_id, b.organization_id, b.creator_username, b.version, b.created_at, b.updated_at, b.deleted_at, b.deleted_by `+from,
+		args, page)
 	bids := []Bid{}
-	err := s.db.SelectContext(ctx, &bids, query, tenderID, username, limit, offset)
-	return bids, err
+	if err := s.conn(ctx).SelectContext(ctx, &bids, query, args...); err != nil {
+		return nil, 0, err
+	}
+	if reversed {
+		slices.Reverse(bids)
+	}
+	return bids, total, nil
 }
 
-func (s *Storage) GetBidVersion(ctx context.Context, bidID, version int) (*Bid, error) {
+func (s *Storage) GetBidVersion(ctx context.Context, bidID string, version int) (*Bid, error) {
 	var b Bid
 	query := `
         SELECT bid_id AS id, name, description, status, tender_id, organization_id, creator_username, version, created_at
         FROM bid_versions
         WHERE bid_id = $1 AND version = $2
+        ORDER BY id DESC
+        LIMIT 1
     `
-	err := s.db.GetContext(ctx, &b, query, bidID, version)
+	err := s.conn(ctx).GetContext(ctx, &b, query, bidID, version)
 	if err != nil {
 		return nil, err
 	}
 	return &b, nil
 }
 
+// SaveBidVersion сохраняет снимок предложения. Автор версии берется из контекста (WithAuthor).
 func (s *Storage) SaveBidVersion(ctx context.Context, b *Bid) error {
 	query := `
         INSERT INTO bid_versions
-            (bid_id, name, description, status, tender_id, organization_id, creator_username, version, created_at)
+            (bid_id, name, description, status, tender_id, organization_id, creator_username, version, author_id, created_at)
         VALUES
-            ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+            ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
     `
-	_, err := s.db.ExecContext(ctx, query,
-		b.ID, b.Name, b.Description, b.Status, b.TenderID, b.OrganizationID, b.CreatorUsername, b.Version)
+	_, err := s.conn(ctx).ExecContext(ctx, query,
+		b.ID, b.Name, b.Description, b.Status, b.TenderID, b.OrganizationID, b.CreatorUsername, b.Version, AuthorFrom(ctx))
 	return err
 }
 
-func (s *Storage) GetBidReviewsByAuthorForTender(ctx context.Context, authorUsername string, tenderID int) ([]BidReview, error) {
+// GetBidVersions возвращает страницу истории версий предложения, начиная с
+// последней, и общее число записей истории
+func (s *Storage) GetBidVersions(ctx context.Context, bidID string, page Page) ([]Version, int, error) {
+	return s.selectVersions(ctx, "bid_versions", "bid_id", bidID, page)
+}
+
+// selectVersions выбирает страницу истории из table (tender_versions или
+// bid_versions) по владельцу column = id и считает все его записи
+func (s *Storage) selectVersions(ctx context.Context, table, column, id string, page Page) ([]Version, int, error) {
+	args := []interface{}{id}
+
+	var total int
+	count := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = $1", table, column)
+	if err := s.conn(ctx).GetContext(ctx, &total, count, args...); err != nil {
+		return nil, 0, err
+	}
+
+	query, args, reversed := versionOrder.on("v").paginate(fmt.Sprintf(`
+        SELECT v.id, v.version, v.status, v.author_id, e.username AS author_username, v.created_at
+        FROM %s v
+        LEFT JOIN employee e ON e.id = v.author_id
+        WHERE v.%s = $1`, table, column), args, page)
+	versions := []Version{}
+	if err := s.conn(ctx).SelectContext(ctx, &versions, query, args...); err != nil {
+		return nil, 0, err
+	}
+	if reversed {
+		slices.Reverse(versions)
+	}
+	return versions, total, nil
+}
+
+func (s *Storage) GetBidReviewsByAuthorForTender(ctx context.Context, authorUsername string, tenderID string) ([]BidReview, error) {
 	var reviews []BidReview
 	query := `
         SELECT r.*
         FROM bid_review r
         JOIN bid b ON r.bid_id = b.id
         WHERE b.creator_username = $1 AND b.tender_id = $2
+        AND r.deleted_at IS NULL AND b.deleted_at IS NULL
         ORDER BY r.created_at DESC
     `
-	err := s.db.SelectContext(ctx, &reviews, query, authorUsername, tenderID)
+	err := s.conn(ctx).SelectContext(ctx, &reviews, query, authorUsername, tenderID)
 	return reviews, err
 }
 
-func (s *Storage) AddBidDecision(ctx context.Context, bidID, userID int, decision string) error {
+// PurgeResult - сколько записей удалила очистка архива
+type PurgeResult struct {
+	Tenders int `json:"tenders"`
+	Bids    int `json:"bids"`
+	Reviews int `json:"reviews"`
+}
+
+// PurgeArchived окончательно удаляет тендеры, предложения и отзывы, которые
+// находятся в архиве дольше retention. Вместе с записью удаляются ее версии
+// и голоса. Тендер удаляется, только если у него не осталось предложений:
+// предложения, которые не в архиве или в архиве меньше retention, сохраняют
+// тендер до следующей очистки.
+func (s *Storage) PurgeArchived(ctx context.Context, retention time.Duration) (PurgeResult, error) {
+	var result PurgeResult
+	err := s.WithTx(ctx, func(ctx context.Context) error {
+		for _, purge := range []struct {
+			table, where string
+			count        *int
+		}{
+			{"bid_review", "", &result.Reviews},
+			{"bid", "", &result.Bids},
+			{"tender", " AND NOT EXISTS (SELECT 1 FROM bid b WHERE b.tender_id = tender.id)", &result.Tenders},
+		} {
+			query := `DELETE FROM ` + purge.table + ` WHERE deleted_at <= NOW() - make_interval(secs => $1)` + purge.where
+			res, err := s.conn(ctx).ExecContext(ctx, query, retention.Seconds())
+			if err != nil {
+				return fmt.Errorf("purge %s: %w", purge.table, err)
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			*purge.count = int(affected)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// Действия, после которых голос попадает в историю
+const (
+	DecisionOverwritten = "Overwritten"
+	DecisionRevoked     = "Revoked"
+)
+
+// AddBidDecision записывает голос сотрудника. Если сотрудник уже голосовал,
+// прежний голос переносится в bid_decision_history.
+func (s *Storage) AddBidDecision(ctx context.Context, bidID string, userID int, decision, comment string) error {
+	archive := `
+        INSERT INTO bid_decision_history (bid_id, user_id, decision, comment, decided_at, action)
+        SELECT bid_id, user_id, decision, comment, created_at, $3
+        FROM bid_decision
+        WHERE bid_id = $1 AND user_id = $2
+    `
 	query := `
-        INSERT INTO bid_decision (bid_id, user_id, decision, created_at)
-        VALUES ($1, $2, $3, NOW())
-        ON CONFLICT (bid_id, user_id) DO UPDATE SET decision = EXCLUDED.decision, created_at = NOW()
+        INSERT INTO bid_decision (bid_id, user_id, decision, comment, created_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (bid_id, user_id) DO UPDATE
+        SET decision = EXCLUDED.decision, comment = EXCLUDED.comment, created_at = NOW()
     `
-	_, err := s.db.ExecContext(ctx, query, bidID, userID, decision)
-	return err
+	return s.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.conn(ctx).ExecContext(ctx, archive, bidID, userID, DecisionOverwritten); err != nil {
+			return err
+		}
+		_, err := s.conn(ctx).ExecContext(ctx, query, bidID, userID, decision, comment)
+		return err
+	})
+}
+
+// RevokeBidDecision удаляет голос сотрудника, сохраняя его в истории.
+// Если голоса нет, возвращает ErrNotFound.
+func (s *Storage) RevokeBidDecision(ctx context.Context, bidID string, userID int) error {
+	archive := `
+        INSERT INTO bid_decision_history (bid_id, user_id, decision, comment, decided_at, action)
+        SELECT bid_id, user_id, decision, comment, created_at, $3
+        FROM bid_decision
+        WHERE bid_id = $1 AND user_id = $2
+    `
+	query := `DELETE FROM bid_decision WHERE bid_id = $1 AND user_id = $2`
+	return s.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.conn(ctx).ExecContext(ctx, archive, bidID, userID, DecisionRevoked); err != nil {
+			return err
+		}
+		res, err := s.conn(ctx).ExecContext(ctx, query, bidID, userID)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// BidDecision (Голос ответственного по предложению)
+type BidDecision struct {
+	ID        int       `db:"id" json:"id"`
+	BidID     string    `db:"bid_id" json:"bidId"`
+	UserID    int       `db:"user_id" json:"userId"`
+	Username  string    `db:"username" json:"username"`
+	Decision  string    `db:"decision" json:"decision"`
+	Comment   string    `db:"comment" json:"comment"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+func (s *Storage) GetBidDecisions(ctx context.Context, bidID string) ([]BidDecision, error) {
+	decisions := []BidDecision{}
+	query := `
+        SELECT d.id, d.bid_id, d.user_id, COALESCE(e.username, '') AS username, d.decision, d.comment, d.created_at
+        FROM bid_decision d
+        LEFT JOIN employee e ON e.id = d.user_id
+        WHERE d.bid_id = $1
+        ORDER BY d.created_at, d.id`
+	err := s.conn(ctx).SelectContext(ctx, &decisions, query, bidID)
+	return decisions, err
+}
+
+// BidDecisionHistory (Переписанный или отозванный голос)
+type BidDecisionHistory struct {
+	ID        int        `db:"id" json:"id"`
+	BidID     string     `db:"bid_id" json:"bidId"`
+	UserID    int        `db:"user_id" json:"userId"`
+	Username  string     `db:"username" json:"username"`
+	Decision  string     `db:"decision" json:"decision"`
+	Comment   string     `db:"comment" json:"comment"`
+	DecidedAt *time.Time `db:"decided_at" json:"decidedAt"`
+	Action    string     `db:"action" json:"action"`
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
 }
-func (s *Storage) GetBidDecisionsCount(ctx context.Context, bidID int) (accepts int, rejects int, err error) {
+
+func (s *Storage) GetBidDecisionHistory(ctx context.Context, bidID string) ([]BidDecisionHistory, error) {
+	history := []BidDecisionHistory{}
+	query := `
+        SELECT h.id, h.bid_id, h.user_id, COALESCE(e.username, '') AS username, h.decision, h.comment, h.decided_at, h.action, h.created_at
+        FROM bid_decision_history h
+        LEFT JOIN employee e ON e.id = h.user_id
+        WHERE h.bid_id = $1
+        ORDER BY h.created_at, h.id`
+	err := s.conn(ctx).SelectContext(ctx, &history, query, bidID)
+	return history, err
+}
+
+func (s *Storage) GetBidDecisionsCount(ctx context.Context, bidID string) (accepts int, rejects int, err error) {
 	query := `
         SELECT 
             COUNT(CASE WHEN decision = 'Approved' THEN 1 END),
@@ -396,15 +1115,15 @@ func (s *Storage) GetBidDecisionsCount(ctx context.Context, bidID int) (accepts
         FROM bid_decision
         WHERE bid_id = $1
     `
-	err = s.db.QueryRowContext(ctx, query, bidID).Scan(&accepts, &rejects)
+	err = s.conn(ctx).QueryRowContext(ctx, query, bidID).Scan(&accepts, &rejects)
 	return
 }
 
-func (s *Storage) GetResponsibleCount(ctx context.Context, organizationID int) (int, error) {
+func (s *Storage) GetResponsibleCount(ctx context.Context, organizationID string) (int, error) {
 	var count int
 	query := `
         SELECT COUNT(1) FROM organization_responsible WHERE organization_id = $1
     `
-	err := s.db.GetContext(ctx, &count, query, organizationID)
+	err := s.conn(ctx).GetContext(ctx, &count, query, organizationID)
 	return count, err
 }
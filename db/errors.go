@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// Ошибки хранилища. Методы Storage возвращают их (обернутыми) вместо
+// sql.ErrNoRows и *pq.Error, чтобы вызывающий мог отличить отсутствующую
+// запись или конфликт от сбоя базы данных.
+var (
+	// ErrNotFound - запись не найдена
+	ErrNotFound = errors.New("not found")
+	// ErrConflict - изменение конфликтует с параллельной транзакцией
+	ErrConflict = errors.New("conflict")
+	// ErrUniqueViolation - запись с таким ключом уже существует
+	ErrUniqueViolation = errors.New("unique violation")
+	// ErrForeignKey - запись ссылается на несуществующую или используется другой записью
+	ErrForeignKey = errors.New("foreign key violation")
+)
+
+// ErrVersionConflict возвращается, если запись изменили после того,
+// как ее прочитал вызывающий (оптимистичная блокировка по полю version).
+var ErrVersionConflict = fmt.Errorf("version %w", ErrConflict)
+
+// Коды ошибок PostgreSQL, которые переводятся в ошибки хранилища
+const (
+	pqUniqueViolation      = "23505"
+	pqForeignKeyViolation  = "23503"
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+	pqLockNotAvailable     = "55P03"
+)
+
+// translateError переводит ошибку драйвера в ошибку хранилища.
+// Исходная ошибка остается в цепочке для логов.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch pqErr.Code {
+	case pqUniqueViolation:
+		return fmt.Errorf("%w: %w", ErrUniqueViolation, err)
+	case pqForeignKeyViolation:
+		return fmt.Errorf("%w: %w", ErrForeignKey, err)
+	case pqSerializationFailure, pqDeadlockDetected, pqLockNotAvailable:
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	return err
+}
+
+// translatingConn переводит ошибки запросов через translateError
+type translatingConn struct {
+	q queryer
+}
+
+func (c translatingConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	res, err := c.q.ExecContext(ctx, query, args...)
+	return res, translateError(err)
+}
+
+func (c translatingConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) row {
+	return row{c.q.QueryRowContext(ctx, query, args...)}
+}
+
+func (c translatingConn) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return translateError(c.q.GetContext(ctx, dest, query, args...))
+}
+
+func (c translatingConn) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return translateError(c.q.SelectContext(ctx, dest, query, args...))
+}
+
+// row - *sql.Row, у которого Scan возвращает ошибки хранилища
+type row struct {
+	r *sql.Row
+}
+
+func (r row) Scan(dest ...interface{}) error {
+	return translateError(r.r.Scan(dest...))
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError(t *testing.T) {
+	require.NoError(t, translateError(nil))
+	require.Equal(t, ErrNotFound, translateError(sql.ErrNoRows))
+	require.ErrorIs(t, translateError(fmt.Errorf("get tender: %w", sql.ErrNoRows)), ErrNotFound)
+
+	cases := map[pq.ErrorCode]error{
+		"23505": ErrUniqueViolation,
+		"23503": ErrForeignKey,
+		"40001": ErrConflict,
+		"40P01": ErrConflict,
+	}
+	for code, want := range cases {
+		pqErr := &pq.Error{Code: code, Message: "boom"}
+		got := translateError(pqErr)
+		require.ErrorIs(t, got, want, code)
+		// Исходная ошибка драйвера остается в цепочке
+		var inner *pq.Error
+		require.True(t, errors.As(got, &inner))
+		require.Equal(t, code, inner.Code)
+	}
+
+	// Прочие ошибки не меняются
+	other := &pq.Error{Code: "23514"}
+	require.Equal(t, error(other), translateError(other))
+	require.ErrorIs(t, ErrVersionConflict, ErrConflict)
+}
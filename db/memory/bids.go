@@ -0,0 +1,293 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"tenders/db"
+
+	"github.com/google/uuid"
+)
+
+func (s *Storage) CreateBid(ctx context.Context, b *db.Bid) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.tenders[b.TenderID]; !ok {
+		return foreignKey("tender", b.TenderID)
+	}
+	if _, ok := s.state.organizations[b.OrganizationID]; !ok {
+		return foreignKey("organization", b.OrganizationID)
+	}
+	b.ID = uuid.NewString()
+	b.Version = 1
+	b.CreatedAt = now()
+	b.UpdatedAt = b.CreatedAt
+	s.state.bids[b.ID] = *b
+	// Сохраняем первую версию
+	s.state.saveBidVersion(ctx, *b)
+	return nil
+}
+
+func (s *Storage) GetBid(ctx context.Context, bidID string) (*db.Bid, error) {
+	defer s.lock(ctx)()
+	b, ok := s.state.bids[bidID]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return &b, nil
+}
+
+// LockBid читает предложение. Отдельная блокировка строки не нужна: транзакция
+// WithTx и так держит все хранилище.
+func (s *Storage) LockBid(ctx context.Context, bidID string) (*db.Bid, error) {
+	return s.GetBid(ctx, bidID)
+}
+
+// UpdateBid сохраняет предложение, если его версия все еще равна b.Version,
+// увеличивает версию и записывает снимок. Иначе возвращает ErrVersionConflict.
+func (s *Storage) UpdateBid(ctx context.Context, b *db.Bid) error {
+	defer s.lock(ctx)()
+	stored, ok := s.state.bids[b.ID]
+	if !ok || stored.Version != b.Version {
+		return db.ErrVersionConflict
+	}
+	stored.Name = b.Name
+	stored.Description = b.Description
+	stored.Status = b.Status
+	stored.Version++
+	stored.UpdatedAt = now()
+	s.state.bids[b.ID] = stored
+	b.Version = stored.Version
+	// Сохраняем новую версию
+	s.state.saveBidVersion(ctx, *b)
+	return nil
+}
+
+// ArchiveBid помечает предложение удаленным. Кто удалил предложение, берется
+// из контекста (db.WithAuthor). ErrNotFound, если предложения нет или оно уже
+// в архиве.
+func (s *Storage) ArchiveBid(ctx context.Context, b *db.Bid) error {
+	defer s.lock(ctx)()
+	stored, ok := s.state.bids[b.ID]
+	if !ok || stored.DeletedAt != nil {
+		return db.ErrNotFound
+	}
+	deletedAt := now()
+	stored.DeletedAt, stored.DeletedBy = &deletedAt, db.AuthorFrom(ctx)
+	s.state.bids[b.ID] = stored
+	b.DeletedAt, b.DeletedBy = stored.DeletedAt, stored.DeletedBy
+	return nil
+}
+
+// RestoreBid возвращает предложение из архива. ErrNotFound, если предложения
+// в архиве нет.
+func (s *Storage) RestoreBid(ctx context.Context, b *db.Bid) error {
+	defer s.lock(ctx)()
+	stored, ok := s.state.bids[b.ID]
+	if !ok || stored.DeletedAt == nil {
+		return db.ErrNotFound
+	}
+	stored.DeletedAt, stored.DeletedBy = nil, nil
+	s.state.bids[b.ID] = stored
+	b.DeletedAt, b.DeletedBy = nil, nil
+	return nil
+}
+
+// SaveBidVersion сохраняет снимок предложения. Автор версии берется из контекста (db.WithAuthor).
+func (s *Storage) SaveBidVersion(ctx context.Context, b *db.Bid) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.bids[b.ID]; !ok {
+		return foreignKey("bid", b.ID)
+	}
+	s.state.saveBidVersion(ctx, *b)
+	return nil
+}
+
+func (st *state) saveBidVersion(ctx context.Context, b db.Bid) {
+	b.CreatedAt = now()
+	b.UpdatedAt = time.Time{} // в bid_versions нет updated_at
+	st.bidVersions = append(st.bidVersions, bidVersion{
+		id:       st.nextID("bid_versions"),
+		bid:      b,
+		authorID: db.AuthorFrom(ctx),
+	})
+}
+
+func (s *Storage) GetBidVersion(ctx context.Context, bidID string, version int) (*db.Bid, error) {
+	defer s.lock(ctx)()
+	// Последний снимок версии, как ORDER BY id DESC LIMIT 1
+	for _, v := range slices.Backward(s.state.bidVersions) {
+		if v.bid.ID == bidID && v.bid.Version == version {
+			b := v.bid
+			return &b, nil
+		}
+	}
+	return nil, db.ErrNotFound
+}
+
+// GetBidVersions возвращает историю версий предложения, начиная с последней
+func (s *Storage) GetBidVersions(ctx context.Context, bidID string, page db.Page) ([]db.Version, int, error) {
+	defer s.lock(ctx)()
+	versions := []db.Version{}
+	for _, v := range s.state.bidVersions {
+		if v.bid.ID == bidID {
+			versions = append(versions, s.state.version(v.id, v.bid.Version, v.bid.Status, v.authorID, v.bid.CreatedAt))
+		}
+	}
+	return paginate(versions, page, db.VersionCursor, compareVersions), len(versions), nil
+}
+
+// GetUserBids возвращает страницу предложений пользователя, от новых к старым,
+// и общее число его предложений
+func (s *Storage) GetUserBids(ctx context.Context, username string, page db.Page) ([]db.Bid, int, error) {
+	defer s.lock(ctx)()
+	bids := []db.Bid{}
+	for _, b := range s.state.bids {
+		if b.CreatorUsername == username && b.DeletedAt == nil {
+			bids = append(bids, b)
+		}
+	}
+	return paginate(bids, page, db.BidCursor, compareBids), len(bids), nil
+}
+
+// GetBidsForTender возвращает страницу предложений по тендеру, от новых к
+// старым, и общее число таких предложений. Условие повторяет запрос
+// db.Storage: автор предложения - username или ответственный за организацию
+// предложения.
+func (s *Storage) GetBidsForTender(ctx context.Context, tenderID string, username string, page db.Page) ([]db.Bid, int, error) {
+	defer s.lock(ctx)()
+	bids := []db.Bid{}
+	for _, b := range s.state.bids {
+		if b.TenderID != tenderID || b.DeletedAt != nil {
+			continue
+		}
+		creator, ok := s.state.employeeByUsername(b.CreatorUsername)
+		if !ok {
+			continue
+		}
+		_, responsible := s.state.responsibles[responsibleKey{b.OrganizationID, creator.ID}]
+		if creator.Username == username || responsible {
+			bids = append(bids, b)
+		}
+	}
+	return paginate(bids, page, db.BidCursor, compareBids), len(bids), nil
+}
+
+// deleteBid удаляет предложение вместе с версиями, отзывами и голосами
+func (st *state) deleteBid(id string) {
+	delete(st.bids, id)
+	st.bidVersions = slices.DeleteFunc(slices.Clone(st.bidVersions), func(v bidVersion) bool { return v.bid.ID == id })
+	st.reviews = slices.DeleteFunc(slices.Clone(st.reviews), func(r db.BidReview) bool { return r.BidID == id })
+	st.history = slices.DeleteFunc(slices.Clone(st.history), func(h db.BidDecisionHistory) bool { return h.BidID == id })
+	maps.DeleteFunc(st.decisions, func(k decisionKey, _ db.BidDecision) bool { return k.bidID == id })
+}
+
+func (s *Storage) CreateBidReview(ctx context.Context, r *db.BidReview) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.bids[r.BidID]; !ok {
+		return foreignKey("bid", r.BidID)
+	}
+	r.ID = s.state.nextID("bid_review")
+	r.CreatedAt = now()
+	s.state.reviews = append(s.state.reviews, *r)
+	return nil
+}
+
+// GetBidReviewsByAuthorForTender возвращает отзывы на предложения автора по
+// тендеру, от новых к старым
+func (s *Storage) GetBidReviewsByAuthorForTender(ctx context.Context, authorUsername string, tenderID string) ([]db.BidReview, error) {
+	defer s.lock(ctx)()
+	reviews := []db.BidReview{}
+	for _, r := range s.state.reviews {
+		b := s.state.bids[r.BidID]
+		if b.CreatorUsername == authorUsername && b.TenderID == tenderID && b.DeletedAt == nil && r.DeletedAt == nil {
+			reviews = append(reviews, r)
+		}
+	}
+	slices.SortStableFunc(reviews, func(a, b db.BidReview) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return reviews, nil
+}
+
+// AddBidDecision записывает голос сотрудника. Если сотрудник уже голосовал,
+// прежний голос переносится в историю.
+func (s *Storage) AddBidDecision(ctx context.Context, bidID string, employeeID int, decision, comment string) error {
+	defer s.lock(ctx)()
+	st := &s.state
+	if _, ok := st.bids[bidID]; !ok {
+		return foreignKey("bid", bidID)
+	}
+	if _, ok := st.employees[employeeID]; !ok {
+		return foreignKey("employee", employeeID)
+	}
+
+	key := decisionKey{bidID, employeeID}
+	d, voted := st.decisions[key]
+	if voted {
+		st.archiveDecision(d, db.DecisionOverwritten)
+	} else {
+		d = db.BidDecision{ID: st.nextID("bid_decision"), BidID: bidID, UserID: employeeID}
+	}
+	d.Decision = decision
+	d.Comment = comment
+	d.CreatedAt = now()
+	st.decisions[key] = d
+	return nil
+}
+
+// RevokeBidDecision удаляет голос сотрудника, сохраняя его в истории.
+// Если голоса нет, возвращает ErrNotFound.
+func (s *Storage) RevokeBidDecision(ctx context.Context, bidID string, employeeID int) error {
+	defer s.lock(ctx)()
+	key := decisionKey{bidID, employeeID}
+	d, ok := s.state.decisions[key]
+	if !ok {
+		return db.ErrNotFound
+	}
+	s.state.archiveDecision(d, db.DecisionRevoked)
+	delete(s.state.decisions, key)
+	return nil
+}
+
+func (st *state) archiveDecision(d db.BidDecision, action string) {
+	decidedAt := d.CreatedAt
+	st.history = append(st.history, db.BidDecisionHistory{
+		ID:        st.nextID("bid_decision_history"),
+		BidID:     d.BidID,
+		UserID:    d.UserID,
+		Decision:  d.Decision,
+		Comment:   d.Comment,
+		DecidedAt: &decidedAt,
+		Action:    action,
+		CreatedAt: now(),
+	})
+}
+
+func (s *Storage) GetBidDecisions(ctx context.Context, bidID string) ([]db.BidDecision, error) {
+	defer s.lock(ctx)()
+	decisions := []db.BidDecision{}
+	for key, d := range s.state.decisions {
+		if key.bidID == bidID {
+			d.Username = s.state.employees[d.UserID].Username
+			decisions = append(decisions, d)
+		}
+	}
+	slices.SortFunc(decisions, func(a, b db.BidDecision) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return decisions, nil
+}
+
+func (s *Storage) GetBidDecisionHistory(ctx context.Context, bidID string) ([]db.BidDecisionHistory, error) {
+	defer s.lock(ctx)()
+	history := []db.BidDecisionHistory{}
+	for _, h := range s.state.history {
+		if h.BidID == bidID {
+			h.Username = s.state.employees[h.UserID].Username
+			history = append(history, h)
+		}
+	}
+	// Записи добавляются по порядку, поэтому уже отсортированы по created_at и id
+	return history, nil
+}
@@ -0,0 +1,251 @@
+// Package memory - хранилище в памяти с тем же поведением, что у db.Storage:
+// те же ошибки (db.ErrNotFound, db.ErrVersionConflict и т.д.), порядок списков
+// и курсоры страниц. Нужно для запуска API без Postgres (-storage=memory) и
+// для тестов. Данные теряются при остановке процесса.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"tenders/db"
+)
+
+// Storage хранит данные в памяти. Безопасно для одновременного использования:
+// каждый вызов, как и транзакция WithTx целиком, выполняется под общей
+// блокировкой, поэтому транзакции идут строго по очереди.
+type Storage struct {
+	mu    sync.Mutex
+	state state
+}
+
+// New создает пустое хранилище
+func New() *Storage {
+	return &Storage{state: newState()}
+}
+
+// responsibleKey - ответственный или сотрудник организации
+type responsibleKey struct {
+	organizationID string
+	userID         int
+}
+
+// decisionKey - голос сотрудника по предложению
+type decisionKey struct {
+	bidID  string
+	userID int
+}
+
+// tenderVersion - снимок тендера (строка tender_versions)
+type tenderVersion struct {
+	id       int
+	tender   db.Tender
+	authorID *int
+}
+
+// bidVersion - снимок предложения (строка bid_versions)
+type bidVersion struct {
+	id       int
+	bid      db.Bid
+	authorID *int
+}
+
+// state - все таблицы. Значения в map и срезах не меняются на месте, а
+// заменяются целиком, поэтому для отката транзакции достаточно
+// поверхностной копии (clone).
+type state struct {
+	// ids - последние выданные целые идентификаторы по таблицам (как SERIAL).
+	// Организации, тендеры и предложения получают UUID.
+	ids map[string]int
+
+	employees     map[int]db.Employee
+	platformRoles map[int]string
+	organizations map[string]db.Organization
+	responsibles  map[responsibleKey]string
+	members       map[responsibleKey]bool
+	policies      map[int]db.ApprovalPolicy
+
+	tenders        map[string]db.Tender
+	tenderVersions []tenderVersion
+	bids           map[string]db.Bid
+	bidVersions    []bidVersion
+	reviews        []db.BidReview
+	decisions      map[decisionKey]db.BidDecision
+	history        []db.BidDecisionHistory
+}
+
+func newState() state {
+	return state{
+		ids:           map[string]int{},
+		employees:     map[int]db.Employee{},
+		platformRoles: map[int]string{},
+		organizations: map[string]db.Organization{},
+		responsibles:  map[responsibleKey]string{},
+		members:       map[responsibleKey]bool{},
+		policies:      map[int]db.ApprovalPolicy{},
+		tenders:       map[string]db.Tender{},
+		bids:          map[string]db.Bid{},
+		decisions:     map[decisionKey]db.BidDecision{},
+	}
+}
+
+func (st state) clone() state {
+	return state{
+		ids:            maps.Clone(st.ids),
+		employees:      maps.Clone(st.employees),
+		platformRoles:  maps.Clone(st.platformRoles),
+		organizations:  maps.Clone(st.organizations),
+		responsibles:   maps.Clone(st.responsibles),
+		members:        maps.Clone(st.members),
+		policies:       maps.Clone(st.policies),
+		tenders:        maps.Clone(st.tenders),
+		tenderVersions: slices.Clone(st.tenderVersions),
+		bids:           maps.Clone(st.bids),
+		bidVersions:    slices.Clone(st.bidVersions),
+		reviews:        slices.Clone(st.reviews),
+		decisions:      maps.Clone(st.decisions),
+		history:        slices.Clone(st.history),
+	}
+}
+
+// nextID выдает следующий идентификатор таблицы table
+func (st *state) nextID(table string) int {
+	st.ids[table]++
+	return st.ids[table]
+}
+
+// ResolveLegacyID всегда возвращает ErrNotFound: записи в памяти созданы
+// после перехода на UUID, прежних целых идентификаторов у них нет.
+func (s *Storage) ResolveLegacyID(ctx context.Context, table string, legacyID int) (string, error) {
+	return "", db.ErrNotFound
+}
+
+// txKey - ключ контекста с хранилищем, транзакция которого открыта
+type txKey struct{}
+
+// lock захватывает хранилище на время вызова. Внутри WithTx блокировка уже
+// захвачена транзакцией, и lock ничего не делает.
+func (s *Storage) lock(ctx context.Context) func() {
+	if ctx.Value(txKey{}) == s {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// WithTx выполняет fn атомарно: если fn вернула ошибку или упала с паникой,
+// все изменения, сделанные с переданным в fn контекстом, отменяются.
+// Вложенный вызов переиспользует внешнюю транзакцию.
+func (s *Storage) WithTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if ctx.Value(txKey{}) == s {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := s.state.clone()
+	defer func() {
+		if p := recover(); p != nil {
+			s.state = saved
+			panic(p)
+		}
+		if err != nil {
+			s.state = saved
+		}
+	}()
+	return fn(context.WithValue(ctx, txKey{}, s))
+}
+
+// PurgeArchived окончательно удаляет тендеры, предложения и отзывы, которые
+// находятся в архиве дольше retention, так же, как db.Storage: тендер
+// удаляется, только если у него не осталось предложений
+func (s *Storage) PurgeArchived(ctx context.Context, retention time.Duration) (db.PurgeResult, error) {
+	defer s.lock(ctx)()
+	st := &s.state
+	cutoff := now().Add(-retention)
+	expired := func(deletedAt *time.Time) bool { return deletedAt != nil && !deletedAt.After(cutoff) }
+
+	var result db.PurgeResult
+	reviews := len(st.reviews)
+	st.reviews = slices.DeleteFunc(slices.Clone(st.reviews), func(r db.BidReview) bool { return expired(r.DeletedAt) })
+	result.Reviews = reviews - len(st.reviews)
+	for _, b := range st.bids {
+		if expired(b.DeletedAt) {
+			st.deleteBid(b.ID)
+			result.Bids++
+		}
+	}
+	for _, t := range st.tenders {
+		if expired(t.DeletedAt) && !st.hasBids(t.ID) {
+			st.deleteTender(t.ID)
+			result.Tenders++
+		}
+	}
+	return result, nil
+}
+
+// now возвращает текущее время с точностью Postgres (микросекунды)
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// foreignKey возвращает ошибку ссылки на несуществующую запись
+func foreignKey(table string, id interface{}) error {
+	return fmt.Errorf("%w: %s %v does not exist", db.ErrForeignKey, table, id)
+}
+
+// paginate сортирует rows в порядке списка и выбирает страницу page так же,
+// как db.Storage: по курсору, если он задан, иначе по offset. compare
+// сравнивает ключи сортировки строк в порядке списка.
+func paginate[T any](rows []T, page db.Page, key func(T) db.Cursor, compare func(a, b db.Cursor) int) []T {
+	slices.SortFunc(rows, func(a, b T) int { return compare(key(a), key(b)) })
+
+	if c := page.Cursor; c != nil {
+		// Первая строка не раньше курсора
+		i, found := slices.BinarySearchFunc(rows, *c, func(row T, c db.Cursor) int { return compare(key(row), c) })
+		if c.Before {
+			return rows[max(0, i-page.Limit):i]
+		}
+		if found {
+			i++
+		}
+		rows = rows[i:]
+	} else {
+		rows = rows[min(page.Offset, len(rows)):]
+	}
+	return rows[:min(page.Limit, len(rows))]
+}
+
+// compareByName - порядок тендеров и организаций: по названию, затем по id
+func compareByName(a, b db.Cursor) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+}
+
+// compareBids - порядок предложений: от новых к старым, затем по id по убыванию
+func compareBids(a, b db.Cursor) int {
+	return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+}
+
+// compareEmployees - порядок сотрудников: по username, затем по id
+func compareEmployees(a, b db.Cursor) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(intID(a), intID(b)))
+}
+
+// compareVersions - порядок истории версий: от последней к первой, затем по
+// строке истории по убыванию
+func compareVersions(a, b db.Cursor) int {
+	return cmp.Or(cmp.Compare(b.Version, a.Version), cmp.Compare(intID(b), intID(a)))
+}
+
+// intID возвращает целый id из курсора сотрудника или версии. Курсоры с
+// нецелым id отклоняет обработчик.
+func intID(c db.Cursor) int {
+	id, _ := strconv.Atoi(c.ID)
+	return id
+}
@@ -0,0 +1,169 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"tenders/db"
+	"tenders/db/memory"
+	"tenders/db/storagetest"
+	"tenders/internal/handlers"
+
+	"github.com/stretchr/testify/require"
+)
+
+var _ handlers.StorageInterface = (*memory.Storage)(nil)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) handlers.StorageInterface { return memory.New() })
+}
+
+// newTender создает организацию и тендер в ней
+func newTender(t *testing.T, s *memory.Storage, name string) *db.Tender {
+	t.Helper()
+	ctx := context.Background()
+	org := &db.Organization{Name: "Org " + name, Type: db.OrganizationLLC}
+	require.NoError(t, s.CreateOrganization(ctx, org))
+	tender := &db.Tender{Name: name, Description: "d", ServiceType: "Delivery", Status: "Created", OrganizationID: org.ID}
+	require.NoError(t, s.CreateTender(ctx, tender))
+	return tender
+}
+
+func TestWithTxRollback(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	tender := newTender(t, s, "Tender")
+
+	failure := errors.New("failure")
+	err := s.WithTx(ctx, func(ctx context.Context) error {
+		tender.Name = "Renamed"
+		require.NoError(t, s.UpdateTender(ctx, tender))
+		require.NoError(t, s.CreateEmployee(ctx, &db.Employee{Username: "ghost"}))
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+
+	stored, err := s.GetTender(ctx, tender.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Tender", stored.Name)
+	require.Equal(t, 1, stored.Version)
+	_, err = s.GetEmployeeByUsername(ctx, "ghost")
+	require.ErrorIs(t, err, db.ErrNotFound)
+	versions, _, err := s.GetTenderVersions(ctx, tender.ID, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+
+	// Паника тоже откатывает транзакцию
+	require.Panics(t, func() {
+		s.WithTx(ctx, func(ctx context.Context) error {
+			require.NoError(t, s.CreateEmployee(ctx, &db.Employee{Username: "ghost"}))
+			panic("boom")
+		})
+	})
+	_, err = s.GetEmployeeByUsername(ctx, "ghost")
+	require.ErrorIs(t, err, db.ErrNotFound)
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	tender := newTender(t, s, "Tender")
+
+	// Все правки по версии 1: проходит ровно одна
+	const writers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.WithTx(ctx, func(ctx context.Context) error {
+				locked, err := s.LockTender(ctx, tender.ID)
+				if err != nil {
+					return err
+				}
+				locked.Version = 1
+				locked.Status = "Published"
+				return s.UpdateTender(ctx, locked)
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, db.ErrVersionConflict)
+	}
+	require.Equal(t, 1, succeeded)
+}
+
+func TestGetTendersPagination(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	for _, name := range []string{"C", "A", "B", "A"} {
+		newTender(t, s, name)
+	}
+
+	tenders, total, err := s.GetTenders(ctx, nil, db.Page{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+	require.Equal(t, []string{"A", "A"}, names(tenders))
+
+	// После второго "A" - "B" и "C"
+	after := db.TenderCursor(tenders[1])
+	tenders, _, err = s.GetTenders(ctx, nil, db.Page{Limit: 5, Cursor: &after})
+	require.NoError(t, err)
+	require.Equal(t, []string{"B", "C"}, names(tenders))
+
+	before := db.TenderCursor(tenders[1])
+	before.Before = true
+	tenders, _, err = s.GetTenders(ctx, nil, db.Page{Limit: 2, Cursor: &before})
+	require.NoError(t, err)
+	require.Equal(t, []string{"A", "B"}, names(tenders))
+
+	tenders, _, err = s.GetTenders(ctx, nil, db.Page{Limit: 2, Offset: 3})
+	require.NoError(t, err)
+	require.Equal(t, []string{"C"}, names(tenders))
+}
+
+func TestDeleteOrganizationCascades(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	org := &db.Organization{Name: "Empty", Type: db.OrganizationLLC}
+	require.NoError(t, s.CreateOrganization(ctx, org))
+	employee := &db.Employee{Username: "user"}
+	require.NoError(t, s.CreateEmployee(ctx, employee))
+	require.NoError(t, s.AddResponsible(ctx, org.ID, employee.ID, "member"))
+
+	require.NoError(t, s.DeleteOrganization(ctx, org.ID))
+	responsible, err := s.IsUserResponsibleForOrganization(ctx, employee.ID, org.ID)
+	require.NoError(t, err)
+	require.False(t, responsible)
+	require.ErrorIs(t, s.DeleteOrganization(ctx, org.ID), db.ErrNotFound)
+}
+
+// Записи закупок не удаляются вместе с организацией
+func TestDeleteOrganizationWithTenders(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+	tender := newTender(t, s, "Tender")
+
+	require.ErrorIs(t, s.DeleteOrganization(ctx, tender.OrganizationID), db.ErrForeignKey)
+	_, err := s.GetTender(ctx, tender.ID)
+	require.NoError(t, err)
+}
+
+func names(tenders []db.Tender) []string {
+	names := make([]string, len(tenders))
+	for i, t := range tenders {
+		names[i] = t.Name
+	}
+	return names
+}
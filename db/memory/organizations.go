@@ -0,0 +1,412 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"tenders/db"
+
+	"github.com/google/uuid"
+)
+
+func (s *Storage) CreateEmployee(ctx context.Context, e *db.Employee) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.employeeByUsername(e.Username); ok {
+		return fmt.Errorf("%w: employee %q already exists", db.ErrUniqueViolation, e.Username)
+	}
+	e.ID = s.state.nextID("employee")
+	e.CreatedAt = now()
+	e.UpdatedAt = e.CreatedAt
+	e.DeactivatedAt = nil
+	s.state.employees[e.ID] = *e
+	return nil
+}
+
+func (st *state) employeeByUsername(username string) (db.Employee, bool) {
+	for _, e := range st.employees {
+		if e.Username == username {
+			return e, true
+		}
+	}
+	return db.Employee{}, false
+}
+
+func (s *Storage) GetEmployeeByUsername(ctx context.Context, username string) (*db.Employee, error) {
+	defer s.lock(ctx)()
+	e, ok := s.state.employeeByUsername(username)
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return &e, nil
+}
+
+// ListEmployees ищет сотрудников по подстроке в username, имени или фамилии
+// без учета регистра. Деактивированные возвращаются, только если includeInactive.
+func (s *Storage) ListEmployees(ctx context.Context, search string, includeInactive bool, page db.Page) ([]db.Employee, int, error) {
+	defer s.lock(ctx)()
+	employees := []db.Employee{}
+	for _, e := range s.state.employees {
+		if !includeInactive && !e.Active() {
+			continue
+		}
+		if search == "" || containsFold(e.Username, search) || containsFold(e.FirstName, search) || containsFold(e.LastName, search) {
+			employees = append(employees, e)
+		}
+	}
+	return paginate(employees, page, db.EmployeeCursor, compareEmployees), len(employees), nil
+}
+
+// containsFold - аналог ILIKE '%substr%'
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func (s *Storage) UpdateEmployee(ctx context.Context, e *db.Employee) error {
+	defer s.lock(ctx)()
+	stored, ok := s.state.employeeByUsername(e.Username)
+	if !ok {
+		return db.ErrNotFound
+	}
+	stored.FirstName = e.FirstName
+	stored.LastName = e.LastName
+	stored.UpdatedAt = now()
+	s.state.employees[stored.ID] = stored
+	e.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// DeactivateEmployee запрещает сотруднику работать в системе. Повторная
+// деактивация не меняет время деактивации.
+func (s *Storage) DeactivateEmployee(ctx context.Context, username string) error {
+	defer s.lock(ctx)()
+	e, ok := s.state.employeeByUsername(username)
+	if !ok {
+		return db.ErrNotFound
+	}
+	e.UpdatedAt = now()
+	if e.DeactivatedAt == nil {
+		deactivatedAt := e.UpdatedAt
+		e.DeactivatedAt = &deactivatedAt
+	}
+	s.state.employees[e.ID] = e
+	return nil
+}
+
+// DeleteEmployee удаляет сотрудника вместе с ролями и членством в
+// организациях (как ON DELETE CASCADE в схеме), а в версиях и архиве ссылка на
+// него обнуляется. Сотрудника с голосами или историей голосов удалить нельзя
+// (ON DELETE RESTRICT): ErrForeignKey. В StorageInterface метода нет.
+func (s *Storage) DeleteEmployee(ctx context.Context, username string) error {
+	defer s.lock(ctx)()
+	st := &s.state
+	e, ok := st.employeeByUsername(username)
+	if !ok {
+		return db.ErrNotFound
+	}
+	for key := range st.decisions {
+		if key.userID == e.ID {
+			return fmt.Errorf("%w: employee %s has bid decisions", db.ErrForeignKey, username)
+		}
+	}
+	for _, h := range st.history {
+		if h.UserID == e.ID {
+			return fmt.Errorf("%w: employee %s has bid decision history", db.ErrForeignKey, username)
+		}
+	}
+
+	delete(st.employees, e.ID)
+	delete(st.platformRoles, e.ID)
+	maps.DeleteFunc(st.responsibles, func(k responsibleKey, _ string) bool { return k.userID == e.ID })
+	maps.DeleteFunc(st.members, func(k responsibleKey, _ bool) bool { return k.userID == e.ID })
+
+	byEmployee := func(id *int) bool { return id != nil && *id == e.ID }
+	st.tenderVersions = slices.Clone(st.tenderVersions)
+	for i, v := range st.tenderVersions {
+		if byEmployee(v.authorID) {
+			st.tenderVersions[i].authorID = nil
+		}
+	}
+	st.bidVersions = slices.Clone(st.bidVersions)
+	for i, v := range st.bidVersions {
+		if byEmployee(v.authorID) {
+			st.bidVersions[i].authorID = nil
+		}
+	}
+	for id, t := range st.tenders {
+		if byEmployee(t.DeletedBy) {
+			t.DeletedBy = nil
+			st.tenders[id] = t
+		}
+	}
+	for id, b := range st.bids {
+		if byEmployee(b.DeletedBy) {
+			b.DeletedBy = nil
+			st.bids[id] = b
+		}
+	}
+	st.reviews = slices.Clone(st.reviews)
+	for i, r := range st.reviews {
+		if byEmployee(r.DeletedBy) {
+			st.reviews[i].DeletedBy = nil
+		}
+	}
+	return nil
+}
+
+// SetPlatformRole назначает сотруднику роль площадки ("admin", "auditor").
+// Пустая роль снимает ее. В StorageInterface метода нет: в Postgres роли
+// назначаются вне API.
+func (s *Storage) SetPlatformRole(ctx context.Context, userID int, role string) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.employees[userID]; !ok {
+		return foreignKey("employee", userID)
+	}
+	if role == "" {
+		delete(s.state.platformRoles, userID)
+		return nil
+	}
+	s.state.platformRoles[userID] = role
+	return nil
+}
+
+func (s *Storage) GetPlatformRole(ctx context.Context, userID int) (string, error) {
+	defer s.lock(ctx)()
+	return s.state.platformRoles[userID], nil
+}
+
+func (s *Storage) CreateOrganization(ctx context.Context, o *db.Organization) error {
+	defer s.lock(ctx)()
+	o.ID = uuid.NewString()
+	o.CreatedAt = now()
+	o.UpdatedAt = o.CreatedAt
+	s.state.organizations[o.ID] = *o
+	return nil
+}
+
+func (s *Storage) GetOrganization(ctx context.Context, id string) (*db.Organization, error) {
+	defer s.lock(ctx)()
+	o, ok := s.state.organizations[id]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return &o, nil
+}
+
+// LockOrganization читает организацию. Отдельная блокировка строки не нужна:
+// транзакция WithTx и так держит все хранилище.
+func (s *Storage) LockOrganization(ctx context.Context, id string) (*db.Organization, error) {
+	return s.GetOrganization(ctx, id)
+}
+
+// ListOrganizations ищет организации по подстроке в названии без учета регистра
+func (s *Storage) ListOrganizations(ctx context.Context, search string, page db.Page) ([]db.Organization, int, error) {
+	defer s.lock(ctx)()
+	organizations := []db.Organization{}
+	for _, o := range s.state.organizations {
+		if search == "" || containsFold(o.Name, search) {
+			organizations = append(organizations, o)
+		}
+	}
+	return paginate(organizations, page, db.OrganizationCursor, compareByName), len(organizations), nil
+}
+
+func (s *Storage) UpdateOrganization(ctx context.Context, o *db.Organization) error {
+	defer s.lock(ctx)()
+	stored, ok := s.state.organizations[o.ID]
+	if !ok {
+		return db.ErrNotFound
+	}
+	stored.Name = o.Name
+	stored.Description = o.Description
+	stored.Type = o.Type
+	stored.UpdatedAt = now()
+	s.state.organizations[o.ID] = stored
+	o.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+// DeleteOrganization удаляет организацию вместе с ответственными и политиками
+// (как ON DELETE CASCADE в схеме). Организацию с тендерами или предложениями
+// удалить нельзя (ON DELETE RESTRICT): ErrForeignKey.
+func (s *Storage) DeleteOrganization(ctx context.Context, id string) error {
+	defer s.lock(ctx)()
+	st := &s.state
+	if _, ok := st.organizations[id]; !ok {
+		return db.ErrNotFound
+	}
+	for _, t := range st.tenders {
+		if t.OrganizationID == id {
+			return fmt.Errorf("%w: organization %s has tenders", db.ErrForeignKey, id)
+		}
+	}
+	for _, b := range st.bids {
+		if b.OrganizationID == id {
+			return fmt.Errorf("%w: organization %s has bids", db.ErrForeignKey, id)
+		}
+	}
+	delete(st.organizations, id)
+	maps.DeleteFunc(st.responsibles, func(k responsibleKey, _ string) bool { return k.organizationID == id })
+	maps.DeleteFunc(st.members, func(k responsibleKey, _ bool) bool { return k.organizationID == id })
+	maps.DeleteFunc(st.policies, func(_ int, p db.ApprovalPolicy) bool { return p.OrganizationID == id })
+	return nil
+}
+
+// AddResponsible назначает сотрудника ответственным за организацию.
+// Повторное назначение меняет только роль.
+func (s *Storage) AddResponsible(ctx context.Context, organizationID string, userID int, role string) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.organizations[organizationID]; !ok {
+		return foreignKey("organization", organizationID)
+	}
+	if _, ok := s.state.employees[userID]; !ok {
+		return foreignKey("employee", userID)
+	}
+	s.state.responsibles[responsibleKey{organizationID, userID}] = role
+	return nil
+}
+
+// RemoveResponsible снимает сотрудника с ответственности. ErrNotFound, если он не был назначен.
+func (s *Storage) RemoveResponsible(ctx context.Context, organizationID string, userID int) error {
+	defer s.lock(ctx)()
+	key := responsibleKey{organizationID, userID}
+	if _, ok := s.state.responsibles[key]; !ok {
+		return db.ErrNotFound
+	}
+	delete(s.state.responsibles, key)
+	return nil
+}
+
+func (s *Storage) GetResponsibles(ctx context.Context, organizationID string) ([]db.Responsible, error) {
+	defer s.lock(ctx)()
+	responsibles := []db.Responsible{}
+	for key, role := range s.state.responsibles {
+		if key.organizationID == organizationID {
+			responsibles = append(responsibles, db.Responsible{
+				UserID:         key.userID,
+				Username:       s.state.employees[key.userID].Username,
+				OrganizationID: organizationID,
+				Role:           role,
+			})
+		}
+	}
+	slices.SortFunc(responsibles, func(a, b db.Responsible) int { return cmp.Compare(a.UserID, b.UserID) })
+	return responsibles, nil
+}
+
+func (s *Storage) IsUserResponsibleForOrganization(ctx context.Context, userID int, organizationID string) (bool, error) {
+	defer s.lock(ctx)()
+	_, ok := s.state.responsibles[responsibleKey{organizationID, userID}]
+	return ok, nil
+}
+
+// AddMember добавляет сотрудника в организацию без ответственности. В
+// StorageInterface метода нет: в Postgres состав организаций ведется вне API.
+func (s *Storage) AddMember(ctx context.Context, organizationID string, userID int) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.organizations[organizationID]; !ok {
+		return foreignKey("organization", organizationID)
+	}
+	if _, ok := s.state.employees[userID]; !ok {
+		return foreignKey("employee", userID)
+	}
+	s.state.members[responsibleKey{organizationID, userID}] = true
+	return nil
+}
+
+// IsUserMemberOfOrganization проверяет, что сотрудник состоит в организации.
+// Ответственные считаются сотрудниками организации.
+func (s *Storage) IsUserMemberOfOrganization(ctx context.Context, userID int, organizationID string) (bool, error) {
+	defer s.lock(ctx)()
+	key := responsibleKey{organizationID, userID}
+	_, responsible := s.state.responsibles[key]
+	return responsible || s.state.members[key], nil
+}
+
+// GetApprovalPolicy возвращает политику тендера, а если ее нет - политику организации.
+// Если tenderID == nil, ищется только политика организации. Без политики - ErrNotFound.
+func (s *Storage) GetApprovalPolicy(ctx context.Context, organizationID string, tenderID *string) (*db.ApprovalPolicy, error) {
+	defer s.lock(ctx)()
+	var found *db.ApprovalPolicy
+	for _, p := range s.state.policies {
+		if p.OrganizationID != organizationID {
+			continue
+		}
+		if p.TenderID != nil && tenderID != nil && *p.TenderID == *tenderID {
+			return clonePolicy(p), nil
+		}
+		if p.TenderID == nil {
+			found = clonePolicy(p)
+		}
+	}
+	if found == nil {
+		return nil, db.ErrNotFound
+	}
+	return found, nil
+}
+
+// SaveApprovalPolicy создает или заменяет политику организации (или тендера, если задан TenderID)
+func (s *Storage) SaveApprovalPolicy(ctx context.Context, p *db.ApprovalPolicy) error {
+	defer s.lock(ctx)()
+	st := &s.state
+	if _, ok := st.organizations[p.OrganizationID]; !ok {
+		return foreignKey("organization", p.OrganizationID)
+	}
+	if p.TenderID != nil {
+		if _, ok := st.tenders[*p.TenderID]; !ok {
+			return foreignKey("tender", *p.TenderID)
+		}
+	}
+
+	saved := *clonePolicy(*p)
+	saved.UpdatedAt = now()
+	saved.CreatedAt = saved.UpdatedAt
+	saved.ID = 0
+	for id, existing := range st.policies {
+		sameTender := existing.TenderID != nil && p.TenderID != nil && *existing.TenderID == *p.TenderID
+		sameOrganization := existing.TenderID == nil && p.TenderID == nil && existing.OrganizationID == p.OrganizationID
+		if sameTender || sameOrganization {
+			saved.ID = id
+			saved.OrganizationID = existing.OrganizationID
+			saved.CreatedAt = existing.CreatedAt
+		}
+	}
+	if saved.ID == 0 {
+		saved.ID = st.nextID("approval_policy")
+	}
+	st.policies[saved.ID] = saved
+
+	p.ID, p.CreatedAt, p.UpdatedAt = saved.ID, saved.CreatedAt, saved.UpdatedAt
+	return nil
+}
+
+// DeleteApprovalPolicy удаляет политику организации (tenderID == nil) или тендера
+func (s *Storage) DeleteApprovalPolicy(ctx context.Context, organizationID string, tenderID *string) error {
+	defer s.lock(ctx)()
+	maps.DeleteFunc(s.state.policies, func(_ int, p db.ApprovalPolicy) bool {
+		if p.OrganizationID != organizationID {
+			return false
+		}
+		if tenderID == nil {
+			return p.TenderID == nil
+		}
+		return p.TenderID != nil && *p.TenderID == *tenderID
+	})
+	return nil
+}
+
+// clonePolicy копирует политику, чтобы вызывающий не менял сохраненные TenderID и RoleWeights
+func clonePolicy(p db.ApprovalPolicy) *db.ApprovalPolicy {
+	if p.TenderID != nil {
+		tenderID := *p.TenderID
+		p.TenderID = &tenderID
+	}
+	p.RoleWeights = maps.Clone(p.RoleWeights)
+	if p.RoleWeights == nil {
+		p.RoleWeights = db.RoleWeights{}
+	}
+	return &p
+}
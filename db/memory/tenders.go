@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"tenders/db"
+
+	"github.com/google/uuid"
+)
+
+func (s *Storage) CreateTender(ctx context.Context, t *db.Tender) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.organizations[t.OrganizationID]; !ok {
+		return foreignKey("organization", t.OrganizationID)
+	}
+	t.ID = uuid.NewString()
+	t.Version = 1
+	t.CreatedAt = now()
+	t.UpdatedAt = t.CreatedAt
+	s.state.tenders[t.ID] = *t
+	// Сохраняем первую версию
+	s.state.saveTenderVersion(ctx, *t)
+	return nil
+}
+
+func (s *Storage) GetTender(ctx context.Context, tenderID string) (*db.Tender, error) {
+	defer s.lock(ctx)()
+	t, ok := s.state.tenders[tenderID]
+	if !ok {
+		return nil, db.ErrNotFound
+	}
+	return &t, nil
+}
+
+// LockTender читает тендер. Отдельная блокировка строки не нужна: транзакция
+// WithTx и так держит все хранилище.
+func (s *Storage) LockTender(ctx context.Context, tenderID string) (*db.Tender, error) {
+	return s.GetTender(ctx, tenderID)
+}
+
+// UpdateTender сохраняет тендер, если его версия все еще равна t.Version,
+// и увеличивает версию. Иначе возвращает ErrVersionConflict.
+func (s *Storage) UpdateTender(ctx context.Context, t *db.Tender) error {
+	defer s.lock(ctx)()
+	stored, ok := s.state.tenders[t.ID]
+	if !ok || stored.Version != t.Version {
+		return db.ErrVersionConflict
+	}
+	stored.Name = t.Name
+	stored.Description = t.Description
+	stored.ServiceType = t.ServiceType
+	stored.Status = t.Status
+	stored.Version++
+	stored.UpdatedAt = now()
+	s.state.tenders[t.ID] = stored
+	t.Version = stored.Version
+	// Сохраняем новую версию
+	s.state.saveTenderVersion(ctx, *t)
+	return nil
+}
+
+// ArchiveTender помечает тендер удаленным. Кто удалил тендер, берется из
+// контекста (db.WithAuthor). ErrNotFound, если тендера нет или он уже в архиве.
+func (s *Storage) ArchiveTender(ctx context.Context, t *db.Tender) error {
+	defer s.lock(ctx)()
+	stored, ok := s.state.tenders[t.ID]
+	if !ok || stored.DeletedAt != nil {
+		return db.ErrNotFound
+	}
+	deletedAt := now()
+	stored.DeletedAt, stored.DeletedBy = &deletedAt, db.AuthorFrom(ctx)
+	s.state.tenders[t.ID] = stored
+	t.DeletedAt, t.DeletedBy = stored.DeletedAt, stored.DeletedBy
+	return nil
+}
+
+// RestoreTender возвращает тендер из архива. ErrNotFound, если тендера в архиве нет.
+func (s *Storage) RestoreTender(ctx context.Context, t *db.Tender) error {
+	defer s.lock(ctx)()
+	stored, ok := s.state.tenders[t.ID]
+	if !ok || stored.DeletedAt == nil {
+		return db.ErrNotFound
+	}
+	stored.DeletedAt, stored.DeletedBy = nil, nil
+	s.state.tenders[t.ID] = stored
+	t.DeletedAt, t.DeletedBy = nil, nil
+	return nil
+}
+
+// SaveTenderVersion сохраняет снимок тендера. Автор версии берется из контекста (db.WithAuthor).
+func (s *Storage) SaveTenderVersion(ctx context.Context, t *db.Tender) error {
+	defer s.lock(ctx)()
+	if _, ok := s.state.tenders[t.ID]; !ok {
+		return foreignKey("tender", t.ID)
+	}
+	s.state.saveTenderVersion(ctx, *t)
+	return nil
+}
+
+func (st *state) saveTenderVersion(ctx context.Context, t db.Tender) {
+	t.CreatedAt = now()
+	t.UpdatedAt = time.Time{} // в tender_versions нет updated_at
+	st.tenderVersions = append(st.tenderVersions, tenderVersion{
+		id:       st.nextID("tender_versions"),
+		tender:   t,
+		authorID: db.AuthorFrom(ctx),
+	})
+}
+
+func (s *Storage) GetTenderVersion(ctx context.Context, tenderID string, version int) (*db.Tender, error) {
+	defer s.lock(ctx)()
+	// Последний снимок версии, как ORDER BY id DESC LIMIT 1
+	for _, v := range slices.Backward(s.state.tenderVersions) {
+		if v.tender.ID == tenderID && v.tender.Version == version {
+			t := v.tender
+			return &t, nil
+		}
+	}
+	return nil, db.ErrNotFound
+}
+
+// GetTenderVersions возвращает историю версий тендера, начиная с последней
+func (s *Storage) GetTenderVersions(ctx context.Context, tenderID string, page db.Page) ([]db.Version, int, error) {
+	defer s.lock(ctx)()
+	versions := []db.Version{}
+	for _, v := range s.state.tenderVersions {
+		if v.tender.ID == tenderID {
+			versions = append(versions, s.state.version(v.id, v.tender.Version, v.tender.Status, v.authorID, v.tender.CreatedAt))
+		}
+	}
+	return paginate(versions, page, db.VersionCursor, compareVersions), len(versions), nil
+}
+
+// version - запись истории версий с именем автора
+func (st *state) version(id, version int, status string, authorID *int, createdAt time.Time) db.Version {
+	v := db.Version{ID: id, Version: version, Status: status, AuthorID: authorID, CreatedAt: createdAt}
+	if authorID != nil {
+		if author, ok := st.employees[*authorID]; ok {
+			username := author.Username
+			v.AuthorUsername = &username
+		}
+	}
+	return v
+}
+
+// GetTenders возвращает страницу тендеров, отсортированных по названию, с
+// фильтром по видам услуг, и общее число тендеров под фильтром. Тендеры в
+// архиве не выбираются.
+func (s *Storage) GetTenders(ctx context.Context, serviceTypes []string, page db.Page) ([]db.Tender, int, error) {
+	defer s.lock(ctx)()
+	tenders := []db.Tender{}
+	for _, t := range s.state.tenders {
+		if t.DeletedAt == nil && (len(serviceTypes) == 0 || slices.Contains(serviceTypes, t.ServiceType)) {
+			tenders = append(tenders, t)
+		}
+	}
+	return paginate(tenders, page, db.TenderCursor, compareByName), len(tenders), nil
+}
+
+// GetUserTenders возвращает страницу тендеров организаций, за которые отвечает
+// пользователь, отсортированных по названию, и общее число таких тендеров
+func (s *Storage) GetUserTenders(ctx context.Context, username string, page db.Page) ([]db.Tender, int, error) {
+	defer s.lock(ctx)()
+	tenders := []db.Tender{}
+	e, ok := s.state.employeeByUsername(username)
+	if ok {
+		for _, t := range s.state.tenders {
+			if _, responsible := s.state.responsibles[responsibleKey{t.OrganizationID, e.ID}]; responsible && t.DeletedAt == nil {
+				tenders = append(tenders, t)
+			}
+		}
+	}
+	return paginate(tenders, page, db.TenderCursor, compareByName), len(tenders), nil
+}
+
+// deleteTender удаляет тендер вместе с версиями и политикой. Предложений у
+// тендера уже быть не должно (ON DELETE RESTRICT).
+func (st *state) deleteTender(id string) {
+	delete(st.tenders, id)
+	st.tenderVersions = slices.DeleteFunc(slices.Clone(st.tenderVersions), func(v tenderVersion) bool { return v.tender.ID == id })
+	for policyID, p := range st.policies {
+		if p.TenderID != nil && *p.TenderID == id {
+			delete(st.policies, policyID)
+		}
+	}
+}
+
+// hasBids сообщает, есть ли у тендера предложения
+func (st *state) hasBids(tenderID string) bool {
+	for _, b := range st.bids {
+		if b.TenderID == tenderID {
+			return true
+		}
+	}
+	return false
+}
@@ -1,4 +1,38 @@
 -- +goose Up
+-- Таблицы сотрудников и организаций на развернутой платформе уже есть, а на
+-- чистой базе их создает эта миграция: на них ссылаются тендеры ниже и все
+-- последующие миграции.
+CREATE TABLE IF NOT EXISTS employee (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(50) UNIQUE NOT NULL,
+    first_name VARCHAR(50),
+    last_name VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose StatementBegin
+DO $$ BEGIN
+    CREATE TYPE organization_type AS ENUM ('IE', 'LLC', 'JSC');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+-- +goose StatementEnd
+
+CREATE TABLE IF NOT EXISTS organization (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    type organization_type,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS organization_responsible (
+    id SERIAL PRIMARY KEY,
+    organization_id INT REFERENCES organization(id) ON DELETE CASCADE,
+    user_id INT REFERENCES employee(id) ON DELETE CASCADE
+);
+
 CREATE TYPE tender_status AS ENUM ('Created', 'Published', 'Closed');
 CREATE TYPE tender_service_type AS ENUM ('Construction', 'Delivery', 'Manufacture');
 
@@ -30,3 +64,7 @@ DROP FUNCTION IF EXISTS update_updated_at_column();
 DROP TABLE IF EXISTS tender;
 DROP TYPE IF EXISTS tender_status;
 DROP TYPE IF EXISTS tender_service_type;
+DROP TABLE IF EXISTS organization_responsible;
+DROP TABLE IF EXISTS organization;
+DROP TYPE IF EXISTS organization_type;
+DROP TABLE IF EXISTS employee;
@@ -1,7 +1,5 @@
 -- +goose Up
-CREATE TYPE tender_status AS ENUM ('Created', 'Published', 'Closed');
-CREATE TYPE tender_service_type AS ENUM ('Construction', 'Delivery', 'Manufacture');
-
+-- Типы tender_status и tender_service_type созданы в 0001
 CREATE TABLE tender_versions (
     id SERIAL PRIMARY KEY,
     tender_id INT NOT NULL REFERENCES tender(id) ON DELETE CASCADE,
@@ -0,0 +1,22 @@
+-- +goose Up
+ALTER TABLE organization_responsible ADD COLUMN IF NOT EXISTS role VARCHAR(50) NOT NULL DEFAULT 'member';
+
+CREATE TABLE approval_policy (
+    id SERIAL PRIMARY KEY,
+    organization_id INT NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    tender_id INT REFERENCES tender(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    required INT NOT NULL DEFAULT 0,
+    reject_threshold INT NOT NULL DEFAULT 1,
+    role_weights JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Одна политика на организацию и одна на каждый тендер
+CREATE UNIQUE INDEX approval_policy_organization_uniq ON approval_policy (organization_id) WHERE tender_id IS NULL;
+CREATE UNIQUE INDEX approval_policy_tender_uniq ON approval_policy (tender_id) WHERE tender_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS approval_policy;
+ALTER TABLE organization_responsible DROP COLUMN IF EXISTS role;
@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE bid_decision ADD COLUMN comment VARCHAR(1000) NOT NULL DEFAULT '';
+
+-- Голоса, которые были переписаны новым решением или отозваны
+CREATE TABLE bid_decision_history (
+    id SERIAL PRIMARY KEY,
+    bid_id INT NOT NULL REFERENCES bid(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    decision VARCHAR(20) NOT NULL,
+    comment VARCHAR(1000) NOT NULL DEFAULT '',
+    decided_at TIMESTAMP,
+    action VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX bid_decision_history_bid_idx ON bid_decision_history (bid_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS bid_decision_history;
+ALTER TABLE bid_decision DROP COLUMN IF EXISTS comment;
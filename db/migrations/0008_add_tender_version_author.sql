@@ -0,0 +1,9 @@
+-- +goose Up
+-- Кто внес изменение, сохраненное в версии (NULL для версий, записанных до миграции)
+ALTER TABLE tender_versions ADD COLUMN author_id INT REFERENCES employee(id) ON DELETE SET NULL;
+
+CREATE INDEX tender_versions_tender_version_idx ON tender_versions (tender_id, version);
+
+-- +goose Down
+DROP INDEX IF EXISTS tender_versions_tender_version_idx;
+ALTER TABLE tender_versions DROP COLUMN IF EXISTS author_id;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Кто внес изменение, сохраненное в версии (NULL для версий, записанных до миграции)
+ALTER TABLE bid_versions ADD COLUMN author_id INT REFERENCES employee(id) ON DELETE SET NULL;
+
+CREATE INDEX bid_versions_bid_version_idx ON bid_versions (bid_id, version);
+
+-- Раньше CreateBid и UpdateBid не писали версии: сохраняем текущее состояние
+-- предложений без истории, чтобы к нему можно было откатиться
+INSERT INTO bid_versions
+    (bid_id, name, description, status, tender_id, organization_id, creator_username, version, created_at)
+SELECT b.id, b.name, b.description, b.status, b.tender_id, b.organization_id, b.creator_username, b.version,
+       COALESCE(b.updated_at, b.created_at, CURRENT_TIMESTAMP)
+FROM bid b
+WHERE NOT EXISTS (SELECT 1 FROM bid_versions bv WHERE bv.bid_id = b.id AND bv.version = b.version);
+
+-- +goose Down
+DROP INDEX IF EXISTS bid_versions_bid_version_idx;
+ALTER TABLE bid_versions DROP COLUMN IF EXISTS author_id;
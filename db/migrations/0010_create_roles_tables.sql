@@ -0,0 +1,20 @@
+-- +goose Up
+-- Роли уровня площадки: администратор и аудитор (только чтение)
+CREATE TABLE employee_platform_role (
+    user_id INT PRIMARY KEY REFERENCES employee(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('admin', 'auditor')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Сотрудники организации, не являющиеся ответственными
+CREATE TABLE organization_member (
+    id SERIAL PRIMARY KEY,
+    organization_id INT NOT NULL REFERENCES organization(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES employee(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (organization_id, user_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS organization_member;
+DROP TABLE IF EXISTS employee_platform_role;
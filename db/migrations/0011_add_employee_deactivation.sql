@@ -0,0 +1,9 @@
+-- +goose Up
+-- Деактивированный сотрудник не может войти, но остается в истории голосов и версий
+ALTER TABLE employee ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS employee_name_idx ON employee (lower(first_name), lower(last_name));
+
+-- +goose Down
+DROP INDEX IF EXISTS employee_name_idx;
+ALTER TABLE employee DROP COLUMN IF EXISTS deactivated_at;
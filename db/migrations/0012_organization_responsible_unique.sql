@@ -0,0 +1,11 @@
+-- +goose Up
+-- Сотрудник может быть ответственным за организацию только один раз
+DELETE FROM organization_responsible a
+USING organization_responsible b
+WHERE a.organization_id = b.organization_id AND a.user_id = b.user_id AND a.id > b.id;
+
+ALTER TABLE organization_responsible
+    ADD CONSTRAINT organization_responsible_org_user_key UNIQUE (organization_id, user_id);
+
+-- +goose Down
+ALTER TABLE organization_responsible DROP CONSTRAINT IF EXISTS organization_responsible_org_user_key;
@@ -0,0 +1,206 @@
+-- +goose Up
+-- Идентификаторы организаций, тендеров и предложений становятся UUID, как в
+-- openapi.yml. Строки сохраняются: прежний целый id остается в legacy_id, и по
+-- нему API находит записи, на которые ссылаются старые клиенты.
+
+ALTER TABLE organization ADD COLUMN uuid UUID NOT NULL DEFAULT gen_random_uuid();
+ALTER TABLE tender ADD COLUMN uuid UUID NOT NULL DEFAULT gen_random_uuid();
+ALTER TABLE bid ADD COLUMN uuid UUID NOT NULL DEFAULT gen_random_uuid();
+
+-- Заменяет целую ссылку child.col на parent.id ссылкой на parent.uuid.
+-- Вместе с колонкой удаляются ее внешние ключи и индексы, они создаются ниже.
+-- +goose StatementBegin
+CREATE FUNCTION pg_temp.reference_uuid(child TEXT, col TEXT, parent TEXT) RETURNS void AS $$
+BEGIN
+    EXECUTE format('ALTER TABLE %I ADD COLUMN %I UUID', child, col || '_uuid');
+    EXECUTE format('UPDATE %I c SET %I = p.uuid FROM %I p WHERE p.id = c.%I', child, col || '_uuid', parent, col);
+    EXECUTE format('ALTER TABLE %I DROP COLUMN %I', child, col);
+    EXECUTE format('ALTER TABLE %I RENAME COLUMN %I TO %I', child, col || '_uuid', col);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+SELECT pg_temp.reference_uuid('organization_responsible', 'organization_id', 'organization');
+SELECT pg_temp.reference_uuid('organization_member', 'organization_id', 'organization');
+SELECT pg_temp.reference_uuid('approval_policy', 'organization_id', 'organization');
+SELECT pg_temp.reference_uuid('tender', 'organization_id', 'organization');
+SELECT pg_temp.reference_uuid('tender_versions', 'organization_id', 'organization');
+SELECT pg_temp.reference_uuid('bid', 'organization_id', 'organization');
+SELECT pg_temp.reference_uuid('bid_versions', 'organization_id', 'organization');
+SELECT pg_temp.reference_uuid('approval_policy', 'tender_id', 'tender');
+SELECT pg_temp.reference_uuid('tender_versions', 'tender_id', 'tender');
+SELECT pg_temp.reference_uuid('bid', 'tender_id', 'tender');
+SELECT pg_temp.reference_uuid('bid_versions', 'tender_id', 'tender');
+SELECT pg_temp.reference_uuid('bid_versions', 'bid_id', 'bid');
+SELECT pg_temp.reference_uuid('bid_review', 'bid_id', 'bid');
+SELECT pg_temp.reference_uuid('bid_decision', 'bid_id', 'bid');
+SELECT pg_temp.reference_uuid('bid_decision_history', 'bid_id', 'bid');
+
+DROP FUNCTION pg_temp.reference_uuid(TEXT, TEXT, TEXT);
+
+-- Прежний id остается уникальным, но новые записи его не получают
+ALTER TABLE organization DROP CONSTRAINT organization_pkey;
+ALTER TABLE organization RENAME COLUMN id TO legacy_id;
+ALTER TABLE organization ALTER COLUMN legacy_id DROP DEFAULT, ALTER COLUMN legacy_id DROP NOT NULL;
+ALTER TABLE organization ADD CONSTRAINT organization_legacy_id_key UNIQUE (legacy_id);
+ALTER TABLE organization RENAME COLUMN uuid TO id;
+ALTER TABLE organization ADD PRIMARY KEY (id);
+
+ALTER TABLE tender DROP CONSTRAINT tender_pkey;
+ALTER TABLE tender RENAME COLUMN id TO legacy_id;
+ALTER TABLE tender ALTER COLUMN legacy_id DROP DEFAULT, ALTER COLUMN legacy_id DROP NOT NULL;
+ALTER TABLE tender ADD CONSTRAINT tender_legacy_id_key UNIQUE (legacy_id);
+ALTER TABLE tender RENAME COLUMN uuid TO id;
+ALTER TABLE tender ADD PRIMARY KEY (id);
+
+ALTER TABLE bid DROP CONSTRAINT bid_pkey;
+ALTER TABLE bid RENAME COLUMN id TO legacy_id;
+ALTER TABLE bid ALTER COLUMN legacy_id DROP DEFAULT, ALTER COLUMN legacy_id DROP NOT NULL;
+ALTER TABLE bid ADD CONSTRAINT bid_legacy_id_key UNIQUE (legacy_id);
+ALTER TABLE bid RENAME COLUMN uuid TO id;
+ALTER TABLE bid ADD PRIMARY KEY (id);
+
+-- Ограничения и индексы ссылок, как в миграциях 0001-0012
+ALTER TABLE organization_responsible
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE,
+    ADD CONSTRAINT organization_responsible_org_user_key UNIQUE (organization_id, user_id);
+ALTER TABLE organization_member
+    ALTER COLUMN organization_id SET NOT NULL,
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE,
+    ADD UNIQUE (organization_id, user_id);
+ALTER TABLE approval_policy
+    ALTER COLUMN organization_id SET NOT NULL,
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE,
+    ADD FOREIGN KEY (tender_id) REFERENCES tender(id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX approval_policy_organization_uniq ON approval_policy (organization_id) WHERE tender_id IS NULL;
+CREATE UNIQUE INDEX approval_policy_tender_uniq ON approval_policy (tender_id) WHERE tender_id IS NOT NULL;
+ALTER TABLE tender
+    ALTER COLUMN organization_id SET NOT NULL,
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE;
+ALTER TABLE tender_versions
+    ALTER COLUMN organization_id SET NOT NULL,
+    ALTER COLUMN tender_id SET NOT NULL,
+    ADD FOREIGN KEY (tender_id) REFERENCES tender(id) ON DELETE CASCADE;
+CREATE INDEX tender_versions_tender_version_idx ON tender_versions (tender_id, version);
+ALTER TABLE bid
+    ALTER COLUMN organization_id SET NOT NULL,
+    ALTER COLUMN tender_id SET NOT NULL,
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE,
+    ADD FOREIGN KEY (tender_id) REFERENCES tender(id) ON DELETE CASCADE;
+ALTER TABLE bid_versions
+    ALTER COLUMN organization_id SET NOT NULL,
+    ALTER COLUMN tender_id SET NOT NULL,
+    ALTER COLUMN bid_id SET NOT NULL,
+    ADD FOREIGN KEY (bid_id) REFERENCES bid(id) ON DELETE CASCADE;
+CREATE INDEX bid_versions_bid_version_idx ON bid_versions (bid_id, version);
+ALTER TABLE bid_review
+    ALTER COLUMN bid_id SET NOT NULL,
+    ADD FOREIGN KEY (bid_id) REFERENCES bid(id) ON DELETE CASCADE;
+ALTER TABLE bid_decision
+    ALTER COLUMN bid_id SET NOT NULL,
+    ADD FOREIGN KEY (bid_id) REFERENCES bid(id) ON DELETE CASCADE,
+    ADD UNIQUE (bid_id, user_id);
+ALTER TABLE bid_decision_history
+    ALTER COLUMN bid_id SET NOT NULL,
+    ADD FOREIGN KEY (bid_id) REFERENCES bid(id) ON DELETE CASCADE;
+CREATE INDEX bid_decision_history_bid_idx ON bid_decision_history (bid_id);
+
+-- +goose Down
+-- Записи, созданные после перехода на UUID, получают новые целые id
+UPDATE organization SET legacy_id = nextval('organization_id_seq') WHERE legacy_id IS NULL;
+UPDATE tender SET legacy_id = nextval('tender_id_seq') WHERE legacy_id IS NULL;
+UPDATE bid SET legacy_id = nextval('bid_id_seq') WHERE legacy_id IS NULL;
+
+-- +goose StatementBegin
+CREATE FUNCTION pg_temp.reference_legacy_id(child TEXT, col TEXT, parent TEXT) RETURNS void AS $$
+BEGIN
+    EXECUTE format('ALTER TABLE %I ADD COLUMN %I INT', child, col || '_legacy');
+    EXECUTE format('UPDATE %I c SET %I = p.legacy_id FROM %I p WHERE p.id = c.%I', child, col || '_legacy', parent, col);
+    EXECUTE format('ALTER TABLE %I DROP COLUMN %I', child, col);
+    EXECUTE format('ALTER TABLE %I RENAME COLUMN %I TO %I', child, col || '_legacy', col);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+SELECT pg_temp.reference_legacy_id('organization_responsible', 'organization_id', 'organization');
+SELECT pg_temp.reference_legacy_id('organization_member', 'organization_id', 'organization');
+SELECT pg_temp.reference_legacy_id('approval_policy', 'organization_id', 'organization');
+SELECT pg_temp.reference_legacy_id('tender', 'organization_id', 'organization');
+SELECT pg_temp.reference_legacy_id('tender_versions', 'organization_id', 'organization');
+SELECT pg_temp.reference_legacy_id('bid', 'organization_id', 'organization');
+SELECT pg_temp.reference_legacy_id('bid_versions', 'organization_id', 'organization');
+SELECT pg_temp.reference_legacy_id('approval_policy', 'tender_id', 'tender');
+SELECT pg_temp.reference_legacy_id('tender_versions', 'tender_id', 'tender');
+SELECT pg_temp.reference_legacy_id('bid', 'tender_id', 'tender');
+SELECT pg_temp.reference_legacy_id('bid_versions', 'tender_id', 'tender');
+SELECT pg_temp.reference_legacy_id('bid_versions', 'bid_id', 'bid');
+SELECT pg_temp.reference_legacy_id('bid_review', 'bid_id', 'bid');
+SELECT pg_temp.reference_legacy_id('bid_decision', 'bid_id', 'bid');
+SELECT pg_temp.reference_legacy_id('bid_decision_history', 'bid_id', 'bid');
+
+DROP FUNCTION pg_temp.reference_legacy_id(TEXT, TEXT, TEXT);
+
+ALTER TABLE organization DROP CONSTRAINT organization_pkey;
+ALTER TABLE organization DROP CONSTRAINT organization_legacy_id_key;
+ALTER TABLE organization DROP COLUMN id;
+ALTER TABLE organization RENAME COLUMN legacy_id TO id;
+ALTER TABLE organization ALTER COLUMN id SET NOT NULL, ALTER COLUMN id SET DEFAULT nextval('organization_id_seq');
+ALTER TABLE organization ADD PRIMARY KEY (id);
+
+ALTER TABLE tender DROP CONSTRAINT tender_pkey;
+ALTER TABLE tender DROP CONSTRAINT tender_legacy_id_key;
+ALTER TABLE tender DROP COLUMN id;
+ALTER TABLE tender RENAME COLUMN legacy_id TO id;
+ALTER TABLE tender ALTER COLUMN id SET NOT NULL, ALTER COLUMN id SET DEFAULT nextval('tender_id_seq');
+ALTER TABLE tender ADD PRIMARY KEY (id);
+
+ALTER TABLE bid DROP CONSTRAINT bid_pkey;
+ALTER TABLE bid DROP CONSTRAINT bid_legacy_id_key;
+ALTER TABLE bid DROP COLUMN id;
+ALTER TABLE bid RENAME COLUMN legacy_id TO id;
+ALTER TABLE bid ALTER COLUMN id SET NOT NULL, ALTER COLUMN id SET DEFAULT nextval('bid_id_seq');
+ALTER TABLE bid ADD PRIMARY KEY (id);
+
+ALTER TABLE organization_responsible
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE,
+    ADD CONSTRAINT organization_responsible_org_user_key UNIQUE (organization_id, user_id);
+ALTER TABLE organization_member
+    ALTER COLUMN organization_id SET NOT NULL,
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE,
+    ADD UNIQUE (organization_id, user_id);
+ALTER TABLE approval_policy
+    ALTER COLUMN organization_id SET NOT NULL,
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE,
+    ADD FOREIGN KEY (tender_id) REFERENCES tender(id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX approval_policy_organization_uniq ON approval_policy (organization_id) WHERE tender_id IS NULL;
+CREATE UNIQUE INDEX approval_policy_tender_uniq ON approval_policy (tender_id) WHERE tender_id IS NOT NULL;
+ALTER TABLE tender
+    ALTER COLUMN organization_id SET NOT NULL,
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE;
+ALTER TABLE tender_versions
+    ALTER COLUMN organization_id SET NOT NULL,
+    ALTER COLUMN tender_id SET NOT NULL,
+    ADD FOREIGN KEY (tender_id) REFERENCES tender(id) ON DELETE CASCADE;
+CREATE INDEX tender_versions_tender_version_idx ON tender_versions (tender_id, version);
+ALTER TABLE bid
+    ALTER COLUMN organization_id SET NOT NULL,
+    ALTER COLUMN tender_id SET NOT NULL,
+    ADD FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE,
+    ADD FOREIGN KEY (tender_id) REFERENCES tender(id) ON DELETE CASCADE;
+ALTER TABLE bid_versions
+    ALTER COLUMN organization_id SET NOT NULL,
+    ALTER COLUMN tender_id SET NOT NULL,
+    ALTER COLUMN bid_id SET NOT NULL,
+    ADD FOREIGN KEY (bid_id) REFERENCES bid(id) ON DELETE CASCADE;
+CREATE INDEX bid_versions_bid_version_idx ON bid_versions (bid_id, version);
+ALTER TABLE bid_review
+    ALTER COLUMN bid_id SET NOT NULL,
+    ADD FOREIGN KEY (bid_id) REFERENCES bid(id) ON DELETE CASCADE;
+ALTER TABLE bid_decision
+    ALTER COLUMN bid_id SET NOT NULL,
+    ADD FOREIGN KEY (bid_id) REFERENCES bid(id) ON DELETE CASCADE,
+    ADD UNIQUE (bid_id, user_id);
+ALTER TABLE bid_decision_history
+    ALTER COLUMN bid_id SET NOT NULL,
+    ADD FOREIGN KEY (bid_id) REFERENCES bid(id) ON DELETE CASCADE;
+CREATE INDEX bid_decision_history_bid_idx ON bid_decision_history (bid_id);
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Тендеры, предложения и отзывы не удаляются, а помечаются удаленными: записи
+-- закупок вместе с версиями и голосами хранятся до очистки архива
+ALTER TABLE tender
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS deleted_by INT REFERENCES employee(id) ON DELETE SET NULL;
+ALTER TABLE bid
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS deleted_by INT REFERENCES employee(id) ON DELETE SET NULL;
+ALTER TABLE bid_review
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS deleted_by INT REFERENCES employee(id) ON DELETE SET NULL;
+
+-- Очистка архива ищет удаленные записи по времени удаления
+CREATE INDEX IF NOT EXISTS tender_deleted_at_idx ON tender (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS bid_deleted_at_idx ON bid (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS bid_review_deleted_at_idx ON bid_review (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS bid_review_deleted_at_idx;
+DROP INDEX IF EXISTS bid_deleted_at_idx;
+DROP INDEX IF EXISTS tender_deleted_at_idx;
+ALTER TABLE bid_review DROP COLUMN IF EXISTS deleted_by, DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE bid DROP COLUMN IF EXISTS deleted_by, DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE tender DROP COLUMN IF EXISTS deleted_by, DROP COLUMN IF EXISTS deleted_at;
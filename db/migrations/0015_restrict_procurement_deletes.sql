@@ -0,0 +1,21 @@
+-- +goose Up
+-- Записи закупок удаляются только очисткой архива: организацию с тендерами
+-- или предложениями удалить нельзя, а тендер - пока у него есть предложения
+ALTER TABLE tender
+    DROP CONSTRAINT tender_organization_id_fkey,
+    ADD CONSTRAINT tender_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE RESTRICT;
+ALTER TABLE bid
+    DROP CONSTRAINT bid_organization_id_fkey,
+    ADD CONSTRAINT bid_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE RESTRICT,
+    DROP CONSTRAINT bid_tender_id_fkey,
+    ADD CONSTRAINT bid_tender_id_fkey FOREIGN KEY (tender_id) REFERENCES tender(id) ON DELETE RESTRICT;
+
+-- +goose Down
+ALTER TABLE bid
+    DROP CONSTRAINT bid_tender_id_fkey,
+    ADD CONSTRAINT bid_tender_id_fkey FOREIGN KEY (tender_id) REFERENCES tender(id) ON DELETE CASCADE,
+    DROP CONSTRAINT bid_organization_id_fkey,
+    ADD CONSTRAINT bid_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE;
+ALTER TABLE tender
+    DROP CONSTRAINT tender_organization_id_fkey,
+    ADD CONSTRAINT tender_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organization(id) ON DELETE CASCADE;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- История голосов - аудит: удаление сотрудника не должно ее стирать.
+-- Сотрудника с историей голосов не удаляют, а деактивируют.
+ALTER TABLE bid_decision_history
+    DROP CONSTRAINT bid_decision_history_user_id_fkey,
+    ADD CONSTRAINT bid_decision_history_user_id_fkey FOREIGN KEY (user_id) REFERENCES employee(id) ON DELETE RESTRICT;
+
+-- +goose Down
+ALTER TABLE bid_decision_history
+    DROP CONSTRAINT bid_decision_history_user_id_fkey,
+    ADD CONSTRAINT bid_decision_history_user_id_fkey FOREIGN KEY (user_id) REFERENCES employee(id) ON DELETE CASCADE;
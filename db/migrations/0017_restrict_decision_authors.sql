@@ -0,0 +1,11 @@
+-- +goose Up
+-- Действующие голоса, как и их история, остаются за автором: сотрудника,
+-- который голосовал, не удаляют, а деактивируют.
+ALTER TABLE bid_decision
+    DROP CONSTRAINT bid_decision_user_id_fkey,
+    ADD CONSTRAINT bid_decision_user_id_fkey FOREIGN KEY (user_id) REFERENCES employee(id) ON DELETE RESTRICT;
+
+-- +goose Down
+ALTER TABLE bid_decision
+    DROP CONSTRAINT bid_decision_user_id_fkey,
+    ADD CONSTRAINT bid_decision_user_id_fkey FOREIGN KEY (user_id) REFERENCES employee(id) ON DELETE CASCADE;
@@ -1,31 +1,77 @@
+// Package migrations содержит миграции схемы базы, встроенные в бинарник.
 package migrations
 
 import (
+	"context"
 	"database/sql"
+	"embed"
+	"errors"
 	"fmt"
-	"log"
-	"os"
 
-	_ "github.com/lib/pq"
 	"github.com/pressly/goose/v3"
 )
 
-// Run выполняет все миграции из папки ./migrations
-func Run() {
-	db, err := sql.Open("postgres", os.Getenv("POSTGRES_CONN"))
+//go:embed *.sql
+var files embed.FS
+
+// ErrSchemaBehind - в базе применены не все миграции
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// Migrator применяет встроенные миграции к базе через соединение сервера
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// New создает Migrator для базы Postgres db. Соединение остается за
+// вызывающим: Migrator его не закрывает.
+func New(db *sql.DB) (*Migrator, error) {
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, files)
 	if err != nil {
-		log.Fatalf("failed to open db: %v", err)
+		return nil, fmt.Errorf("load migrations: %w", err)
 	}
-	defer db.Close()
+	return &Migrator{provider: provider}, nil
+}
+
+// Up применяет все непримененные миграции и возвращает их результаты
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down откатывает последнюю примененную миграцию
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return m.provider.Down(ctx)
+}
 
-	if err := goose.SetDialect("postgres"); err != nil {
-		log.Fatalf("failed to set dialect: %v", err)
+// Redo откатывает и заново применяет последнюю примененную миграцию
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	down, err := m.provider.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+	up, err := m.provider.ApplyVersion(ctx, down.Source.Version, true)
+	if err != nil {
+		return []*goose.MigrationResult{down}, err
 	}
+	return []*goose.MigrationResult{down, up}, nil
+}
 
-	migrationDir := "./migrations" // или полный путь к вашим миграциям
+// Status возвращает состояние каждой миграции в порядке версий
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
 
-	fmt.Printf("Running migrations from %s\n", migrationDir)
-	if err := goose.Up(db, migrationDir); err != nil {
-		log.Fatalf("failed to run migrations: %v", err)
+// Check возвращает ErrSchemaBehind, если в базе применены не все миграции
+func (m *Migrator) Check(ctx context.Context) error {
+	current, target, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		return err
+	}
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return fmt.Errorf("%w: version %d, latest %d", ErrSchemaBehind, current, target)
 	}
+	return nil
 }
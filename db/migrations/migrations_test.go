@@ -0,0 +1,106 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourcesAreSequential(t *testing.T) {
+	// Соединение не открывается, пока не выполняется запрос
+	conn, err := sql.Open("postgres", "")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	m, err := New(conn)
+	require.NoError(t, err)
+	sources := m.provider.ListSources()
+	require.NotEmpty(t, sources)
+	for i, source := range sources {
+		require.Equal(t, int64(i+1), source.Version, source.Path)
+	}
+}
+
+func TestMigrationsHaveUpAndDown(t *testing.T) {
+	names, err := fs.Glob(files, "*.sql")
+	require.NoError(t, err)
+	for _, name := range names {
+		data, err := fs.ReadFile(files, name)
+		require.NoError(t, err)
+		require.Contains(t, string(data), "-- +goose Up", name)
+		require.Contains(t, string(data), "-- +goose Down", name)
+	}
+}
+
+// Повторный CREATE TYPE без проверки ломает миграцию чистой базы
+func TestTypesCreatedOnce(t *testing.T) {
+	createType := regexp.MustCompile(`(?im)^\s*CREATE TYPE (\w+)`)
+	created := map[string]string{}
+
+	names, err := fs.Glob(files, "*.sql")
+	require.NoError(t, err)
+	for _, name := range names {
+		data, err := fs.ReadFile(files, name)
+		require.NoError(t, err)
+		up, _, _ := strings.Cut(string(data), "-- +goose Down")
+		for _, match := range createType.FindAllStringSubmatch(up, -1) {
+			first, ok := created[match[1]]
+			require.False(t, ok, "type %s is created in %s and %s", match[1], first, name)
+			created[match[1]] = name
+		}
+	}
+}
+
+// TestUpDownUp применяет все миграции к пустой схеме, откатывает их до версии
+// 0 и применяет снова. Нужна локальная база в POSTGRES_CONN: тест работает в
+// отдельной схеме и удаляет ее в конце.
+func TestUpDownUp(t *testing.T) {
+	connString := os.Getenv("POSTGRES_CONN")
+	if connString == "" {
+		t.Skip("POSTGRES_CONN is not set")
+	}
+	conn, err := sql.Open("postgres", connString)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	// search_path задается на соединении, поэтому оно должно быть одно
+	conn.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("migrations_test_%d", time.Now().UnixNano())
+	_, err = conn.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Exec("DROP SCHEMA " + schema + " CASCADE") })
+	_, err = conn.ExecContext(ctx, "SET search_path TO "+schema+", public")
+	require.NoError(t, err)
+
+	m, err := New(conn)
+	require.NoError(t, err)
+	sources := m.provider.ListSources()
+	latest := sources[len(sources)-1].Version
+
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+	require.NoError(t, m.Check(ctx))
+
+	_, err = m.provider.DownTo(ctx, 0)
+	require.NoError(t, err)
+	current, err := m.provider.GetDBVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), current)
+
+	results, err := m.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, len(sources))
+	current, err = m.provider.GetDBVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, latest, current)
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Page - параметры выборки страницы списка. Если задан Cursor, строки
+// выбираются по ключу сортировки относительно курсора (keyset), а Offset
+// не используется.
+type Page struct {
+	Limit  int
+	Offset int
+	Cursor *Cursor
+}
+
+// Cursor - позиция в списке: ключ сортировки строки, относительно которой
+// выбирается страница. Из ключа заполняется только поле, по которому
+// сортируется список: Name у тендеров, организаций и сотрудников (username),
+// CreatedAt у предложений, Version у истории версий. ID - UUID строки, а у
+// сотрудников и версий - целый id в десятичной записи.
+type Cursor struct {
+	Name      string
+	CreatedAt time.Time
+	Version   int
+	ID        string
+	// Before - страница перед строкой курсора, иначе после нее
+	Before bool
+}
+
+// TenderCursor возвращает курсор, указывающий на тендер t
+func TenderCursor(t Tender) Cursor {
+	return Cursor{Name: t.Name, ID: t.ID}
+}
+
+// BidCursor возвращает курсор, указывающий на предложение b
+func BidCursor(b Bid) Cursor {
+	return Cursor{CreatedAt: b.CreatedAt, ID: b.ID}
+}
+
+// OrganizationCursor возвращает курсор, указывающий на организацию o
+func OrganizationCursor(o Organization) Cursor {
+	return Cursor{Name: o.Name, ID: o.ID}
+}
+
+// EmployeeCursor возвращает курсор, указывающий на сотрудника e
+func EmployeeCursor(e Employee) Cursor {
+	return Cursor{Name: e.Username, ID: strconv.Itoa(e.ID)}
+}
+
+// VersionCursor возвращает курсор, указывающий на запись истории версий v
+func VersionCursor(v Version) Cursor {
+	return Cursor{Version: v.Version, ID: strconv.Itoa(v.ID)}
+}
+
+// keyset - порядок сортировки списка: сначала по first, затем по id, чтобы
+// порядок был однозначным. По нему строится выборка страницы после или
+// перед курсором.
+type keyset struct {
+	first string
+	id    string
+	// idType - тип id в SQL, по умолчанию uuid
+	idType string
+	desc   bool
+	// key возвращает значение first в строке курсора и его тип в SQL
+	key func(c *Cursor) (interface{}, string)
+}
+
+var (
+	tenderOrder = keyset{
+		first: "name", id: "id",
+		key: func(c *Cursor) (interface{}, string) { return c.Name, "text" },
+	}
+	bidOrder = keyset{
+		first: "created_at", id: "id", desc: true,
+		key: func(c *Cursor) (interface{}, string) { return c.CreatedAt.UTC(), "timestamp" },
+	}
+	organizationOrder = keyset{
+		first: "name", id: "id",
+		key: func(c *Cursor) (interface{}, string) { return c.Name, "text" },
+	}
+	employeeOrder = keyset{
+		first: "username", id: "id", idType: "int",
+		key: func(c *Cursor) (interface{}, string) { return c.Name, "text" },
+	}
+	// versionOrder - история версий, от последней к первой
+	versionOrder = keyset{
+		first: "version", id: "id", idType: "int", desc: true,
+		key: func(c *Cursor) (interface{}, string) { return c.Version, "int" },
+	}
+)
+
+// on возвращает тот же порядок для таблицы под псевдонимом alias
+func (k keyset) on(alias string) keyset {
+	k.first = alias + "." + k.first
+	k.id = alias + "." + k.id
+	return k
+}
+
+// paginate дописывает к query, который заканчивается условием WHERE,
+// условие курсора, ORDER BY и LIMIT страницы page. Страница перед курсором
+// выбирается в обратном порядке: тогда reversed = true, и строки нужно
+// развернуть.
+func (k keyset) paginate(query string, args []interface{}, page Page) (_ string, _ []interface{}, reversed bool) {
+	order, cmp := "ASC", ">"
+	if k.desc {
+		order, cmp = "DESC", "<"
+	}
+	if page.Cursor != nil {
+		if page.Cursor.Before {
+			reversed = true
+			if k.desc {
+				order, cmp = "ASC", ">"
+			} else {
+				order, cmp = "DESC", "<"
+			}
+		}
+		key, typ := k.key(page.Cursor)
+		idType := k.idType
+		if idType == "" {
+			idType = "uuid"
+		}
+		args = append(args, key, page.Cursor.ID)
+		query += fmt.Sprintf(" AND (%s, %s) %s ($%d::%s, $%d::%s)", k.first, k.id, cmp, len(args)-1, typ, len(args), idType)
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT %d", k.first, order, k.id, order, page.Limit)
+	if page.Cursor == nil && page.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", page.Offset)
+	}
+	return query, args, reversed
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeysetPaginate(t *testing.T) {
+	const base = "SELECT * FROM tender WHERE service_type = $1"
+	const id = "550e8400-e29b-41d4-a716-446655440000"
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	tests := []struct {
+		name     string
+		order    keyset
+		page     Page
+		query    string
+		args     []interface{}
+		reversed bool
+	}{
+		{
+			name:  "offset",
+			order: tenderOrder,
+			page:  Page{Limit: 6, Offset: 10},
+			query: base + " ORDER BY name ASC, id ASC LIMIT 6 OFFSET 10",
+			args:  []interface{}{"Delivery"},
+		},
+		{
+			name:  "after cursor ignores offset",
+			order: tenderOrder,
+			page:  Page{Limit: 6, Offset: 10, Cursor: &Cursor{Name: "Tender", ID: id}},
+			query: base + " AND (name, id) > ($2::text, $3::uuid) ORDER BY name ASC, id ASC LIMIT 6",
+			args:  []interface{}{"Delivery", "Tender", id},
+		},
+		{
+			name:     "before cursor",
+			order:    tenderOrder.on("t"),
+			page:     Page{Limit: 6, Cursor: &Cursor{Name: "Tender", ID: id, Before: true}},
+			query:    base + " AND (t.name, t.id) < ($2::text, $3::uuid) ORDER BY t.name DESC, t.id DESC LIMIT 6",
+			args:     []interface{}{"Delivery", "Tender", id},
+			reversed: true,
+		},
+		{
+			name:  "descending after cursor",
+			order: bidOrder.on("b"),
+			page:  Page{Limit: 6, Cursor: &Cursor{CreatedAt: created, ID: id}},
+			query: base + " AND (b.created_at, b.id) < ($2::timestamp, $3::uuid) ORDER BY b.created_at DESC, b.id DESC LIMIT 6",
+			args:  []interface{}{"Delivery", created.UTC(), id},
+		},
+		{
+			name:     "descending before cursor",
+			order:    bidOrder,
+			page:     Page{Limit: 6, Cursor: &Cursor{CreatedAt: created, ID: id, Before: true}},
+			query:    base + " AND (created_at, id) > ($2::timestamp, $3::uuid) ORDER BY created_at ASC, id ASC LIMIT 6",
+			args:     []interface{}{"Delivery", created.UTC(), id},
+			reversed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, reversed := tt.order.paginate(base, []interface{}{"Delivery"}, tt.page)
+			require.Equal(t, tt.query, query)
+			require.Equal(t, tt.args, args)
+			require.Equal(t, tt.reversed, reversed)
+		})
+	}
+}
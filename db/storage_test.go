@@ -0,0 +1,37 @@
+package db_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"tenders/db"
+	"tenders/db/migrations"
+	"tenders/db/storagetest"
+	"tenders/internal/handlers"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConformance проверяет db.Storage общими тестами контракта. Нужна
+// локальная база в POSTGRES_CONN: недостающие миграции применяются, тесты
+// добавляют строки с уникальными именами и не удаляют их.
+func TestConformance(t *testing.T) {
+	connString := os.Getenv("POSTGRES_CONN")
+	if connString == "" {
+		t.Skip("POSTGRES_CONN is not set")
+	}
+	conn, err := sqlx.Connect("postgres", connString)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	migrator, err := migrations.New(conn.DB)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	store := db.NewStorage(conn)
+	storagetest.Run(t, func(t *testing.T) handlers.StorageInterface { return store })
+}
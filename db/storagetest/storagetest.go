@@ -0,0 +1,645 @@
+// Package storagetest - общие тесты контракта хранилища. Run проверяет любую
+// реализацию handlers.StorageInterface, чтобы db.Storage (Postgres) и
+// memory.Storage вели себя одинаково: нумерация версий, снимки, видимость
+// предложений, голоса, порядок и курсоры страниц, архив, ошибки хранилища.
+//
+// Тесты не рассчитывают на пустое хранилище: имена сотрудников и организаций
+// уникальны для каждого теста, а списки проверяются только в их пределах.
+package storagetest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tenders/db"
+	"tenders/internal/handlers"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Factory возвращает хранилище для одного теста
+type Factory func(t *testing.T) handlers.StorageInterface
+
+// Run запускает все тесты контракта на хранилищах из newStorage
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, f *fixture)
+	}{
+		{"NotFound", testNotFound},
+		{"WithTxRollback", testWithTxRollback},
+		{"TenderVersions", testTenderVersions},
+		{"BidVersions", testBidVersions},
+		{"BidsForTenderVisibility", testBidsForTenderVisibility},
+		{"DecisionUpsert", testDecisionUpsert},
+		{"DeleteVoter", testDeleteVoter},
+		{"TenderPagination", testTenderPagination},
+		{"BidPagination", testBidPagination},
+		{"DirectoryPagination", testDirectoryPagination},
+		{"SoftDelete", testSoftDelete},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newFixture(t, newStorage(t)))
+		})
+	}
+}
+
+// Идентификаторы, которых заведомо нет в хранилище: missingID - для
+// организаций, тендеров и предложений, missingEmployeeID - для сотрудников
+const (
+	missingID         = "ffffffff-ffff-ffff-ffff-ffffffffffff"
+	missingEmployeeID = math.MaxInt32
+)
+
+// fixture создает записи для теста с уникальными именами
+type fixture struct {
+	t      *testing.T
+	s      handlers.StorageInterface
+	ctx    context.Context
+	suffix string
+}
+
+var fixtures atomic.Int64
+
+func newFixture(t *testing.T, s handlers.StorageInterface) *fixture {
+	suffix := fmt.Sprintf("%d-%d", time.Now().UnixNano(), fixtures.Add(1))
+	return &fixture{t: t, s: s, ctx: context.Background(), suffix: suffix}
+}
+
+// employee создает сотрудника с именем name и уникальным суффиксом
+func (f *fixture) employee(name string) *db.Employee {
+	f.t.Helper()
+	e := &db.Employee{Username: name + "-" + f.suffix, FirstName: name, LastName: "Test"}
+	require.NoError(f.t, f.s.CreateEmployee(f.ctx, e))
+	return e
+}
+
+// organization создает организацию с ответственными responsibles
+func (f *fixture) organization(responsibles ...*db.Employee) *db.Organization {
+	f.t.Helper()
+	o := &db.Organization{Name: "Org " + f.suffix, Type: db.OrganizationLLC}
+	require.NoError(f.t, f.s.CreateOrganization(f.ctx, o))
+	for _, e := range responsibles {
+		require.NoError(f.t, f.s.AddResponsible(f.ctx, o.ID, e.ID, "member"))
+	}
+	return o
+}
+
+func (f *fixture) tender(org *db.Organization, name string) *db.Tender {
+	f.t.Helper()
+	t := &db.Tender{Name: name, Description: "Description", ServiceType: "Delivery", Status: "Created", OrganizationID: org.ID}
+	require.NoError(f.t, f.s.CreateTender(f.ctx, t))
+	return t
+}
+
+func (f *fixture) bid(tender *db.Tender, org *db.Organization, creator *db.Employee, name string) *db.Bid {
+	f.t.Helper()
+	b := &db.Bid{Name: name, Description: "Description", Status: "Created", TenderID: tender.ID, OrganizationID: org.ID, CreatorUsername: creator.Username}
+	require.NoError(f.t, f.s.CreateBid(f.ctx, b))
+	return b
+}
+
+func testNotFound(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	org := f.organization()
+	tender := f.tender(org, "Tender")
+	bid := f.bid(tender, org, f.employee("author"), "Bid")
+
+	_, err := s.GetEmployeeByUsername(ctx, "missing-"+f.suffix)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = s.GetOrganization(ctx, missingID)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = s.GetTender(ctx, missingID)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = s.GetBid(ctx, missingID)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = s.GetTenderVersion(ctx, tender.ID, 2)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = s.GetBidVersion(ctx, bid.ID, 2)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	require.ErrorIs(t, s.RevokeBidDecision(ctx, bid.ID, missingEmployeeID), db.ErrNotFound)
+	require.ErrorIs(t, s.RemoveResponsible(ctx, org.ID, missingEmployeeID), db.ErrNotFound)
+	_, err = s.ResolveLegacyID(ctx, db.TenderTable, missingEmployeeID)
+	require.ErrorIs(t, err, db.ErrNotFound)
+
+	// Правка несуществующей записи - тот же конфликт версий, что и правка устаревшей
+	require.ErrorIs(t, s.UpdateTender(ctx, &db.Tender{ID: missingID, Version: 1}), db.ErrVersionConflict)
+	require.ErrorIs(t, s.UpdateBid(ctx, &db.Bid{ID: missingID, Version: 1}), db.ErrVersionConflict)
+
+	require.ErrorIs(t, s.CreateTender(ctx, &db.Tender{Name: "Orphan", Description: "d", ServiceType: "Delivery", Status: "Created", OrganizationID: missingID}), db.ErrForeignKey)
+	require.ErrorIs(t, s.CreateEmployee(ctx, &db.Employee{Username: "author-" + f.suffix}), db.ErrUniqueViolation)
+}
+
+func testWithTxRollback(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	tender := f.tender(f.organization(), "Tender")
+
+	failure := errors.New("failure")
+	err := s.WithTx(ctx, func(ctx context.Context) error {
+		locked, err := s.LockTender(ctx, tender.ID)
+		require.NoError(t, err)
+		locked.Name = "Renamed"
+		require.NoError(t, s.UpdateTender(ctx, locked))
+		require.NoError(t, s.CreateEmployee(ctx, &db.Employee{Username: "ghost-" + f.suffix}))
+		return failure
+	})
+	require.ErrorIs(t, err, failure)
+
+	stored, err := s.GetTender(ctx, tender.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Tender", stored.Name)
+	require.Equal(t, 1, stored.Version)
+	_, err = s.GetEmployeeByUsername(ctx, "ghost-"+f.suffix)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	versions, _, err := s.GetTenderVersions(ctx, tender.ID, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+}
+
+func testTenderVersions(t *testing.T, f *fixture) {
+	s := f.s
+	author := f.employee("author")
+	ctx := db.WithAuthor(f.ctx, author.ID)
+	org := f.organization(author)
+
+	tender := &db.Tender{Name: "First", Description: "First description", ServiceType: "Delivery", Status: "Created", OrganizationID: org.ID}
+	require.NoError(t, s.CreateTender(ctx, tender))
+	require.Equal(t, 1, tender.Version)
+
+	stale := *tender
+	tender.Name = "Second"
+	tender.ServiceType = "Construction"
+	tender.Status = "Published"
+	require.NoError(t, s.UpdateTender(ctx, tender))
+	require.Equal(t, 2, tender.Version)
+
+	stale.Name = "Lost"
+	require.ErrorIs(t, s.UpdateTender(ctx, &stale), db.ErrVersionConflict)
+
+	stored, err := s.GetTender(ctx, tender.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, stored.Version)
+	require.Equal(t, "Second", stored.Name)
+
+	first, err := s.GetTenderVersion(ctx, tender.ID, 1)
+	require.NoError(t, err)
+	require.Equal(t, tender.ID, first.ID)
+	require.Equal(t, 1, first.Version)
+	require.Equal(t, "First", first.Name)
+	require.Equal(t, "First description", first.Description)
+	require.Equal(t, "Delivery", first.ServiceType)
+	require.Equal(t, "Created", first.Status)
+	require.Equal(t, org.ID, first.OrganizationID)
+
+	second, err := s.GetTenderVersion(ctx, tender.ID, 2)
+	require.NoError(t, err)
+	require.Equal(t, "Second", second.Name)
+	require.Equal(t, "Construction", second.ServiceType)
+	require.Equal(t, "Published", second.Status)
+
+	versions, total, err := s.GetTenderVersions(ctx, tender.ID, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Len(t, versions, 2)
+	require.Equal(t, 2, versions[0].Version)
+	require.Equal(t, "Published", versions[0].Status)
+	require.Equal(t, 1, versions[1].Version)
+	for _, v := range versions {
+		require.Equal(t, &author.ID, v.AuthorID)
+		require.Equal(t, &author.Username, v.AuthorUsername)
+	}
+
+	// Курсор продолжает историю после последней версии страницы
+	cursor := db.VersionCursor(versions[0])
+	versions, total, err = s.GetTenderVersions(ctx, tender.ID, db.Page{Limit: 1, Cursor: &cursor})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Len(t, versions, 1)
+	require.Equal(t, 1, versions[0].Version)
+
+	cursor = db.VersionCursor(versions[0])
+	cursor.Before = true
+	versions, _, err = s.GetTenderVersions(ctx, tender.ID, db.Page{Limit: 1, Cursor: &cursor})
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Equal(t, 2, versions[0].Version)
+
+	// Снимок без автора в контексте
+	require.NoError(t, s.SaveTenderVersion(f.ctx, tender))
+	versions, _, err = s.GetTenderVersions(ctx, tender.ID, db.Page{Limit: 1})
+	require.NoError(t, err)
+	require.Nil(t, versions[0].AuthorID)
+	require.Nil(t, versions[0].AuthorUsername)
+}
+
+func testBidVersions(t *testing.T, f *fixture) {
+	s := f.s
+	author := f.employee("author")
+	ctx := db.WithAuthor(f.ctx, author.ID)
+	org := f.organization(author)
+	tender := f.tender(org, "Tender")
+
+	bid := &db.Bid{Name: "First", Description: "First description", Status: "Created", TenderID: tender.ID, OrganizationID: org.ID, CreatorUsername: author.Username}
+	require.NoError(t, s.CreateBid(ctx, bid))
+	require.Equal(t, 1, bid.Version)
+
+	stale := *bid
+	bid.Name = "Second"
+	bid.Status = "Published"
+	require.NoError(t, s.UpdateBid(ctx, bid))
+	require.Equal(t, 2, bid.Version)
+	require.ErrorIs(t, s.UpdateBid(ctx, &stale), db.ErrVersionConflict)
+
+	first, err := s.GetBidVersion(ctx, bid.ID, 1)
+	require.NoError(t, err)
+	require.Equal(t, bid.ID, first.ID)
+	require.Equal(t, 1, first.Version)
+	require.Equal(t, "First", first.Name)
+	require.Equal(t, "First description", first.Description)
+	require.Equal(t, "Created", first.Status)
+	require.Equal(t, tender.ID, first.TenderID)
+	require.Equal(t, org.ID, first.OrganizationID)
+	require.Equal(t, author.Username, first.CreatorUsername)
+
+	second, err := s.GetBidVersion(ctx, bid.ID, 2)
+	require.NoError(t, err)
+	require.Equal(t, "Second", second.Name)
+	require.Equal(t, "Published", second.Status)
+
+	versions, _, err := s.GetBidVersions(ctx, bid.ID, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, 2, versions[0].Version)
+	require.Equal(t, 1, versions[1].Version)
+	require.Equal(t, &author.Username, versions[0].AuthorUsername)
+}
+
+func testBidsForTenderVisibility(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	responsible := f.employee("responsible")
+	outsider := f.employee("outsider")
+	stranger := f.employee("stranger")
+	org := f.organization(responsible)
+	tender := f.tender(f.organization(), "Tender")
+
+	// Предложение ответственного видно всем, предложение постороннего - только ему
+	byResponsible := f.bid(tender, org, responsible, "By responsible")
+	byOutsider := f.bid(tender, org, outsider, "By outsider")
+
+	bids, total, err := s.GetBidsForTender(ctx, tender.ID, outsider.Username, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Equal(t, newestFirst(byOutsider, byResponsible), bidIDs(bids))
+
+	bids, total, err = s.GetBidsForTender(ctx, tender.ID, stranger.Username, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, []string{byResponsible.ID}, bidIDs(bids))
+
+	bids, total, err = s.GetBidsForTender(ctx, missingID, outsider.Username, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Zero(t, total)
+	require.Empty(t, bids)
+}
+
+func testDecisionUpsert(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	alice := f.employee("alice")
+	bob := f.employee("bob")
+	org := f.organization(alice, bob)
+	tender := f.tender(org, "Tender")
+	bid := f.bid(tender, f.organization(), f.employee("author"), "Bid")
+
+	require.NoError(t, s.AddBidDecision(ctx, bid.ID, alice.ID, "Rejected", "too expensive"))
+	require.NoError(t, s.AddBidDecision(ctx, bid.ID, bob.ID, "Approved", ""))
+	// Повторный голос заменяет прежний
+	require.NoError(t, s.AddBidDecision(ctx, bid.ID, alice.ID, "Approved", "fixed"))
+
+	decisions, err := s.GetBidDecisions(ctx, bid.ID)
+	require.NoError(t, err)
+	require.Len(t, decisions, 2)
+	byUser := map[string]db.BidDecision{}
+	for _, d := range decisions {
+		require.Equal(t, bid.ID, d.BidID)
+		byUser[d.Username] = d
+	}
+	require.Equal(t, "Approved", byUser[alice.Username].Decision)
+	require.Equal(t, "fixed", byUser[alice.Username].Comment)
+	require.Equal(t, alice.ID, byUser[alice.Username].UserID)
+	require.Equal(t, "Approved", byUser[bob.Username].Decision)
+
+	require.NoError(t, s.RevokeBidDecision(ctx, bid.ID, bob.ID))
+	require.ErrorIs(t, s.RevokeBidDecision(ctx, bid.ID, bob.ID), db.ErrNotFound)
+
+	decisions, err = s.GetBidDecisions(ctx, bid.ID)
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	require.Equal(t, alice.Username, decisions[0].Username)
+
+	history, err := s.GetBidDecisionHistory(ctx, bid.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, alice.Username, history[0].Username)
+	require.Equal(t, "Rejected", history[0].Decision)
+	require.Equal(t, "too expensive", history[0].Comment)
+	require.Equal(t, db.DecisionOverwritten, history[0].Action)
+	require.NotNil(t, history[0].DecidedAt)
+	require.Equal(t, bob.Username, history[1].Username)
+	require.Equal(t, "Approved", history[1].Decision)
+	require.Equal(t, db.DecisionRevoked, history[1].Action)
+
+	require.ErrorIs(t, s.AddBidDecision(ctx, missingID, alice.ID, "Approved", ""), db.ErrForeignKey)
+}
+
+// employeeDeleter - хранилище, которое умеет удалять сотрудников. В
+// StorageInterface метода нет: API сотрудников только деактивирует.
+type employeeDeleter interface {
+	DeleteEmployee(ctx context.Context, username string) error
+}
+
+func testDeleteVoter(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	deleter, ok := s.(employeeDeleter)
+	if !ok {
+		t.Skip("storage does not delete employees")
+	}
+	alice := f.employee("alice")
+	bob := f.employee("bob")
+	org := f.organization(alice, bob)
+	tender := f.tender(org, "Tender")
+	bid := f.bid(tender, f.organization(), f.employee("author"), "Bid")
+
+	// У alice действующий голос, у bob - только история
+	require.NoError(t, s.AddBidDecision(ctx, bid.ID, alice.ID, "Approved", ""))
+	require.NoError(t, s.AddBidDecision(ctx, bid.ID, bob.ID, "Rejected", "late"))
+	require.NoError(t, s.RevokeBidDecision(ctx, bid.ID, bob.ID))
+
+	require.ErrorIs(t, deleter.DeleteEmployee(ctx, alice.Username), db.ErrForeignKey)
+	require.ErrorIs(t, deleter.DeleteEmployee(ctx, bob.Username), db.ErrForeignKey)
+	require.ErrorIs(t, deleter.DeleteEmployee(ctx, "missing-"+f.suffix), db.ErrNotFound)
+
+	decisions, err := s.GetBidDecisions(ctx, bid.ID)
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	require.Equal(t, alice.Username, decisions[0].Username)
+
+	history, err := s.GetBidDecisionHistory(ctx, bid.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, bob.Username, history[0].Username)
+	require.Equal(t, "late", history[0].Comment)
+
+	// Сотрудника без голосов удалить можно
+	idle := f.employee("idle")
+	require.NoError(t, deleter.DeleteEmployee(ctx, idle.Username))
+	_, err = s.GetEmployeeByUsername(ctx, idle.Username)
+	require.ErrorIs(t, err, db.ErrNotFound)
+}
+
+func testTenderPagination(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	user := f.employee("user")
+	org := f.organization(user)
+	var created []*db.Tender
+	for _, name := range []string{"C", "A", "B", "A"} {
+		created = append(created, f.tender(org, name))
+	}
+	// По названию, затем по id
+	order := []string{min(created[1].ID, created[3].ID), max(created[1].ID, created[3].ID), created[2].ID, created[0].ID}
+
+	tenders, total, err := s.GetUserTenders(ctx, user.Username, db.Page{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+	require.Equal(t, order[:2], tenderIDs(tenders))
+
+	after := db.TenderCursor(tenders[1])
+	tenders, total, err = s.GetUserTenders(ctx, user.Username, db.Page{Limit: 5, Cursor: &after})
+	require.NoError(t, err)
+	require.Equal(t, 4, total)
+	require.Equal(t, order[2:], tenderIDs(tenders))
+
+	before := db.TenderCursor(tenders[1])
+	before.Before = true
+	tenders, _, err = s.GetUserTenders(ctx, user.Username, db.Page{Limit: 2, Cursor: &before})
+	require.NoError(t, err)
+	require.Equal(t, order[1:3], tenderIDs(tenders))
+
+	tenders, _, err = s.GetUserTenders(ctx, user.Username, db.Page{Limit: 2, Offset: 3})
+	require.NoError(t, err)
+	require.Equal(t, order[3:], tenderIDs(tenders))
+
+	tenders, total, err = s.GetUserTenders(ctx, f.employee("nobody").Username, db.Page{Limit: 2})
+	require.NoError(t, err)
+	require.Zero(t, total)
+	require.Empty(t, tenders)
+}
+
+func testBidPagination(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	author := f.employee("author")
+	org := f.organization(author)
+	tender := f.tender(org, "Tender")
+	var created []*db.Bid
+	for i := 0; i < 5; i++ {
+		created = append(created, f.bid(tender, org, author, fmt.Sprintf("Bid %d", i)))
+	}
+	order := newestFirst(created...)
+
+	// От новых к старым
+	bids, total, err := s.GetUserBids(ctx, author.Username, db.Page{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 5, total)
+	require.Equal(t, order[:2], bidIDs(bids))
+
+	after := db.BidCursor(bids[1])
+	bids, _, err = s.GetUserBids(ctx, author.Username, db.Page{Limit: 2, Cursor: &after})
+	require.NoError(t, err)
+	require.Equal(t, order[2:4], bidIDs(bids))
+
+	before := db.BidCursor(bids[0])
+	before.Before = true
+	bids, _, err = s.GetUserBids(ctx, author.Username, db.Page{Limit: 5, Cursor: &before})
+	require.NoError(t, err)
+	require.Equal(t, order[:2], bidIDs(bids))
+
+	bids, total, err = s.GetBidsForTender(ctx, tender.ID, author.Username, db.Page{Limit: 2, Offset: 4})
+	require.NoError(t, err)
+	require.Equal(t, 5, total)
+	require.Equal(t, order[4:], bidIDs(bids))
+}
+
+func testDirectoryPagination(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	c, a, b := f.employee("c"), f.employee("a"), f.employee("b")
+
+	// Сотрудники - по username
+	employees, total, err := s.ListEmployees(ctx, f.suffix, false, db.Page{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, []string{a.Username, b.Username}, usernames(employees))
+
+	after := db.EmployeeCursor(employees[1])
+	employees, _, err = s.ListEmployees(ctx, f.suffix, false, db.Page{Limit: 2, Cursor: &after})
+	require.NoError(t, err)
+	require.Equal(t, []string{c.Username}, usernames(employees))
+
+	before := db.EmployeeCursor(employees[0])
+	before.Before = true
+	employees, _, err = s.ListEmployees(ctx, f.suffix, false, db.Page{Limit: 1, Cursor: &before})
+	require.NoError(t, err)
+	require.Equal(t, []string{b.Username}, usernames(employees))
+
+	// Организации с одинаковым названием - по id
+	var created []string
+	for i := 0; i < 3; i++ {
+		created = append(created, f.organization().ID)
+	}
+	slices.Sort(created)
+
+	organizations, total, err := s.ListOrganizations(ctx, f.suffix, db.Page{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 3, total)
+	require.Equal(t, created[:2], organizationIDs(organizations))
+
+	next := db.OrganizationCursor(organizations[1])
+	organizations, _, err = s.ListOrganizations(ctx, f.suffix, db.Page{Limit: 2, Cursor: &next})
+	require.NoError(t, err)
+	require.Equal(t, created[2:], organizationIDs(organizations))
+}
+
+func testSoftDelete(t *testing.T, f *fixture) {
+	ctx, s := f.ctx, f.s
+	responsible := f.employee("responsible")
+	author := f.employee("author")
+	org := f.organization(responsible)
+	kept := f.tender(org, "Kept")
+	archived := f.tender(org, "Archived")
+	keptBid := f.bid(kept, org, author, "Kept bid")
+	archivedBid := f.bid(kept, org, author, "Archived bid")
+	orphanBid := f.bid(archived, org, author, "Bid on archived tender")
+
+	byResponsible := db.WithAuthor(ctx, responsible.ID)
+	require.NoError(t, s.ArchiveTender(byResponsible, archived))
+	require.NotNil(t, archived.DeletedAt)
+	require.Equal(t, &responsible.ID, archived.DeletedBy)
+	require.ErrorIs(t, s.ArchiveTender(byResponsible, archived), db.ErrNotFound)
+	require.ErrorIs(t, s.ArchiveTender(byResponsible, &db.Tender{ID: missingID}), db.ErrNotFound)
+	require.NoError(t, s.ArchiveBid(byResponsible, archivedBid))
+	require.Equal(t, &responsible.ID, archivedBid.DeletedBy)
+
+	// Записи из архива пропадают из списков, но читаются по идентификатору
+	tenders, total, err := s.GetUserTenders(ctx, responsible.Username, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	require.Equal(t, []string{kept.ID}, tenderIDs(tenders))
+	bids, total, err := s.GetUserBids(ctx, author.Username, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.Equal(t, newestFirst(orphanBid, keptBid), bidIDs(bids))
+	bids, _, err = s.GetBidsForTender(ctx, kept.ID, author.Username, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, []string{keptBid.ID}, bidIDs(bids))
+
+	stored, err := s.GetTender(ctx, archived.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.DeletedAt)
+	require.Equal(t, &responsible.ID, stored.DeletedBy)
+
+	require.NoError(t, s.RestoreTender(ctx, archived))
+	require.Nil(t, archived.DeletedAt)
+	require.Nil(t, archived.DeletedBy)
+	require.ErrorIs(t, s.RestoreTender(ctx, archived), db.ErrNotFound)
+	require.ErrorIs(t, s.RestoreBid(ctx, keptBid), db.ErrNotFound)
+	_, total, err = s.GetUserTenders(ctx, responsible.Username, db.Page{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	require.NoError(t, s.ArchiveTender(byResponsible, archived))
+
+	// Очистка удаляет только записи, которые в архиве дольше retention
+	_, err = s.PurgeArchived(ctx, time.Hour)
+	require.NoError(t, err)
+	_, err = s.GetTender(ctx, archived.ID)
+	require.NoError(t, err)
+
+	// Тендер с предложением, которое не в архиве, остается до следующей очистки
+	result, err := s.PurgeArchived(ctx, 0)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, result.Bids, 1)
+	_, err = s.GetBid(ctx, archivedBid.ID)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = s.GetTender(ctx, archived.ID)
+	require.NoError(t, err)
+	_, err = s.GetBid(ctx, orphanBid.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, s.ArchiveBid(byResponsible, orphanBid))
+	result, err = s.PurgeArchived(ctx, 0)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, result.Tenders, 1)
+	require.GreaterOrEqual(t, result.Bids, 1)
+	_, err = s.GetTender(ctx, archived.ID)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = s.GetBid(ctx, orphanBid.ID)
+	require.ErrorIs(t, err, db.ErrNotFound)
+	_, err = s.GetTender(ctx, kept.ID)
+	require.NoError(t, err)
+	_, err = s.GetBid(ctx, keptBid.ID)
+	require.NoError(t, err)
+
+	// Организацию с записями закупок удалить нельзя
+	require.ErrorIs(t, s.DeleteOrganization(ctx, org.ID), db.ErrForeignKey)
+}
+
+func tenderIDs(tenders []db.Tender) []string {
+	ids := make([]string, len(tenders))
+	for i, t := range tenders {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+func bidIDs(bids []db.Bid) []string {
+	ids := make([]string, len(bids))
+	for i, b := range bids {
+		ids[i] = b.ID
+	}
+	return ids
+}
+
+func usernames(employees []db.Employee) []string {
+	names := make([]string, len(employees))
+	for i, e := range employees {
+		names[i] = e.Username
+	}
+	return names
+}
+
+func organizationIDs(organizations []db.Organization) []string {
+	ids := make([]string, len(organizations))
+	for i, o := range organizations {
+		ids[i] = o.ID
+	}
+	return ids
+}
+
+// newestFirst возвращает идентификаторы предложений в порядке списков: от
+// новых к старым, при равном времени создания - по убыванию id
+func newestFirst(bids ...*db.Bid) []string {
+	sorted := slices.Clone(bids)
+	slices.SortFunc(sorted, func(a, b *db.Bid) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	ids := make([]string, len(sorted))
+	for i, b := range sorted {
+		ids[i] = b.ID
+	}
+	return ids
+}
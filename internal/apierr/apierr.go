@@ -0,0 +1,66 @@
+// Package apierr описывает ошибки API в формате схемы errorResponse из openapi.yml:
+// {"reason": "...", "code": "..."}. reason - текст для человека, code - стабильный
+// машиночитаемый код, по которому клиенты различают ошибки.
+package apierr
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Code - машиночитаемый код ошибки. Значения не меняются между версиями API.
+type Code string
+
+const (
+	// InvalidRequest - неверные параметры или тело запроса
+	InvalidRequest Code = "invalid_request"
+	// ValidationFailed - поля тела запроса не прошли проверку, список в errors
+	ValidationFailed Code = "validation_failed"
+	// Unauthorized - запрос без токена или с неверным токеном
+	Unauthorized Code = "unauthorized"
+	// Forbidden - у пользователя нет прав на действие
+	Forbidden Code = "forbidden"
+	// NotFound - ресурс не найден
+	NotFound Code = "not_found"
+	// Conflict - изменение конфликтует с параллельным, запрос можно повторить
+	Conflict Code = "conflict"
+	// VersionConflict - ресурс изменился после того, как клиент его прочитал
+	VersionConflict Code = "version_conflict"
+	// PreconditionRequired - правка без версии, которую редактировал клиент
+	PreconditionRequired Code = "precondition_required"
+	// AlreadyExists - ресурс с таким ключом уже существует
+	AlreadyExists Code = "already_exists"
+	// InvalidState - текущее состояние ресурса не допускает действие
+	InvalidState Code = "invalid_state"
+	// InvalidTransition - недопустимая смена статуса
+	InvalidTransition Code = "invalid_transition"
+	// InvalidReference - запрос ссылается на несуществующую или используемую запись
+	InvalidReference Code = "invalid_reference"
+	// Internal - сбой сервера
+	Internal Code = "internal_error"
+)
+
+// Response - тело ответа с ошибкой
+type Response struct {
+	Reason string `json:"reason"`
+	Code   Code   `json:"code"`
+}
+
+// Error позволяет возвращать Response как error (например, из клиента API)
+func (r *Response) Error() string {
+	return string(r.Code) + ": " + r.Reason
+}
+
+// Write отвечает клиенту ошибкой status с телом Response
+func Write(w http.ResponseWriter, status int, code Code, reason string) {
+	WriteJSON(w, status, Response{Reason: reason, Code: code})
+}
+
+// WriteJSON отвечает клиенту ошибкой status с произвольным телом. Тело должно
+// содержать поля reason и code, чтобы соответствовать схеме errorResponse.
+func WriteJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
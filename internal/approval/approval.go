@@ -0,0 +1,173 @@
+package approval
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind - способ подсчета голосов
+type Kind string
+
+const (
+	// KindFixed - нужно Required одобрений (но не больше числа голосующих)
+	KindFixed Kind = "fixed"
+	// KindMajority - нужно больше половины голосующих
+	KindMajority Kind = "majority"
+	// KindUnanimous - нужны одобрения всех голосующих, любое отклонение решает исход
+	KindUnanimous Kind = "unanimous"
+	// KindWeighted - голоса взвешиваются по роли голосующего, нужно набрать
+	// Required веса (или больше половины общего веса, если Required = 0)
+	KindWeighted Kind = "weighted"
+)
+
+// DefaultFixedQuorum - кворум политики по умолчанию
+const DefaultFixedQuorum = 3
+
+// Outcome - итог голосования
+type Outcome int
+
+const (
+	Pending Outcome = iota
+	Approved
+	Rejected
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Approved:
+		return "Approved"
+	case Rejected:
+		return "Rejected"
+	default:
+		return "Pending"
+	}
+}
+
+// Policy описывает правило принятия решения по предложению.
+type Policy struct {
+	Kind Kind
+	// Required - число одобрений для fixed или порог веса для weighted
+	Required int
+	// RejectThreshold - сколько отклонений отклоняют предложение (0 означает 1)
+	RejectThreshold int
+	// RoleWeights - вес голоса по роли для weighted, по умолчанию 1
+	RoleWeights map[string]int
+}
+
+// Default - политика, действовавшая до появления настроек:
+// min(3, число ответственных) одобрений, одно отклонение отклоняет предложение.
+func Default() Policy {
+	return Policy{Kind: KindFixed, Required: DefaultFixedQuorum, RejectThreshold: 1}
+}
+
+// Vote - учтенный голос
+type Vote struct {
+	Role    string
+	Approve bool
+}
+
+// Validate проверяет согласованность настроек политики.
+func (p Policy) Validate() error {
+	switch p.Kind {
+	case KindFixed:
+		if p.Required < 1 {
+			return errors.New("required must be at least 1 for fixed policy")
+		}
+	case KindMajority, KindUnanimous:
+	case KindWeighted:
+		if len(p.RoleWeights) == 0 {
+			return errors.New("roleWeights must not be empty for weighted policy")
+		}
+		if p.Required < 0 {
+			return errors.New("required must not be negative")
+		}
+		total := 0
+		for role, weight := range p.RoleWeights {
+			if weight < 0 {
+				return fmt.Errorf("weight of role %q must not be negative", role)
+			}
+			total += weight
+		}
+		// С нулевыми весами голоса ничего не весят и решение не принимается
+		if total == 0 {
+			return errors.New("at least one role weight must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown policy kind %q", p.Kind)
+	}
+	if p.RejectThreshold < 0 {
+		return errors.New("rejectThreshold must not be negative")
+	}
+	return nil
+}
+
+func (p Policy) weight(role string) int {
+	if p.Kind != KindWeighted {
+		return 1
+	}
+	if w, ok := p.RoleWeights[role]; ok {
+		return w
+	}
+	return 1
+}
+
+// Evaluate подводит итог по ролям всех голосующих (electorate) и поданным голосам.
+// Предложение отклоняется, если набрано RejectThreshold отклонений или если
+// оставшихся голосов уже не хватит для одобрения.
+func (p Policy) Evaluate(electorate []string, votes []Vote) Outcome {
+	total := 0
+	for _, role := range electorate {
+		total += p.weight(role)
+	}
+
+	approveWeight, rejectWeight, rejects := 0, 0, 0
+	for _, v := range votes {
+		if v.Approve {
+			approveWeight += p.weight(v.Role)
+		} else {
+			rejectWeight += p.weight(v.Role)
+			rejects++
+		}
+	}
+
+	rejectThreshold := p.RejectThreshold
+	if rejectThreshold < 1 || p.Kind == KindUnanimous {
+		rejectThreshold = 1
+	}
+	if rejects >= rejectThreshold {
+		return Rejected
+	}
+
+	need := p.needed(total)
+	if approveWeight >= need {
+		return Approved
+	}
+	pending := total - approveWeight - rejectWeight
+	if pending < 0 {
+		pending = 0
+	}
+	if approveWeight+pending < need {
+		return Rejected
+	}
+	return Pending
+}
+
+// needed возвращает необходимый для одобрения вес
+func (p Policy) needed(total int) int {
+	switch p.Kind {
+	case KindFixed:
+		if p.Required < total {
+			return p.Required
+		}
+		return total
+	case KindUnanimous:
+		return total
+	case KindWeighted:
+		if p.Required > 0 {
+			return p.Required
+		}
+		return total/2 + 1
+	default: // KindMajority
+		return total/2 + 1
+	}
+}
@@ -0,0 +1,79 @@
+package approval_test
+
+import (
+	"testing"
+
+	"tenders/internal/approval"
+
+	"github.com/stretchr/testify/require"
+)
+
+func votes(approvals, rejections int) []approval.Vote {
+	var vs []approval.Vote
+	for i := 0; i < approvals; i++ {
+		vs = append(vs, approval.Vote{Approve: true})
+	}
+	for i := 0; i < rejections; i++ {
+		vs = append(vs, approval.Vote{Approve: false})
+	}
+	return vs
+}
+
+func electorate(n int) []string {
+	return make([]string, n)
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	p := approval.Default()
+	require.Equal(t, approval.Pending, p.Evaluate(electorate(5), votes(2, 0)))
+	require.Equal(t, approval.Approved, p.Evaluate(electorate(5), votes(3, 0)))
+	require.Equal(t, approval.Approved, p.Evaluate(electorate(2), votes(2, 0)))
+	require.Equal(t, approval.Rejected, p.Evaluate(electorate(5), votes(2, 1)))
+}
+
+func TestMajorityPolicy(t *testing.T) {
+	p := approval.Policy{Kind: approval.KindMajority, RejectThreshold: 3}
+	require.Equal(t, approval.Pending, p.Evaluate(electorate(5), votes(2, 1)))
+	require.Equal(t, approval.Approved, p.Evaluate(electorate(5), votes(3, 2)))
+	// 2 одобрения + 0 оставшихся голосов < 3
+	require.Equal(t, approval.Rejected, p.Evaluate(electorate(4), votes(2, 2)))
+}
+
+func TestUnanimousPolicy(t *testing.T) {
+	p := approval.Policy{Kind: approval.KindUnanimous, RejectThreshold: 5}
+	require.Equal(t, approval.Pending, p.Evaluate(electorate(3), votes(2, 0)))
+	require.Equal(t, approval.Approved, p.Evaluate(electorate(3), votes(3, 0)))
+	require.Equal(t, approval.Rejected, p.Evaluate(electorate(3), votes(2, 1)))
+}
+
+func TestWeightedPolicy(t *testing.T) {
+	p := approval.Policy{
+		Kind:            approval.KindWeighted,
+		Required:        4,
+		RejectThreshold: 2,
+		RoleWeights:     map[string]int{"head": 3, "member": 1},
+	}
+	roles := []string{"head", "member", "member", "member"}
+	require.Equal(t, approval.Pending, p.Evaluate(roles, []approval.Vote{{Role: "head", Approve: true}}))
+	require.Equal(t, approval.Approved, p.Evaluate(roles, []approval.Vote{
+		{Role: "head", Approve: true}, {Role: "member", Approve: true},
+	}))
+	// Без руководителя набрать 4 нельзя
+	require.Equal(t, approval.Rejected, p.Evaluate(roles, []approval.Vote{{Role: "head", Approve: false}}))
+}
+
+func TestRejectThreshold(t *testing.T) {
+	p := approval.Policy{Kind: approval.KindFixed, Required: 2, RejectThreshold: 2}
+	require.Equal(t, approval.Pending, p.Evaluate(electorate(10), votes(1, 1)))
+	require.Equal(t, approval.Rejected, p.Evaluate(electorate(10), votes(1, 2)))
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, approval.Default().Validate())
+	require.Error(t, approval.Policy{Kind: approval.KindFixed}.Validate())
+	require.Error(t, approval.Policy{Kind: approval.KindWeighted}.Validate())
+	require.Error(t, approval.Policy{Kind: approval.KindWeighted, RoleWeights: map[string]int{"member": 0, "lead": 0}}.Validate())
+	require.NoError(t, approval.Policy{Kind: approval.KindWeighted, RoleWeights: map[string]int{"member": 0, "lead": 1}}.Validate())
+	require.Error(t, approval.Policy{Kind: "random"}.Validate())
+	require.Error(t, approval.Policy{Kind: approval.KindMajority, RejectThreshold: -1}.Validate())
+}
@@ -0,0 +1,170 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"tenders/db"
+	"tenders/internal/auth"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte(strings.Repeat("s", 32))
+
+type employees map[string]*db.Employee
+
+func (e employees) GetEmployeeByUsername(ctx context.Context, username string) (*db.Employee, error) {
+	if username == "broken" {
+		return nil, errors.New("connection reset by peer")
+	}
+	if emp, ok := e[username]; ok {
+		return emp, nil
+	}
+	return nil, db.ErrNotFound
+}
+
+func TestHMACRoundTrip(t *testing.T) {
+	issuer, err := auth.NewHMACIssuer(testSecret, time.Hour)
+	require.NoError(t, err)
+
+	token, expiresAt, err := issuer.Issue(&db.Employee{ID: 7, Username: "user1"})
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	claims, err := issuer.Parse(token)
+	require.NoError(t, err)
+	require.Equal(t, "user1", claims.Username)
+	id, err := claims.EmployeeID()
+	require.NoError(t, err)
+	require.Equal(t, 7, id)
+}
+
+func TestHMACShortSecret(t *testing.T) {
+	_, err := auth.NewHMACIssuer([]byte("short"), time.Hour)
+	require.Error(t, err)
+}
+
+func TestParseRejectsBadTokens(t *testing.T) {
+	issuer, err := auth.NewHMACIssuer(testSecret, time.Hour)
+	require.NoError(t, err)
+	other, err := auth.NewHMACIssuer([]byte(strings.Repeat("x", 32)), time.Hour)
+	require.NoError(t, err)
+	expired, err := auth.NewHMACIssuer(testSecret, -time.Minute)
+	require.NoError(t, err)
+
+	foreign, _, err := other.Issue(&db.Employee{ID: 1, Username: "user1"})
+	require.NoError(t, err)
+	stale, _, err := expired.Issue(&db.Employee{ID: 1, Username: "user1"})
+	require.NoError(t, err)
+
+	for _, token := range []string{"", "garbage", foreign, stale} {
+		_, err := issuer.Parse(token)
+		require.ErrorIs(t, err, auth.ErrInvalidToken)
+	}
+}
+
+func TestLoadIssuerEd25519(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	issuer, err := auth.LoadIssuer(path, time.Hour)
+	require.NoError(t, err)
+	token, _, err := issuer.Issue(&db.Employee{ID: 3, Username: "user3"})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, "eyJhbGciOiJFZERTQSI"), "token must be signed with EdDSA")
+
+	claims, err := issuer.Parse(token)
+	require.NoError(t, err)
+	require.Equal(t, "user3", claims.Username)
+
+	// HMAC токен с тем же содержимым не должен проходить проверку EdDSA
+	hmac, err := auth.NewHMACIssuer(testSecret, time.Hour)
+	require.NoError(t, err)
+	forged, _, err := hmac.Issue(&db.Employee{ID: 3, Username: "user3"})
+	require.NoError(t, err)
+	_, err = issuer.Parse(forged)
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+}
+
+func TestMiddleware(t *testing.T) {
+	issuer, err := auth.NewHMACIssuer(testSecret, time.Hour)
+	require.NoError(t, err)
+	deactivatedAt := time.Now()
+	store := employees{
+		"user1":  {ID: 1, Username: "user1"},
+		"leaver": {ID: 2, Username: "leaver", DeactivatedAt: &deactivatedAt},
+	}
+
+	token, _, err := issuer.Issue(store["user1"])
+	require.NoError(t, err)
+	recreated, _, err := issuer.Issue(&db.Employee{ID: 99, Username: "user1"})
+	require.NoError(t, err)
+	leaver, _, err := issuer.Issue(store["leaver"])
+	require.NoError(t, err)
+	broken, _, err := issuer.Issue(&db.Employee{ID: 3, Username: "broken"})
+	require.NoError(t, err)
+
+	var seen *db.Employee
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = auth.EmployeeFrom(r.Context())
+	})
+
+	cases := []struct {
+		name     string
+		insecure bool
+		url      string
+		header   string
+		code     int
+		caller   string
+	}{
+		{name: "bearer", url: "/", header: "Bearer " + token, code: http.StatusOK, caller: "user1"},
+		{name: "anonymous", url: "/", code: http.StatusOK},
+		{name: "username ignored by default", url: "/?username=user1", code: http.StatusOK},
+		{name: "insecure username", insecure: true, url: "/?username=user1", code: http.StatusOK, caller: "user1"},
+		{name: "insecure requester", insecure: true, url: "/?requesterUsername=user1", code: http.StatusOK, caller: "user1"},
+		{name: "bad scheme", url: "/", header: "Basic dXNlcjE=", code: http.StatusUnauthorized},
+		{name: "bad token", url: "/", header: "Bearer garbage", code: http.StatusUnauthorized},
+		{name: "stale subject", url: "/", header: "Bearer " + recreated, code: http.StatusUnauthorized},
+		{name: "deactivated", url: "/", header: "Bearer " + leaver, code: http.StatusUnauthorized},
+		{name: "storage failure", url: "/", header: "Bearer " + broken, code: http.StatusInternalServerError},
+		{name: "insecure deactivated", insecure: true, url: "/?username=leaver", code: http.StatusOK},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			seen = nil
+			handler := auth.Middleware(issuer, store, auth.Options{InsecureUsernameParam: c.insecure})(next)
+
+			req := httptest.NewRequest(http.MethodGet, c.url, nil)
+			if c.header != "" {
+				req.Header.Set("Authorization", c.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			require.Equal(t, c.code, w.Code)
+			if c.caller == "" {
+				require.Nil(t, seen)
+			} else {
+				require.NotNil(t, seen)
+				require.Equal(t, c.caller, seen.Username)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+)
+
+// employeeKey - ключ контекста, под которым Middleware хранит сотрудника
+type employeeKey struct{}
+
+// WithEmployee кладет аутентифицированного сотрудника в контекст
+func WithEmployee(ctx context.Context, e *db.Employee) context.Context {
+	return context.WithValue(ctx, employeeKey{}, e)
+}
+
+// EmployeeFrom возвращает сотрудника, от имени которого выполняется запрос
+func EmployeeFrom(ctx context.Context) (*db.Employee, bool) {
+	e, ok := ctx.Value(employeeKey{}).(*db.Employee)
+	return e, ok && e != nil
+}
+
+// EmployeeLookup - часть хранилища, нужная для загрузки сотрудника по токену
+type EmployeeLookup interface {
+	GetEmployeeByUsername(ctx context.Context, username string) (*db.Employee, error)
+}
+
+// Options настраивает Middleware
+type Options struct {
+	// InsecureUsernameParam разрешает представляться query параметром
+	// username (или requesterUsername) без токена. Только для разработки.
+	InsecureUsernameParam bool
+}
+
+// Middleware проверяет заголовок Authorization: Bearer <token> и кладет
+// сотрудника в контекст. Запрос без токена пропускается анонимным: обработчики,
+// которым нужен пользователь, сами отвечают 401. Неверный токен - сразу 401.
+func Middleware(issuer *Issuer, store EmployeeLookup, opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				if opts.InsecureUsernameParam {
+					r = insecureCaller(r, store)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || issuer == nil {
+				apierr.Write(w, http.StatusUnauthorized, apierr.Unauthorized, "Invalid authorization header")
+				return
+			}
+			claims, err := issuer.Parse(strings.TrimSpace(token))
+			if err != nil {
+				apierr.Write(w, http.StatusUnauthorized, apierr.Unauthorized, "Invalid token")
+				return
+			}
+
+			employee, err := store.GetEmployeeByUsername(r.Context(), claims.Username)
+			if err != nil && !errors.Is(err, db.ErrNotFound) {
+				log.Printf("%s %s: load employee %q: %v", r.Method, r.URL.Path, claims.Username, err)
+				apierr.Write(w, http.StatusInternalServerError, apierr.Internal, "Internal server error")
+				return
+			}
+			// Пользователя могли удалить и создать заново с тем же username
+			if id, _ := claims.EmployeeID(); err != nil || employee.ID != id {
+				apierr.Write(w, http.StatusUnauthorized, apierr.Unauthorized, "User not found")
+				return
+			}
+			if !employee.Active() {
+				apierr.Write(w, http.StatusUnauthorized, apierr.Unauthorized, "User is deactivated")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithEmployee(r.Context(), employee)))
+		})
+	}
+}
+
+// insecureCaller подставляет сотрудника по query параметру, если он существует
+func insecureCaller(r *http.Request, store EmployeeLookup) *http.Request {
+	username := r.URL.Query().Get("username")
+	if username == "" {
+		username = r.URL.Query().Get("requesterUsername")
+	}
+	if username == "" {
+		return r
+	}
+	employee, err := store.GetEmployeeByUsername(r.Context(), strings.TrimSpace(username))
+	if err != nil || !employee.Active() {
+		return r
+	}
+	return r.WithContext(WithEmployee(r.Context(), employee))
+}
@@ -0,0 +1,239 @@
+// Package authz решает, может ли сотрудник выполнить действие над ресурсом.
+// Роли сотрудника вычисляются относительно ресурса (организации и автора),
+// а права на действия задаются декларативно в таблице permissions.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tenders/db"
+)
+
+// Role - роль сотрудника относительно ресурса
+type Role string
+
+const (
+	// RolePlatformAdmin - администратор площадки, управляет любыми тендерами
+	RolePlatformAdmin Role = "platform_admin"
+	// RoleAuditor - аудитор, может только читать
+	RoleAuditor Role = "auditor"
+	// RoleResponsible - ответственный за организацию ресурса
+	RoleResponsible Role = "organization_responsible"
+	// RoleMember - сотрудник организации ресурса
+	RoleMember Role = "organization_member"
+	// RoleBidder - автор предложения
+	RoleBidder Role = "bidder"
+)
+
+// Платформенные роли в том виде, в каком они хранятся в БД
+const (
+	PlatformAdmin   = "admin"
+	PlatformAuditor = "auditor"
+)
+
+// Action - действие, на которое проверяется право
+type Action string
+
+const (
+	TenderCreate    Action = "tender.create"
+	TenderView      Action = "tender.view"
+	TenderEdit      Action = "tender.edit"
+	TenderPublish   Action = "tender.publish"
+	TenderClose     Action = "tender.close"
+	TenderRollback  Action = "tender.rollback"
+	TenderHistory   Action = "tender.history"
+	TenderViewDraft Action = "tender.view_draft"
+	TenderBids      Action = "tender.bids"
+	TenderArchive   Action = "tender.archive"
+
+	BidCreate   Action = "bid.create"
+	BidView     Action = "bid.view"
+	BidEdit     Action = "bid.edit"
+	BidPublish  Action = "bid.publish"
+	BidCancel   Action = "bid.cancel"
+	BidRollback Action = "bid.rollback"
+	BidDecide   Action = "bid.decide"
+	BidHistory  Action = "bid.history"
+	BidArchive  Action = "bid.archive"
+
+	ReviewCreate Action = "review.create"
+	ReviewView   Action = "review.view"
+
+	PolicyView   Action = "policy.view"
+	PolicyManage Action = "policy.manage"
+
+	EmployeeView   Action = "employee.view"
+	EmployeeManage Action = "employee.manage"
+
+	OrganizationCreate Action = "organization.create"
+	OrganizationView   Action = "organization.view"
+	OrganizationManage Action = "organization.manage"
+	OrganizationDelete Action = "organization.delete"
+
+	ArchivePurge Action = "archive.purge"
+)
+
+// permissions - какие роли могут выполнять действие
+var permissions = map[Action][]Role{
+	TenderCreate:   {RolePlatformAdmin, RoleResponsible},
+	TenderView:     {RolePlatformAdmin, RoleAuditor, RoleResponsible, RoleMember},
+	TenderEdit:     {RolePlatformAdmin, RoleResponsible},
+	TenderPublish:  {RolePlatformAdmin, RoleResponsible},
+	TenderClose:    {RolePlatformAdmin, RoleResponsible},
+	TenderRollback: {RolePlatformAdmin, RoleResponsible},
+	TenderHistory:  {RolePlatformAdmin, RoleAuditor, RoleResponsible, RoleMember},
+	// Неопубликованный тендер не видят даже сотрудники организации
+	TenderViewDraft: {RolePlatformAdmin, RoleAuditor, RoleResponsible},
+	TenderBids:      {RolePlatformAdmin, RoleAuditor, RoleResponsible},
+	TenderArchive:   {RolePlatformAdmin, RoleResponsible},
+
+	BidCreate:   {RoleResponsible, RoleMember},
+	BidView:     {RolePlatformAdmin, RoleAuditor, RoleResponsible, RoleBidder},
+	BidEdit:     {RoleResponsible, RoleBidder},
+	BidPublish:  {RoleResponsible, RoleBidder},
+	BidCancel:   {RoleResponsible, RoleBidder},
+	BidRollback: {RoleResponsible},
+	BidDecide:   {RoleResponsible},
+	BidHistory:  {RolePlatformAdmin, RoleAuditor, RoleResponsible, RoleBidder},
+	BidArchive:  {RoleResponsible},
+
+	ReviewCreate: {RoleResponsible},
+	ReviewView:   {RolePlatformAdmin, RoleAuditor, RoleResponsible},
+
+	PolicyView:   {RolePlatformAdmin, RoleAuditor, RoleResponsible},
+	PolicyManage: {RolePlatformAdmin, RoleResponsible},
+
+	EmployeeView:   {RolePlatformAdmin, RoleAuditor},
+	EmployeeManage: {RolePlatformAdmin},
+
+	OrganizationCreate: {RolePlatformAdmin},
+	OrganizationView:   {RolePlatformAdmin, RoleAuditor, RoleResponsible, RoleMember},
+	OrganizationManage: {RolePlatformAdmin, RoleResponsible},
+	OrganizationDelete: {RolePlatformAdmin},
+
+	// Очистка архива удаляет данные безвозвратно
+	ArchivePurge: {RolePlatformAdmin},
+}
+
+// Allowed возвращает роли, которым разрешено действие
+func Allowed(action Action) []Role {
+	return append([]Role(nil), permissions[action]...)
+}
+
+// ErrForbidden - у сотрудника нет права на действие
+var ErrForbidden = errors.New("forbidden")
+
+// ErrUnknownAction - действие не описано в таблице прав
+var ErrUnknownAction = errors.New("unknown action")
+
+// Resource - то, над чем выполняется действие
+type Resource struct {
+	// OrganizationID - организация, которой принадлежит ресурс
+	OrganizationID string
+	// Owner - username автора ресурса (для предложений)
+	Owner string
+}
+
+// Organization - ресурс уровня организации (создание тендера, политики)
+func Organization(id string) Resource {
+	return Resource{OrganizationID: id}
+}
+
+// Tender - ресурс тендера
+func Tender(t *db.Tender) Resource {
+	return Resource{OrganizationID: t.OrganizationID}
+}
+
+// Bid - ресурс предложения, автор получает роль RoleBidder
+func Bid(b *db.Bid) Resource {
+	return Resource{OrganizationID: b.OrganizationID, Owner: b.CreatorUsername}
+}
+
+// Platform - ресурс уровня площадки (справочник сотрудников)
+func Platform() Resource {
+	return Resource{}
+}
+
+// Directory - источник ролей сотрудников
+type Directory interface {
+	// GetPlatformRole возвращает PlatformAdmin, PlatformAuditor или пустую строку
+	GetPlatformRole(ctx context.Context, userID int) (string, error)
+	IsUserResponsibleForOrganization(ctx context.Context, userID int, organizationID string) (bool, error)
+	IsUserMemberOfOrganization(ctx context.Context, userID int, organizationID string) (bool, error)
+}
+
+// Authorizer проверяет права по таблице permissions
+type Authorizer struct {
+	dir Directory
+}
+
+// New создает Authorizer
+func New(dir Directory) *Authorizer {
+	return &Authorizer{dir: dir}
+}
+
+// Roles вычисляет роли сотрудника относительно ресурса
+func (a *Authorizer) Roles(ctx context.Context, actor *db.Employee, res Resource) ([]Role, error) {
+	var roles []Role
+
+	platform, err := a.dir.GetPlatformRole(ctx, actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get platform role: %w", err)
+	}
+	switch platform {
+	case PlatformAdmin:
+		roles = append(roles, RolePlatformAdmin)
+	case PlatformAuditor:
+		roles = append(roles, RoleAuditor)
+	}
+
+	if res.Owner != "" && res.Owner == actor.Username {
+		roles = append(roles, RoleBidder)
+	}
+
+	if res.OrganizationID != "" {
+		responsible, err := a.dir.IsUserResponsibleForOrganization(ctx, actor.ID, res.OrganizationID)
+		if err != nil {
+			return nil, fmt.Errorf("check responsible: %w", err)
+		}
+		if responsible {
+			roles = append(roles, RoleResponsible)
+		}
+		member, err := a.dir.IsUserMemberOfOrganization(ctx, actor.ID, res.OrganizationID)
+		if err != nil {
+			return nil, fmt.Errorf("check member: %w", err)
+		}
+		if member {
+			roles = append(roles, RoleMember)
+		}
+	}
+	return roles, nil
+}
+
+// Authorize возвращает nil, если у actor есть право на action над res,
+// ошибку с ErrForbidden, если права нет, и любую другую ошибку, если
+// роли не удалось загрузить.
+func (a *Authorizer) Authorize(ctx context.Context, actor *db.Employee, action Action, res Resource) error {
+	allowed, ok := permissions[action]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownAction, action)
+	}
+	if actor == nil {
+		return fmt.Errorf("%w: %s requires an authenticated user", ErrForbidden, action)
+	}
+
+	roles, err := a.Roles(ctx, actor, res)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		for _, want := range allowed {
+			if role == want {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrForbidden, action)
+}
@@ -0,0 +1,148 @@
+package authz_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tenders/db"
+	"tenders/internal/authz"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	org      = "7f9c2ba4-e88f-4a5c-9a1d-3b6c0e2f1d10"
+	otherOrg = "0b3e9d5a-4c2f-4e8b-8f1a-6d7c9e0a2b31"
+)
+
+// directory раздает роли по id сотрудника
+type directory struct {
+	platform    map[int]string
+	responsible map[int]bool
+	member      map[int]bool
+	err         error
+}
+
+func (d directory) GetPlatformRole(ctx context.Context, userID int) (string, error) {
+	return d.platform[userID], d.err
+}
+
+func (d directory) IsUserResponsibleForOrganization(ctx context.Context, userID int, organizationID string) (bool, error) {
+	return organizationID == org && d.responsible[userID], nil
+}
+
+func (d directory) IsUserMemberOfOrganization(ctx context.Context, userID int, organizationID string) (bool, error) {
+	return organizationID == org && d.member[userID], nil
+}
+
+var (
+	admin       = &db.Employee{ID: 1, Username: "admin"}
+	auditor     = &db.Employee{ID: 2, Username: "auditor"}
+	responsible = &db.Employee{ID: 3, Username: "responsible"}
+	member      = &db.Employee{ID: 4, Username: "member"}
+	bidder      = &db.Employee{ID: 5, Username: "bidder"}
+	stranger    = &db.Employee{ID: 6, Username: "stranger"}
+)
+
+func newAuthorizer() *authz.Authorizer {
+	return authz.New(directory{
+		platform:    map[int]string{admin.ID: authz.PlatformAdmin, auditor.ID: authz.PlatformAuditor},
+		responsible: map[int]bool{responsible.ID: true},
+		member:      map[int]bool{responsible.ID: true, member.ID: true},
+	})
+}
+
+func TestPermissionMatrix(t *testing.T) {
+	a := newAuthorizer()
+	tender := authz.Tender(&db.Tender{OrganizationID: org})
+	bid := authz.Bid(&db.Bid{OrganizationID: org, CreatorUsername: bidder.Username})
+
+	cases := []struct {
+		action  authz.Action
+		res     authz.Resource
+		allowed []*db.Employee
+	}{
+		{authz.TenderCreate, authz.Organization(org), []*db.Employee{admin, responsible}},
+		{authz.TenderView, tender, []*db.Employee{admin, auditor, responsible, member}},
+		{authz.TenderEdit, tender, []*db.Employee{admin, responsible}},
+		{authz.TenderPublish, tender, []*db.Employee{admin, responsible}},
+		{authz.TenderClose, tender, []*db.Employee{admin, responsible}},
+		{authz.TenderRollback, tender, []*db.Employee{admin, responsible}},
+		{authz.TenderHistory, tender, []*db.Employee{admin, auditor, responsible, member}},
+		{authz.TenderViewDraft, tender, []*db.Employee{admin, auditor, responsible}},
+		{authz.TenderBids, tender, []*db.Employee{admin, auditor, responsible}},
+		{authz.TenderArchive, tender, []*db.Employee{admin, responsible}},
+		{authz.BidCreate, authz.Organization(org), []*db.Employee{responsible, member}},
+		{authz.BidView, bid, []*db.Employee{admin, auditor, responsible, bidder}},
+		{authz.BidEdit, bid, []*db.Employee{responsible, bidder}},
+		{authz.BidPublish, bid, []*db.Employee{responsible, bidder}},
+		{authz.BidCancel, bid, []*db.Employee{responsible, bidder}},
+		{authz.BidRollback, bid, []*db.Employee{responsible}},
+		{authz.BidDecide, bid, []*db.Employee{responsible}},
+		{authz.BidHistory, bid, []*db.Employee{admin, auditor, responsible, bidder}},
+		{authz.BidArchive, bid, []*db.Employee{responsible}},
+		{authz.ReviewCreate, bid, []*db.Employee{responsible}},
+		{authz.ReviewView, tender, []*db.Employee{admin, auditor, responsible}},
+		{authz.PolicyView, authz.Organization(org), []*db.Employee{admin, auditor, responsible}},
+		{authz.PolicyManage, authz.Organization(org), []*db.Employee{admin, responsible}},
+		{authz.EmployeeView, authz.Platform(), []*db.Employee{admin, auditor}},
+		{authz.EmployeeManage, authz.Platform(), []*db.Employee{admin}},
+		{authz.OrganizationCreate, authz.Platform(), []*db.Employee{admin}},
+		{authz.OrganizationView, authz.Platform(), []*db.Employee{admin, auditor}},
+		{authz.OrganizationView, authz.Organization(org), []*db.Employee{admin, auditor, responsible, member}},
+		{authz.OrganizationManage, authz.Organization(org), []*db.Employee{admin, responsible}},
+		{authz.OrganizationDelete, authz.Organization(org), []*db.Employee{admin}},
+		{authz.ArchivePurge, authz.Platform(), []*db.Employee{admin}},
+	}
+
+	everyone := []*db.Employee{admin, auditor, responsible, member, bidder, stranger}
+	for _, c := range cases {
+		allowed := map[*db.Employee]bool{}
+		for _, e := range c.allowed {
+			allowed[e] = true
+		}
+		for _, e := range everyone {
+			err := a.Authorize(context.Background(), e, c.action, c.res)
+			if allowed[e] {
+				require.NoError(t, err, "%s should be allowed %s", e.Username, c.action)
+			} else {
+				require.ErrorIs(t, err, authz.ErrForbidden, "%s should not be allowed %s", e.Username, c.action)
+			}
+		}
+	}
+}
+
+func TestAuditorIsReadOnly(t *testing.T) {
+	a := newAuthorizer()
+	res := authz.Organization(org)
+	for _, action := range []authz.Action{
+		authz.TenderCreate, authz.TenderEdit, authz.TenderPublish, authz.TenderClose, authz.TenderRollback, authz.TenderArchive,
+		authz.BidCreate, authz.BidEdit, authz.BidPublish, authz.BidCancel, authz.BidRollback, authz.BidDecide, authz.BidArchive,
+		authz.ReviewCreate, authz.PolicyManage, authz.EmployeeManage,
+		authz.OrganizationCreate, authz.OrganizationManage, authz.OrganizationDelete, authz.ArchivePurge,
+	} {
+		require.ErrorIs(t, a.Authorize(context.Background(), auditor, action, res), authz.ErrForbidden, action)
+	}
+}
+
+func TestOtherOrganization(t *testing.T) {
+	a := newAuthorizer()
+	err := a.Authorize(context.Background(), responsible, authz.TenderEdit, authz.Organization(otherOrg))
+	require.ErrorIs(t, err, authz.ErrForbidden)
+}
+
+func TestDirectoryErrorIsNotForbidden(t *testing.T) {
+	failure := errors.New("db down")
+	a := authz.New(directory{err: failure})
+
+	err := a.Authorize(context.Background(), responsible, authz.TenderView, authz.Organization(org))
+	require.ErrorIs(t, err, failure)
+	require.NotErrorIs(t, err, authz.ErrForbidden)
+}
+
+func TestUnknownActionAndAnonymous(t *testing.T) {
+	a := newAuthorizer()
+	require.ErrorIs(t, a.Authorize(context.Background(), admin, "tender.fly", authz.Organization(org)), authz.ErrUnknownAction)
+	require.ErrorIs(t, a.Authorize(context.Background(), nil, authz.TenderView, authz.Organization(org)), authz.ErrForbidden)
+}
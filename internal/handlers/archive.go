@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/authz"
+)
+
+// Архив: тендеры и предложения не удаляются, а помечаются удаленными
+// (deletedAt, deletedBy). Они пропадают из списков и недоступны для
+// изменений, но остаются в БД вместе с версиями, голосами и отзывами, пока
+// администратор площадки не очистит архив.
+
+// defaultRetentionDays - сколько дней архив хранится, если срок не указан
+const defaultRetentionDays = 365
+
+// tenderWritable проверяет, что тендер не в архиве. Иначе отвечает 409 и
+// возвращает false.
+func tenderWritable(w http.ResponseWriter, tender *db.Tender) bool {
+	if tender.DeletedAt != nil {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, "Tender is archived")
+		return false
+	}
+	return true
+}
+
+// bidWritable проверяет, что предложение не в архиве. Иначе отвечает 409 и
+// возвращает false.
+func bidWritable(w http.ResponseWriter, bid *db.Bid) bool {
+	if bid.DeletedAt != nil {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, "Bid is archived")
+		return false
+	}
+	return true
+}
+
+// ArchiveTenderHandler помещает тендер в архив
+func (h *Handler) ArchiveTenderHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeTenderArchive(w, r, h.Store.ArchiveTender, "Tender is already archived")
+}
+
+// RestoreTenderHandler возвращает тендер из архива
+func (h *Handler) RestoreTenderHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeTenderArchive(w, r, h.Store.RestoreTender, "Tender is not archived")
+}
+
+// changeTenderArchive загружает тендер из пути, проверяет право на
+// архивирование и выполняет change (ArchiveTender или RestoreTender). Если
+// тендер уже в нужном состоянии, отвечает 409 с сообщением conflict.
+func (h *Handler) changeTenderArchive(w http.ResponseWriter, r *http.Request, change func(context.Context, *db.Tender) error, conflict string) {
+	tenderID, ok := h.pathID(w, r, "tenderId")
+	if !ok {
+		return
+	}
+
+	employee, ok := caller(w, r)
+	if !ok {
+		return
+	}
+
+	tender, err := h.Store.GetTender(r.Context(), tenderID)
+	if err != nil {
+		storageError(w, r, err, "Tender")
+		return
+	}
+
+	if !h.authorize(w, r, employee, authz.TenderArchive, authz.Tender(tender)) {
+		return
+	}
+
+	if err := change(db.WithAuthor(r.Context(), employee.ID), tender); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			apierr.Write(w, http.StatusConflict, apierr.InvalidState, conflict)
+			return
+		}
+		storageError(w, r, err, "Tender")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tender)
+}
+
+// ArchiveBidHandler помещает предложение в архив
+func (h *Handler) ArchiveBidHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeBidArchive(w, r, h.Store.ArchiveBid, "Bid is already archived")
+}
+
+// RestoreBidHandler возвращает предложение из архива
+func (h *Handler) RestoreBidHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeBidArchive(w, r, h.Store.RestoreBid, "Bid is not archived")
+}
+
+// changeBidArchive - changeTenderArchive для предложения
+func (h *Handler) changeBidArchive(w http.ResponseWriter, r *http.Request, change func(context.Context, *db.Bid) error, conflict string) {
+	bid, employee := h.bidAccess(w, r, authz.BidArchive)
+	if bid == nil {
+		return
+	}
+
+	if err := change(db.WithAuthor(r.Context(), employee.ID), bid); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			apierr.Write(w, http.StatusConflict, apierr.InvalidState, conflict)
+			return
+		}
+		storageError(w, r, err, "Bid")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bid)
+}
+
+// PurgeArchiveHandler окончательно удаляет тендеры, предложения и отзывы,
+// которые находятся в архиве дольше retentionDays дней (по умолчанию
+// defaultRetentionDays). Доступно только администратору площадки.
+func (h *Handler) PurgeArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	retentionDays := defaultRetentionDays
+	if s := r.URL.Query().Get("retentionDays"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil || days < 0 {
+			apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid retentionDays")
+			return
+		}
+		retentionDays = days
+	}
+
+	employee, ok := caller(w, r)
+	if !ok {
+		return
+	}
+
+	if !h.authorize(w, r, employee, authz.ArchivePurge, authz.Platform()) {
+		return
+	}
+
+	result, err := h.Store.PurgeArchived(r.Context(), time.Duration(retentionDays)*24*time.Hour)
+	if err != nil {
+		storageError(w, r, err, "Archive")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -1,43 +1,86 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
-	"strings"
+	"unicode/utf8"
 
 	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/approval"
+	"tenders/internal/authz"
+	"tenders/internal/lifecycle"
+	"tenders/internal/validate"
 
 	"github.com/go-chi/chi/v5"
 )
 
 func (h *Handler) CreateBidHandler(w http.ResponseWriter, r *http.Request) {
+	employee, ok := caller(w, r)
+	if !ok {
+		return
+	}
+
 	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Failed to read request body")
 		return
 	}
 	defer r.Body.Close()
 
 	var bid db.Bid
 	if err := json.Unmarshal(body, &bid); err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON format")
+		return
+	}
+
+	// Автор предложения - вызывающий, представиться другим пользователем нельзя
+	if bid.CreatorUsername == "" {
+		bid.CreatorUsername = employee.Username
+	}
+	if bid.CreatorUsername != employee.Username {
+		apierr.Write(w, http.StatusForbidden, apierr.Forbidden, "creatorUsername must match the authenticated user")
 		return
 	}
 
 	if err := validateBidRequest(&bid); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeValidationError(w, err)
+		return
+	}
+	if bid.TenderID, ok = h.parseID(w, r, "tenderId", bid.TenderID); !ok {
+		return
+	}
+	if bid.OrganizationID, ok = h.parseID(w, r, "organizationId", bid.OrganizationID); !ok {
+		return
+	}
+
+	if !h.authorize(w, r, employee, authz.BidCreate, authz.Organization(bid.OrganizationID)) {
+		return
+	}
+
+	// Предложение на тендер из архива не принимается. Несуществующий тендер
+	// отклонит хранилище.
+	tender, err := h.Store.GetTender(r.Context(), bid.TenderID)
+	if err != nil && !errors.Is(err, db.ErrNotFound) {
+		storageError(w, r, err, "Tender")
+		return
+	}
+	if err == nil && !tenderWritable(w, tender) {
 		return
 	}
 
-	bid.Status = "Created" // Статус при создании
+	bid.Status = lifecycle.BidCreated // Статус при создании
 
-	if err := h.Store.CreateBid(r.Context(), &bid); err != nil {
-		http.Error(w, "Failed to create bid", http.StatusInternalServerError)
+	// Создатель предложения - автор первой версии
+	if err := h.Store.CreateBid(db.WithAuthor(r.Context(), employee.ID), &bid); err != nil {
+		storageError(w, r, err, "Bid")
 		return
 	}
 
@@ -46,92 +89,78 @@ func (h *Handler) CreateBidHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(bid)
 }
 
+// validateBidRequest проверяет тело запроса на создание предложения.
+// Возвращает validate.Errors со всеми неверными полями.
 func validateBidRequest(b *db.Bid) error {
-	if b.Name == "" || len(b.Name) > 100 {
-		return errors.New("name is required and max length 100")
+	err := bidRules.Validate(b, bidCreateFields...)
+	if b.Status != "" && b.Status != lifecycle.BidCreated {
+		err = withFieldError(err, validate.FieldError{
+			Field: "status", Rule: "oneof", Message: "status must be 'Created' on creation",
+		})
 	}
-	if b.Description == "" || len(b.Description) > 500 {
-		return errors.New("description is required and max length 500")
-	}
-	if b.TenderID <= 0 {
-		return errors.New("tenderId must be positive")
-	}
-
-	if b.OrganizationID <= 0 {
-		return errors.New("organizationId must be positive")
-	}
-	if b.CreatorUsername == "" {
-		return errors.New("creatorUsername is required")
-	}
-	if b.Status != "" && b.Status != "Created" {
-		return errors.New("status must be 'Created' on creation")
-	}
-	return nil
+	return err
 }
 
 func (h *Handler) GetUserBidsHandler(w http.ResponseWriter, r *http.Request) {
-	params := parsePaginationParams(r)
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "Missing username parameter", http.StatusBadRequest)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
-	username = strings.TrimSpace(username)
 
-	bids, err := h.Store.GetUserBids(r.Context(), username, params.Limit, params.Offset)
+	bids, total, err := h.Store.GetUserBids(r.Context(), employee.Username, fetch(page))
 	if err != nil {
-		http.Error(w, "Failed to get user bids", http.StatusInternalServerError)
+		storageError(w, r, err, "Bids")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(bids)
+	writePage(w, r, page, bids, total, db.BidCursor)
 }
 
 func (h *Handler) GetBidsForTenderHandler(w http.ResponseWriter, r *http.Request) {
-	params := parsePaginationParams(r)
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
 
-	tenderIDStr := chi.URLParam(r, "tenderId")
-	tenderID, err := strconv.Atoi(tenderIDStr)
-	if err != nil {
-		http.Error(w, "Invalid tenderId", http.StatusBadRequest)
+	tenderID, ok := h.pathID(w, r, "tenderId")
+	if !ok {
 		return
 	}
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "Missing username parameter", http.StatusBadRequest)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
-	bids, err := h.Store.GetBidsForTender(r.Context(), tenderID, username, params.Limit, params.Offset)
+	bids, total, err := h.Store.GetBidsForTender(r.Context(), tenderID, employee.Username, fetch(page))
 	if err != nil {
-		http.Error(w, "Failed to get bids for tender", http.StatusInternalServerError)
+		storageError(w, r, err, "Bids")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(bids)
+	writePage(w, r, page, bids, total, db.BidCursor)
 }
 
 func (h *Handler) EditBidHandler(w http.ResponseWriter, r *http.Request) {
-	bidIDStr := chi.URLParam(r, "bidId")
-	bidID, err := strconv.Atoi(bidIDStr)
-	if err != nil || bidID <= 0 {
-		http.Error(w, "Invalid bidId", http.StatusBadRequest)
+	bidID, ok := h.pathID(w, r, "bidId")
+	if !ok {
 		return
 	}
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "Missing username parameter", http.StatusBadRequest)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Cannot read body", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Cannot read body")
 		return
 	}
 	defer r.Body.Close()
@@ -139,110 +168,158 @@ func (h *Handler) EditBidHandler(w http.ResponseWriter, r *http.Request) {
 	var input struct {
 		Name        *string `json:"name"`
 		Description *string `json:"description"`
-		Status      *string `json:"status"` // если нужно менять статус тут
+		Status      *string `json:"status"`
+		Version     *int    `json:"version"`
 	}
 
 	if err := json.Unmarshal(body, &input); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON")
+		return
+	}
+	// Статус меняется только через /bids/{bidId}/status, где его проверяет
+	// машина состояний
+	err = bidRules.Validate(&input, bidEditableFields...)
+	if input.Status != nil {
+		err = withFieldError(err, validate.FieldError{
+			Field: "status", Rule: "oneof", Message: "status cannot be edited, use PUT /bids/{bidId}/status",
+		})
+	}
+	if err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	expectedVersion, ok := editVersion(w, r, input.Version)
+	if !ok {
 		return
 	}
 
 	// Получаем предложение из БД
 	bid, err := h.Store.GetBid(r.Context(), bidID)
 	if err != nil {
-		http.Error(w, "Bid not found", http.StatusNotFound)
+		storageError(w, r, err, "Bid")
 		return
 	}
 
-	// Получаем пользователя по username
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	if !h.authorize(w, r, employee, authz.BidEdit, authz.Bid(bid)) || !bidWritable(w, bid) {
 		return
 	}
 
-	// Проверяем, что пользователь автор или ответственный за организацию предложения
-	if employee.Username != bid.CreatorUsername {
-		isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, bid.OrganizationID)
-		if err != nil || !isResponsible {
-			http.Error(w, "Forbidden", http.StatusForbidden)
-			return
-		}
+	if err := h.bids.CheckEdit(bid.Status); err != nil {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, err.Error())
+		return
+	}
+
+	if expectedVersion != bid.Version {
+		apierr.Write(w, http.StatusConflict, apierr.VersionConflict, fmt.Sprintf("Bid was modified: current version is %d", bid.Version))
+		return
 	}
 
 	// Обновляем поля, если они переданы
 	if input.Name != nil {
-		if len(*input.Name) == 0 || len(*input.Name) > 100 {
-			http.Error(w, "Invalid name length", http.StatusBadRequest)
-			return
-		}
 		bid.Name = *input.Name
 	}
 	if input.Description != nil {
-		if len(*input.Description) == 0 || len(*input.Description) > 500 {
-			http.Error(w, "Invalid description length", http.StatusBadRequest)
-			return
-		}
 		bid.Description = *input.Description
 	}
-	if input.Status != nil {
-		// Если хотите разрешить изменение статуса через этот хендлер, добавьте проверку статуса
-		// bid.Status = *input.Status
-	}
 
-	// Обновляем версию и дату обновления в методе UpdateBid
-	if err := h.Store.UpdateBid(r.Context(), bid); err != nil {
-		http.Error(w, "Failed to update bid", http.StatusInternalServerError)
+	// Версию, дату обновления и снимок в истории обновляет UpdateBid
+	if err := h.Store.UpdateBid(db.WithAuthor(r.Context(), employee.ID), bid); err != nil {
+		storageError(w, r, err, "Bid")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(bid.Version)))
 	json.NewEncoder(w).Encode(bid)
 }
 
+// GetBidStatusHandler возвращает текущий статус предложения, если оно видно вызывающему
+func (h *Handler) GetBidStatusHandler(w http.ResponseWriter, r *http.Request) {
+	bidID, ok := h.pathID(w, r, "bidId")
+	if !ok {
+		return
+	}
+
+	employee, ok := caller(w, r)
+	if !ok {
+		return
+	}
+
+	bid, err := h.Store.GetBid(r.Context(), bidID)
+	if err != nil {
+		storageError(w, r, err, "Bid")
+		return
+	}
+
+	if !h.bidVisible(w, r, employee, bid) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bid.Status)
+}
+
 func (h *Handler) UpdateBidStatusHandler(w http.ResponseWriter, r *http.Request) {
-	bidIDStr := chi.URLParam(r, "bidId")
-	bidID, err := strconv.Atoi(bidIDStr)
-	if err != nil || bidID <= 0 {
-		http.Error(w, "Invalid bidId", http.StatusBadRequest)
+	bidID, ok := h.pathID(w, r, "bidId")
+	if !ok {
 		return
 	}
 
 	status := r.URL.Query().Get("status")
-	username := r.URL.Query().Get("username")
-	if status == "" || username == "" {
-		http.Error(w, "Missing status or username", http.StatusBadRequest)
+	if status == "" {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Missing status")
 		return
 	}
 
-	validStatuses := map[string]bool{"Created": true, "Published": true, "Canceled": true, "Approved": true, "Rejected": true}
-	if !validStatuses[status] {
-		http.Error(w, "Invalid status value", http.StatusBadRequest)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
-	// Проверяем пользователя и права
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
+	bid, err := h.Store.GetBid(r.Context(), bidID)
 	if err != nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+		storageError(w, r, err, "Bid")
 		return
 	}
 
-	bid, err := h.Store.GetBid(r.Context(), bidID)
+	if !h.authorize(w, r, employee, bidStatusAction(status), authz.Bid(bid)) || !bidWritable(w, bid) {
+		return
+	}
+
+	// В каком качестве сотрудник меняет статус - решает машина состояний
+	roles, err := h.bidActorRoles(r.Context(), employee, bid)
+	if err != nil {
+		storageError(w, r, err, "Permissions")
+		return
+	}
+
+	tender, err := h.Store.GetTender(r.Context(), bid.TenderID)
 	if err != nil {
-		http.Error(w, "Bid not found", http.StatusNotFound)
+		storageError(w, r, err, "Tender")
 		return
 	}
 
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, bid.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	err = h.bids.Check(lifecycle.Request{
+		From:         bid.Status,
+		To:           status,
+		Actor:        roles,
+		TenderStatus: tender.Status,
+	})
+	if err != nil {
+		writeTransitionError(w, err)
 		return
 	}
 
 	bid.Status = status
-	if err := h.Store.UpdateBid(r.Context(), bid); err != nil {
-		http.Error(w, "Failed to update bid status", http.StatusInternalServerError)
+	err = h.Store.WithTx(db.WithAuthor(r.Context(), employee.ID), func(ctx context.Context) error {
+		if err := h.Store.UpdateBid(ctx, bid); err != nil {
+			return err
+		}
+		return h.bids.AfterTransition(ctx, bid.Status, bid)
+	})
+	if err != nil {
+		storageError(w, r, err, "Bid")
 		return
 	}
 
@@ -250,60 +327,88 @@ func (h *Handler) UpdateBidStatusHandler(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(bid)
 }
 func (h *Handler) RollbackBidHandler(w http.ResponseWriter, r *http.Request) {
-	bidIDStr := chi.URLParam(r, "bidId")
-	versionStr := chi.URLParam(r, "version")
-
-	bidID, err1 := strconv.Atoi(bidIDStr)
-	version, err2 := strconv.Atoi(versionStr)
-	if err1 != nil || err2 != nil || bidID <= 0 || version < 1 {
-		http.Error(w, "Invalid bidId or version", http.StatusBadRequest)
+	bidID, ok := h.pathID(w, r, "bidId")
+	if !ok {
 		return
 	}
-
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "Missing username", http.StatusBadRequest)
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid version")
 		return
 	}
 
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
 	currentBid, err := h.Store.GetBid(r.Context(), bidID)
 	if err != nil {
-		http.Error(w, "Bid not found", http.StatusNotFound)
+		storageError(w, r, err, "Bid")
 		return
 	}
 
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, currentBid.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	if !h.authorize(w, r, employee, authz.BidRollback, authz.Bid(currentBid)) || !bidWritable(w, currentBid) {
+		return
+	}
+
+	// Откат - такая же правка, как PATCH /edit: решенное предложение не меняется
+	if err := h.bids.CheckEdit(currentBid.Status); err != nil {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, err.Error())
 		return
 	}
 
 	versionBid, err := h.Store.GetBidVersion(r.Context(), bidID, version)
 	if err != nil {
-		http.Error(w, "Version not found", http.StatusNotFound)
+		storageError(w, r, err, "Version")
 		return
 	}
 
+	// Восстановление статуса из версии должно быть допустимым переходом
+	statusChanged := versionBid.Status != currentBid.Status
+	if statusChanged {
+		roles, err := h.bidActorRoles(r.Context(), employee, currentBid)
+		if err != nil {
+			storageError(w, r, err, "Permissions")
+			return
+		}
+		tender, err := h.Store.GetTender(r.Context(), currentBid.TenderID)
+		if err != nil {
+			storageError(w, r, err, "Tender")
+			return
+		}
+		err = h.bids.Check(lifecycle.Request{
+			From:         currentBid.Status,
+			To:           versionBid.Status,
+			Actor:        roles,
+			TenderStatus: tender.Status,
+		})
+		if err != nil {
+			writeTransitionError(w, err)
+			return
+		}
+	}
+
 	// Откатываем значения
 	currentBid.Name = versionBid.Name
 	currentBid.Description = versionBid.Description
 	currentBid.Status = versionBid.Status
-	currentBid.Version++ // Инкрементируем версию
+	// Откат - новая правка: версию и снимок в истории запишет UpdateBid
 
-	if err := h.Store.UpdateBid(r.Context(), currentBid); err != nil {
-		http.Error(w, "Failed to rollback bid", http.StatusInternalServerError)
+	err = h.Store.WithTx(db.WithAuthor(r.Context(), employee.ID), func(ctx context.Context) error {
+		if err := h.Store.UpdateBid(ctx, currentBid); err != nil {
+			return err
+		}
+		if statusChanged {
+			return h.bids.AfterTransition(ctx, currentBid.Status, currentBid)
+		}
+		return nil
+	})
+	if err != nil {
+		storageError(w, r, err, "Bid")
 		return
 	}
 
-	// Сохраняем новую версию после отката
-	_ = h.Store.SaveBidVersion(r.Context(), currentBid)
-
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(currentBid)
 }
@@ -311,128 +416,156 @@ func (h *Handler) RollbackBidHandler(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) SubmitBidDecisionHandler(w http.ResponseWriter, r *http.Request) {
 	bidIDStr := chi.URLParam(r, "bidId")
 	decision := r.URL.Query().Get("decision")
-	username := r.URL.Query().Get("username")
+	comment := r.URL.Query().Get("comment")
 
-	if bidIDStr == "" || decision == "" || username == "" {
-		http.Error(w, "Missing required parameters", http.StatusBadRequest)
+	if bidIDStr == "" || decision == "" {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Missing required parameters")
 		return
 	}
-
-	bidID, err := strconv.Atoi(bidIDStr)
-	if err != nil || (decision != "Approved" && decision != "Rejected") {
-		http.Error(w, "Invalid bidId or decision", http.StatusBadRequest)
+	if utf8.RuneCountInString(comment) > 1000 {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Comment is too long")
 		return
 	}
 
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	if decision != lifecycle.BidApproved && decision != lifecycle.BidRejected {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid decision")
 		return
 	}
-
-	bid, err := h.Store.GetBid(r.Context(), bidID)
-	if err != nil {
-		http.Error(w, "Bid not found", http.StatusNotFound)
+	bidID, ok := h.parseID(w, r, "bidId", bidIDStr)
+	if !ok {
 		return
 	}
 
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, bid.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
-	// Добавляем решение текущего пользователя в хранилище
-	err = h.Store.AddBidDecision(r.Context(), bid.ID, employee.ID, decision)
+	bid, err := h.Store.GetBid(r.Context(), bidID)
 	if err != nil {
-		http.Error(w, "Failed to submit decision", http.StatusInternalServerError)
+		storageError(w, r, err, "Bid")
 		return
 	}
 
-	// Подсчитываем количество одобрений и отклонений
-	accepts, rejects, err := h.Store.GetBidDecisionsCount(r.Context(), bid.ID)
-	if err != nil {
-		http.Error(w, "Failed to get decision counts", http.StatusInternalServerError)
+	if !h.authorizeDecision(w, r, employee, bid) || !bidWritable(w, bid) {
 		return
 	}
 
-	// Получаем количество ответственных за организацию
-	respCount, err := h.Store.GetResponsibleCount(r.Context(), bid.OrganizationID)
+	// Автор голоса, закрывшего тендер, попадает в историю версий тендера
+	bid, err = h.submitDecision(db.WithAuthor(r.Context(), employee.ID), bid.ID, employee.ID, decision, comment)
+	var te *lifecycle.TransitionError
+	if errors.As(err, &te) {
+		writeTransitionError(w, err)
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to get responsible count", http.StatusInternalServerError)
+		storageError(w, r, err, "Bid")
 		return
 	}
 
-	quorum := respCount
-	if quorum > 3 {
-		quorum = 3
-	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bid)
+}
 
-	// Логика постановки статуса согласно кворуму
-	switch {
-	case rejects > 0:
-		bid.Status = "Rejected"
-	case accepts >= quorum:
-		bid.Status = "Approved"
+// submitDecision записывает голос и пересчитывает итог по политике
+// принятия решений в одной транзакции.
+// Строки тендера и предложения блокируются (сначала тендер, затем предложение,
+// чтобы параллельные голосования по разным предложениям одного тендера не
+// взаимоблокировались), поэтому одновременные голоса обрабатываются по очереди:
+// кворум не теряется, а тендер закрывается ровно один раз.
+func (h *Handler) submitDecision(ctx context.Context, bidID string, employeeID int, decision, comment string) (*db.Bid, error) {
+	var result *db.Bid
+	err := h.Store.WithTx(ctx, func(ctx context.Context) error {
+		current, err := h.Store.GetBid(ctx, bidID)
+		if err != nil {
+			return fmt.Errorf("get bid: %w", err)
+		}
+		tender, err := h.Store.LockTender(ctx, current.TenderID)
+		if err != nil {
+			return fmt.Errorf("lock tender: %w", err)
+		}
+		bid, err := h.Store.LockBid(ctx, bidID)
+		if err != nil {
+			return fmt.Errorf("lock bid: %w", err)
+		}
 
-		tender, err := h.Store.GetTender(r.Context(), bid.TenderID)
-		if err == nil {
-			tender.Status = "Closed"
-			_ = h.Store.UpdateTender(r.Context(), tender)
+		// Голосовать можно только по предложению, которое может получить такое решение
+		err = h.bids.Check(lifecycle.Request{
+			From:         bid.Status,
+			To:           decision,
+			Actor:        lifecycle.RoleSystem,
+			TenderStatus: tender.Status,
+		})
+		if err != nil {
+			return err
 		}
-	default:
-		// Статус остаётся прежним, например, "Published"
-	}
 
-	err = h.Store.UpdateBid(r.Context(), bid)
+		if err := h.Store.AddBidDecision(ctx, bid.ID, employeeID, decision, comment); err != nil {
+			return fmt.Errorf("add decision: %w", err)
+		}
+
+		// Подводим итог по политике тендера или его организации
+		outcome, err := h.evaluateDecisions(ctx, bid, tender)
+		if err != nil {
+			return fmt.Errorf("evaluate decisions: %w", err)
+		}
+
+		result = bid
+		switch outcome {
+		case approval.Rejected:
+			bid.Status = lifecycle.BidRejected
+		case approval.Approved:
+			bid.Status = lifecycle.BidApproved
+		default:
+			// Решение еще не принято, статус остаётся "Published"
+			return nil
+		}
+
+		if err := h.Store.UpdateBid(ctx, bid); err != nil {
+			return fmt.Errorf("update bid: %w", err)
+		}
+		// Хук Approved закрывает тендер
+		return h.bids.AfterTransition(ctx, bid.Status, bid)
+	})
 	if err != nil {
-		http.Error(w, "Failed to update bid status", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(bid)
+	return result, nil
 }
 
 func (h *Handler) GetBidReviewsHandler(w http.ResponseWriter, r *http.Request) {
 	tenderIDStr := chi.URLParam(r, "tenderId")
 	authorUsername := r.URL.Query().Get("authorUsername")
-	requesterUsername := r.URL.Query().Get("requesterUsername")
 
-	if tenderIDStr == "" || authorUsername == "" || requesterUsername == "" {
-		http.Error(w, "Missing required parameters", http.StatusBadRequest)
+	if tenderIDStr == "" || authorUsername == "" {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Missing required parameters")
 		return
 	}
 
-	tenderID, err := strconv.Atoi(tenderIDStr)
-	if err != nil {
-		http.Error(w, "Invalid tenderId", http.StatusBadRequest)
+	tenderID, ok := h.parseID(w, r, "tenderId", tenderIDStr)
+	if !ok {
 		return
 	}
 
-	requester, err := h.Store.GetEmployeeByUsername(r.Context(), requesterUsername)
-	if err != nil {
-		http.Error(w, "Requester user not found", http.StatusUnauthorized)
+	requester, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
-	// Проверка прав: запросивший должен быть ответственным за организацию тендера
 	tender, err := h.Store.GetTender(r.Context(), tenderID)
 	if err != nil {
-		http.Error(w, "Tender not found", http.StatusNotFound)
+		storageError(w, r, err, "Tender")
 		return
 	}
 
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), requester.ID, tender.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	if !h.authorize(w, r, requester, authz.ReviewView, authz.Tender(tender)) {
 		return
 	}
 
 	// Получаем отзывы по предложениям автора, для указанного тендера
 	reviews, err := h.Store.GetBidReviewsByAuthorForTender(r.Context(), authorUsername, tenderID)
 	if err != nil {
-		http.Error(w, "Failed to get reviews", http.StatusInternalServerError)
+		storageError(w, r, err, "Reviews")
 		return
 	}
 
@@ -440,47 +573,40 @@ func (h *Handler) GetBidReviewsHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(reviews)
 }
 func (h *Handler) CreateBidFeedbackHandler(w http.ResponseWriter, r *http.Request) {
-	bidIDStr := chi.URLParam(r, "bidId")
-	username := r.URL.Query().Get("username")
 	feedback := r.URL.Query().Get("bidFeedback")
 
-	bidID, err := strconv.Atoi(bidIDStr)
-	if err != nil {
-		http.Error(w, "Invalid bidId", http.StatusBadRequest)
+	bidID, ok := h.pathID(w, r, "bidId")
+	if !ok {
 		return
 	}
 
-	if username == "" || feedback == "" {
-		http.Error(w, "Missing username or feedback", http.StatusBadRequest)
+	review := &db.BidReview{
+		BidID:       bidID,
+		Description: feedback,
+	}
+	if err := reviewRules.Validate(review, "description"); err != nil {
+		writeValidationError(w, err)
 		return
 	}
 
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
 	bid, err := h.Store.GetBid(r.Context(), bidID)
 	if err != nil {
-		http.Error(w, "Bid not found", http.StatusNotFound)
+		storageError(w, r, err, "Bid")
 		return
 	}
 
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, bid.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	if !h.authorize(w, r, employee, authz.ReviewCreate, authz.Bid(bid)) || !bidWritable(w, bid) {
 		return
 	}
 
-	review := &db.BidReview{
-		BidID:       bidID,
-		Description: feedback,
-	}
-
 	err = h.Store.CreateBidReview(r.Context(), review)
 	if err != nil {
-		http.Error(w, "Failed to submit feedback", http.StatusInternalServerError)
+		storageError(w, r, err, "Review")
 		return
 	}
 
@@ -489,3 +615,16 @@ func (h *Handler) CreateBidFeedbackHandler(w http.ResponseWriter, r *http.Reques
 }
 
 // Другие обработчики: редактирование, статус, откат версии, решения по предложению и отзывы можно сделать по аналогии с тендерами
+
+// bidStatusAction - право, нужное для перевода предложения в статус
+func bidStatusAction(status string) authz.Action {
+	switch status {
+	case lifecycle.BidPublished:
+		return authz.BidPublish
+	case lifecycle.BidCanceled:
+		return authz.BidCancel
+	default:
+		// Остальные переходы отклонит машина состояний
+		return authz.BidEdit
+	}
+}
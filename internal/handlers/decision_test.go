@@ -0,0 +1,279 @@
+package handlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"tenders/db"
+	"tenders/db/memory"
+	"tenders/internal/handlers"
+	"tenders/internal/handlers/testutils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// votingStorage хранит тендер и предложение в памяти и эмулирует блокировки
+// строк: WithTx сам ничего не сериализует, транзакции упорядочивают только
+// LockTender и LockBid, которые держат мьютекс строки до конца WithTx. Так
+// тест проверяет, что обработчик действительно берет блокировки.
+type votingStorage struct {
+	MockStorage
+
+	tenderMu, bidMu sync.Mutex
+	// mu защищает поля ниже от одновременного доступа
+	mu           sync.Mutex
+	tender       db.Tender
+	bid          db.Bid
+	decisions    map[int]string
+	responsibles int
+	tenderClosed int
+}
+
+// votingTx - состояние транзакции votingStorage: взятые блокировки и снимок
+// данных для отката, сделанный при первой блокировке
+type votingTx struct {
+	unlock   []func()
+	rollback func()
+}
+
+type votingTxKey struct{}
+
+func (s *votingStorage) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx := &votingTx{}
+	defer func() {
+		for i := len(tx.unlock) - 1; i >= 0; i-- {
+			tx.unlock[i]()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, votingTxKey{}, tx)); err != nil {
+		if tx.rollback != nil {
+			tx.rollback()
+		}
+		return err
+	}
+	return nil
+}
+
+// lockRow берет блокировку строки до конца транзакции из ctx. Данные
+// меняются только под блокировкой, поэтому снимка при первой блокировке
+// достаточно для отката.
+func (s *votingStorage) lockRow(ctx context.Context, mu *sync.Mutex) {
+	tx, ok := ctx.Value(votingTxKey{}).(*votingTx)
+	if !ok {
+		panic("votingStorage: lock outside of WithTx")
+	}
+	mu.Lock()
+	tx.unlock = append(tx.unlock, mu.Unlock)
+	if tx.rollback != nil {
+		return
+	}
+
+	s.mu.Lock()
+	tender, bid := s.tender, s.bid
+	decisions := make(map[int]string, len(s.decisions))
+	for k, v := range s.decisions {
+		decisions[k] = v
+	}
+	closed := s.tenderClosed
+	s.mu.Unlock()
+
+	tx.rollback = func() {
+		s.mu.Lock()
+		s.tender, s.bid, s.decisions, s.tenderClosed = tender, bid, decisions, closed
+		s.mu.Unlock()
+	}
+}
+
+func (s *votingStorage) IsUserResponsibleForOrganization(ctx context.Context, userID int, organizationID string) (bool, error) {
+	return true, nil
+}
+
+func (s *votingStorage) GetTender(ctx context.Context, tenderID string) (*db.Tender, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tender
+	return &t, nil
+}
+
+func (s *votingStorage) LockTender(ctx context.Context, tenderID string) (*db.Tender, error) {
+	s.lockRow(ctx, &s.tenderMu)
+	return s.GetTender(ctx, tenderID)
+}
+
+func (s *votingStorage) UpdateTender(ctx context.Context, t *db.Tender) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t.Version != s.tender.Version {
+		return db.ErrVersionConflict
+	}
+	if t.Status == "Closed" && s.tender.Status != "Closed" {
+		s.tenderClosed++
+	}
+	t.Version++
+	s.tender = *t
+	return nil
+}
+
+func (s *votingStorage) GetBid(ctx context.Context, bidID string) (*db.Bid, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.bid
+	return &b, nil
+}
+
+func (s *votingStorage) LockBid(ctx context.Context, bidID string) (*db.Bid, error) {
+	s.lockRow(ctx, &s.bidMu)
+	return s.GetBid(ctx, bidID)
+}
+
+func (s *votingStorage) UpdateBid(ctx context.Context, b *db.Bid) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b.Version++
+	s.bid = *b
+	return nil
+}
+
+func (s *votingStorage) AddBidDecision(ctx context.Context, bidID string, employeeID int, decision, comment string) error {
+	// Задержка запроса к БД расширяет окно гонки между проверкой статуса и
+	// подсчетом голосов: без блокировок строк лишние голоса успеют попасть в
+	// итог
+	time.Sleep(time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decisions[employeeID] = decision
+	return nil
+}
+
+func (s *votingStorage) GetBidDecisions(ctx context.Context, bidID string) ([]db.BidDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	decisions := make([]db.BidDecision, 0, len(s.decisions))
+	for userID, d := range s.decisions {
+		decisions = append(decisions, db.BidDecision{BidID: bidID, UserID: userID, Decision: d})
+	}
+	return decisions, nil
+}
+
+func (s *votingStorage) GetResponsibles(ctx context.Context, organizationID string) ([]db.Responsible, error) {
+	responsibles := make([]db.Responsible, s.responsibles)
+	for i := range responsibles {
+		responsibles[i] = db.Responsible{UserID: i + 1, OrganizationID: organizationID, Role: "member"}
+	}
+	return responsibles, nil
+}
+
+func TestSubmitBidDecisionConcurrent(t *testing.T) {
+	const voters = 20
+
+	store := &votingStorage{
+		tender:       db.Tender{ID: testID(1), Status: "Published", OrganizationID: testID(1), Version: 1},
+		bid:          db.Bid{ID: testID(1), Status: "Published", TenderID: testID(1), OrganizationID: testID(1), Version: 1},
+		decisions:    map[int]string{},
+		responsibles: voters,
+	}
+	handler := handlers.NewHandler(store)
+
+	codes := make([]int, voters)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < voters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(1)+"/submit_decision?decision=Approved", nil)
+			req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+			req = testutils.WithEmployee(req, &db.Employee{ID: i + 1, Username: fmt.Sprintf("resp%d", i+1)})
+			w := httptest.NewRecorder()
+			handler.SubmitBidDecisionHandler(w, req)
+			codes[i] = w.Code
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	var ok, conflict int
+	for _, c := range codes {
+		switch c {
+		case http.StatusOK:
+			ok++
+		case http.StatusConflict:
+			conflict++
+		default:
+			t.Fatalf("unexpected status %d", c)
+		}
+	}
+
+	// Кворум - 3 голоса: ровно три голоса учтены, остальные пришли после решения
+	require.Equal(t, 3, ok)
+	require.Equal(t, voters-3, conflict)
+	require.Len(t, store.decisions, 3)
+	require.Equal(t, "Approved", store.bid.Status)
+	require.Equal(t, "Closed", store.tender.Status)
+	require.Equal(t, 1, store.tenderClosed)
+}
+
+// Предложение решает организация тендера: голосуют ее ответственные и
+// действует политика тендера, а не организации предложения
+func TestSubmitBidDecisionUsesTenderPolicy(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	newOrg := func(name string, responsibles int) (*db.Organization, []*db.Employee) {
+		org := &db.Organization{Name: name, Type: db.OrganizationLLC}
+		require.NoError(t, store.CreateOrganization(ctx, org))
+		employees := make([]*db.Employee, responsibles)
+		for i := range employees {
+			employees[i] = &db.Employee{Username: fmt.Sprintf("%s-%d", name, i)}
+			require.NoError(t, store.CreateEmployee(ctx, employees[i]))
+			require.NoError(t, store.AddResponsible(ctx, org.ID, employees[i].ID, "member"))
+		}
+		return org, employees
+	}
+	customer, voters := newOrg("customer", 3)
+	bidder, bidders := newOrg("bidder", 1)
+
+	tender := &db.Tender{Name: "Tender", Description: "d", ServiceType: "Delivery", Status: "Published", OrganizationID: customer.ID}
+	require.NoError(t, store.CreateTender(ctx, tender))
+	bid := &db.Bid{Name: "Bid", Description: "d", Status: "Published", TenderID: tender.ID, OrganizationID: bidder.ID, CreatorUsername: bidders[0].Username}
+	require.NoError(t, store.CreateBid(ctx, bid))
+	// Политика тендера требует два одобрения, политика организации предложения - одно
+	require.NoError(t, store.SaveApprovalPolicy(ctx, &db.ApprovalPolicy{
+		OrganizationID: customer.ID, TenderID: &tender.ID, Kind: "fixed", Required: 2, RejectThreshold: 1, RoleWeights: db.RoleWeights{},
+	}))
+	require.NoError(t, store.SaveApprovalPolicy(ctx, &db.ApprovalPolicy{
+		OrganizationID: bidder.ID, Kind: "fixed", Required: 1, RejectThreshold: 1, RoleWeights: db.RoleWeights{},
+	}))
+
+	handler := handlers.NewHandler(store)
+	decide := func(employee *db.Employee) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/bids/"+bid.ID+"/submit_decision?decision=Approved", nil)
+		req = testutils.WithChiURLParams(req, map[string]string{"bidId": bid.ID})
+		req = testutils.WithEmployee(req, employee)
+		w := httptest.NewRecorder()
+		handler.SubmitBidDecisionHandler(w, req)
+		return w
+	}
+
+	// Ответственный организации предложения не голосует по своему предложению
+	w := decide(bidders[0])
+	require.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	w = decide(voters[0])
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	stored, err := store.GetBid(ctx, bid.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Published", stored.Status)
+
+	w = decide(voters[1])
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	stored, err = store.GetBid(ctx, bid.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Approved", stored.Status)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/authz"
+	"tenders/internal/lifecycle"
+)
+
+// errBidDecided - по предложению уже принято решение, голоса менять нельзя
+var errBidDecided = errors.New("bid is already decided")
+
+// bidAccess загружает предложение из пути и проверяет право вызывающего на action.
+// Пишет ошибку в ответ и возвращает nil, если доступа нет.
+func (h *Handler) bidAccess(w http.ResponseWriter, r *http.Request, action authz.Action) (*db.Bid, *db.Employee) {
+	bidID, ok := h.pathID(w, r, "bidId")
+	if !ok {
+		return nil, nil
+	}
+
+	employee, ok := caller(w, r)
+	if !ok {
+		return nil, nil
+	}
+
+	bid, err := h.Store.GetBid(r.Context(), bidID)
+	if err != nil {
+		storageError(w, r, err, "Bid")
+		return nil, nil
+	}
+
+	if !h.authorize(w, r, employee, action, authz.Bid(bid)) {
+		return nil, nil
+	}
+	return bid, employee
+}
+
+// authorizeDecision проверяет право вызывающего голосовать по предложению.
+// Предложение решает организация тендера, поэтому право проверяется на тендере.
+// Пишет ошибку в ответ и возвращает false, если права нет.
+func (h *Handler) authorizeDecision(w http.ResponseWriter, r *http.Request, employee *db.Employee, bid *db.Bid) bool {
+	tender, err := h.Store.GetTender(r.Context(), bid.TenderID)
+	if err != nil {
+		storageError(w, r, err, "Tender")
+		return false
+	}
+	return h.authorize(w, r, employee, authz.BidDecide, authz.Tender(tender))
+}
+
+// GetBidDecisionsHandler возвращает действующие голоса по предложению
+func (h *Handler) GetBidDecisionsHandler(w http.ResponseWriter, r *http.Request) {
+	bid, _ := h.bidAccess(w, r, authz.BidView)
+	if bid == nil {
+		return
+	}
+
+	decisions, err := h.Store.GetBidDecisions(r.Context(), bid.ID)
+	if err != nil {
+		storageError(w, r, err, "Decisions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(decisions)
+}
+
+// GetBidDecisionHistoryHandler возвращает переписанные и отозванные голоса
+func (h *Handler) GetBidDecisionHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	bid, _ := h.bidAccess(w, r, authz.BidHistory)
+	if bid == nil {
+		return
+	}
+
+	history, err := h.Store.GetBidDecisionHistory(r.Context(), bid.ID)
+	if err != nil {
+		storageError(w, r, err, "Decision history")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// RevokeBidDecisionHandler отзывает голос пользователя, пока по предложению нет решения
+func (h *Handler) RevokeBidDecisionHandler(w http.ResponseWriter, r *http.Request) {
+	bidID, ok := h.pathID(w, r, "bidId")
+	if !ok {
+		return
+	}
+
+	employee, ok := caller(w, r)
+	if !ok {
+		return
+	}
+
+	bid, err := h.Store.GetBid(r.Context(), bidID)
+	if err != nil {
+		storageError(w, r, err, "Bid")
+		return
+	}
+
+	if !h.authorizeDecision(w, r, employee, bid) || !bidWritable(w, bid) {
+		return
+	}
+
+	err = h.revokeDecision(r.Context(), bid.ID, employee.ID)
+	switch {
+	case errors.Is(err, errBidDecided):
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, err.Error())
+		return
+	case err != nil:
+		storageError(w, r, err, "Decision")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokeDecision удаляет голос под теми же блокировками, что и submitDecision,
+// чтобы отзыв не пересекся с подведением итога.
+func (h *Handler) revokeDecision(ctx context.Context, bidID string, employeeID int) error {
+	return h.Store.WithTx(ctx, func(ctx context.Context) error {
+		current, err := h.Store.GetBid(ctx, bidID)
+		if err != nil {
+			return fmt.Errorf("get bid: %w", err)
+		}
+		if _, err := h.Store.LockTender(ctx, current.TenderID); err != nil {
+			return fmt.Errorf("lock tender: %w", err)
+		}
+		bid, err := h.Store.LockBid(ctx, bidID)
+		if err != nil {
+			return fmt.Errorf("lock bid: %w", err)
+		}
+		if bid.Status != lifecycle.BidPublished {
+			return fmt.Errorf("%w: status is %s", errBidDecided, bid.Status)
+		}
+		return h.Store.RevokeBidDecision(ctx, bidID, employeeID)
+	})
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/authz"
+	"tenders/internal/validate"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// usernamePattern - допустимые символы username. Длину проверяет тег модели.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]*$`)
+
+// validateEmployee проверяет поля сотрудника по правилам models.Employee.
+// Возвращает validate.Errors со всеми неверными полями.
+func validateEmployee(e *db.Employee) error {
+	err := employeeRules.Validate(e)
+	if !usernamePattern.MatchString(e.Username) {
+		err = withFieldError(err, validate.FieldError{
+			Field: "username", Rule: "pattern", Message: "username may contain only letters, digits, '_', '.', '-'",
+		})
+	}
+	return err
+}
+
+// employeeFromPath загружает сотрудника по username из пути
+func (h *Handler) employeeFromPath(w http.ResponseWriter, r *http.Request) (*db.Employee, bool) {
+	employee, err := h.Store.GetEmployeeByUsername(r.Context(), chi.URLParam(r, "username"))
+	if err != nil {
+		storageError(w, r, err, "Employee")
+		return nil, false
+	}
+	return employee, true
+}
+
+// CreateEmployeeHandler заводит нового сотрудника
+func (h *Handler) CreateEmployeeHandler(w http.ResponseWriter, r *http.Request) {
+	admin, ok := caller(w, r)
+	if !ok || !h.authorize(w, r, admin, authz.EmployeeManage, authz.Platform()) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	var employee db.Employee
+	if err := json.Unmarshal(body, &employee); err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON format")
+		return
+	}
+	employee.Username = strings.TrimSpace(employee.Username)
+	if err := validateEmployee(&employee); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	if _, err := h.Store.GetEmployeeByUsername(r.Context(), employee.Username); err == nil {
+		apierr.Write(w, http.StatusConflict, apierr.AlreadyExists, "Username is already taken")
+		return
+	}
+
+	if err := h.Store.CreateEmployee(r.Context(), &employee); err != nil {
+		storageError(w, r, err, "Employee")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employee)
+}
+
+// GetEmployeeHandler возвращает сотрудника по username
+func (h *Handler) GetEmployeeHandler(w http.ResponseWriter, r *http.Request) {
+	viewer, ok := caller(w, r)
+	if !ok || !h.authorize(w, r, viewer, authz.EmployeeView, authz.Platform()) {
+		return
+	}
+
+	employee, ok := h.employeeFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employee)
+}
+
+// ListEmployeesHandler ищет сотрудников по подстроке search в username, имени или фамилии
+func (h *Handler) ListEmployeesHandler(w http.ResponseWriter, r *http.Request) {
+	viewer, ok := caller(w, r)
+	if !ok || !h.authorize(w, r, viewer, authz.EmployeeView, authz.Platform()) {
+		return
+	}
+	page, ok := parseIntKeyPage(w, r)
+	if !ok {
+		return
+	}
+	search := strings.TrimSpace(r.URL.Query().Get("search"))
+	includeInactive := r.URL.Query().Get("includeInactive") == "true"
+
+	employees, total, err := h.Store.ListEmployees(r.Context(), search, includeInactive, fetch(page))
+	if err != nil {
+		storageError(w, r, err, "Employees")
+		return
+	}
+
+	writePage(w, r, page, employees, total, db.EmployeeCursor)
+}
+
+// UpdateEmployeeHandler меняет имя и фамилию сотрудника. Username не меняется.
+func (h *Handler) UpdateEmployeeHandler(w http.ResponseWriter, r *http.Request) {
+	admin, ok := caller(w, r)
+	if !ok || !h.authorize(w, r, admin, authz.EmployeeManage, authz.Platform()) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Cannot read body")
+		return
+	}
+	defer r.Body.Close()
+
+	var input struct {
+		FirstName *string `json:"firstName"`
+		LastName  *string `json:"lastName"`
+	}
+	if err := json.Unmarshal(body, &input); err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON")
+		return
+	}
+
+	employee, ok := h.employeeFromPath(w, r)
+	if !ok {
+		return
+	}
+	if input.FirstName != nil {
+		employee.FirstName = *input.FirstName
+	}
+	if input.LastName != nil {
+		employee.LastName = *input.LastName
+	}
+	if err := validateEmployee(employee); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	if err := h.Store.UpdateEmployee(r.Context(), employee); err != nil {
+		storageError(w, r, err, "Employee")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employee)
+}
+
+// DeactivateEmployeeHandler запрещает сотруднику входить в систему.
+// Сотрудник остается в истории голосов и версий.
+func (h *Handler) DeactivateEmployeeHandler(w http.ResponseWriter, r *http.Request) {
+	admin, ok := caller(w, r)
+	if !ok || !h.authorize(w, r, admin, authz.EmployeeManage, authz.Platform()) {
+		return
+	}
+
+	username := chi.URLParam(r, "username")
+	if username == admin.Username {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, "Cannot deactivate yourself")
+		return
+	}
+
+	err := h.Store.DeactivateEmployee(r.Context(), username)
+	if err != nil {
+		storageError(w, r, err, "Employee")
+		return
+	}
+
+	employee, ok := h.employeeFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employee)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+)
+
+// storageError отвечает клиенту по ошибке хранилища. subject - что искали
+// ("Tender", "Bid", ...), оно попадает в текст ответа:
+//   - db.ErrNotFound - 404
+//   - db.ErrVersionConflict, db.ErrConflict, db.ErrUniqueViolation - 409
+//   - db.ErrForeignKey - 422
+//
+// Остальные ошибки - сбой сервера: они пишутся в лог, клиент получает 500
+// без подробностей.
+func storageError(w http.ResponseWriter, r *http.Request, err error, subject string) {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		apierr.Write(w, http.StatusNotFound, apierr.NotFound, subject+" not found")
+	case errors.Is(err, db.ErrVersionConflict):
+		apierr.Write(w, http.StatusConflict, apierr.VersionConflict, subject+" was modified concurrently")
+	case errors.Is(err, db.ErrUniqueViolation):
+		apierr.Write(w, http.StatusConflict, apierr.AlreadyExists, subject+" already exists")
+	case errors.Is(err, db.ErrConflict):
+		apierr.Write(w, http.StatusConflict, apierr.Conflict, subject+" conflicts with a concurrent change, retry the request")
+	case errors.Is(err, db.ErrForeignKey):
+		apierr.Write(w, http.StatusUnprocessableEntity, apierr.InvalidReference, subject+" references a missing or dependent record")
+	default:
+		log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+		apierr.Write(w, http.StatusInternalServerError, apierr.Internal, "Internal server error")
+	}
+}
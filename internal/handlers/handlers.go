@@ -6,54 +6,111 @@ import (
 	"io"
 	"net/http"
 	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/auth"
+	"tenders/internal/authz"
+	"tenders/internal/lifecycle"
+	"tenders/internal/validate"
 )
 
 // Handler оборачивает Storage для доступа к данным
 type Handler struct {
 	Store StorageInterface
+
+	tenders *lifecycle.Machine[*db.Tender]
+	bids    *lifecycle.Machine[*db.Bid]
+	authz   *authz.Authorizer
 }
 
 // NewHandler создает новый Handler
 func NewHandler(store StorageInterface) *Handler {
-	return &Handler{Store: store}
+	h := &Handler{
+		Store:   store,
+		authz:   authz.New(store),
+		tenders: lifecycle.NewTenderMachine(),
+		bids:    lifecycle.NewBidMachine(),
+	}
+	// Одобрение предложения закрывает тендер
+	h.bids.OnEnter(lifecycle.BidApproved, h.closeTenderOnApproval)
+	return h
+}
+
+// caller возвращает сотрудника, от имени которого выполняется запрос
+// (его кладет в контекст auth.Middleware). Для анонимного запроса отвечает 401.
+func caller(w http.ResponseWriter, r *http.Request) (*db.Employee, bool) {
+	employee, ok := auth.EmployeeFrom(r.Context())
+	if !ok {
+		apierr.Write(w, http.StatusUnauthorized, apierr.Unauthorized, "Authentication required")
+		return nil, false
+	}
+	return employee, true
+}
+
+// authorize проверяет право сотрудника на действие над ресурсом.
+// Отвечает 403, если права нет, иначе передает ошибку загрузки ролей в storageError.
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request, employee *db.Employee, action authz.Action, res authz.Resource) bool {
+	err := h.authz.Authorize(r.Context(), employee, action, res)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, authz.ErrForbidden):
+		apierr.Write(w, http.StatusForbidden, apierr.Forbidden, "Forbidden")
+	default:
+		storageError(w, r, err, "Permissions")
+	}
+	return false
 }
 
 // PingHandler отвечает "ok" для проверки сервера
 func (h *Handler) PingHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("ok"))
 }
 
 // CreateTenderHandler обрабатывает POST /api/tenders/new запрос
 func (h *Handler) CreateTenderHandler(w http.ResponseWriter, r *http.Request) {
+	employee, ok := caller(w, r)
+	if !ok {
+		return
+	}
+
 	// Ограничение размера тела, чтобы избежать DoS
 	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Failed to read request body")
 		return
 	}
 	defer r.Body.Close()
 
 	var tender db.Tender
 	if err := json.Unmarshal(body, &tender); err != nil {
-		http.Error(w, "Invalid JSON format", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON format")
 		return
 	}
 
-	// Валидация полей согласно OpenAPI (можно расширить)
+	// Валидация полей по правилам models.Tender
 	if err := validateTenderRequest(&tender); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeValidationError(w, err)
+		return
+	}
+	if tender.OrganizationID, ok = h.parseID(w, r, "organizationId", tender.OrganizationID); !ok {
+		return
+	}
+
+	if !h.authorize(w, r, employee, authz.TenderCreate, authz.Organization(tender.OrganizationID)) {
 		return
 	}
 
 	// Статус должен быть "Created" при создании (по требованиям)
-	tender.Status = "Created"
+	tender.Status = lifecycle.TenderCreated
 	// Версия устанавливается в CreateTender (1), так что не надо менять
 
-	if err := h.Store.CreateTender(r.Context(), &tender); err != nil {
-		http.Error(w, "Failed to create tender", http.StatusInternalServerError)
+	// Создатель тендера - автор первой версии
+	if err := h.Store.CreateTender(db.WithAuthor(r.Context(), employee.ID), &tender); err != nil {
+		storageError(w, r, err, "Tender")
 		return
 	}
 
@@ -62,25 +119,14 @@ func (h *Handler) CreateTenderHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(tender)
 }
 
-// validateTenderRequest проверяет необходимые поля по спецификации
+// validateTenderRequest проверяет тело запроса на создание тендера.
+// Возвращает validate.Errors со всеми неверными полями.
 func validateTenderRequest(t *db.Tender) error {
-	if t.Name == "" || len(t.Name) > 100 {
-		return errors.New("name is required and max length 100")
-	}
-	if t.Description == "" || len(t.Description) > 500 {
-		return errors.New("description is required and max length 500")
-	}
-	switch t.ServiceType {
-	case "Construction", "Delivery", "Manufacture":
-		// ok
-	default:
-		return errors.New("invalid serviceType")
-	}
-	if t.OrganizationID <= 0 {
-		return errors.New("organizationId must be positive")
-	}
-	if t.Status != "" && t.Status != "Created" {
-		return errors.New("status must be 'Created' on creation")
+	err := tenderRules.Validate(t, tenderCreateFields...)
+	if t.Status != "" && t.Status != lifecycle.TenderCreated {
+		err = withFieldError(err, validate.FieldError{
+			Field: "status", Rule: "oneof", Message: "status must be 'Created' on creation",
+		})
 	}
-	return nil
+	return err
 }
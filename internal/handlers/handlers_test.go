@@ -2,15 +2,22 @@ package handlers_test
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"tenders/db"
+	"tenders/db/memory"
+	"tenders/internal/apierr"
 	"tenders/internal/handlers"
 	"tenders/internal/handlers/testutils"
+	"tenders/models"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -19,51 +26,119 @@ import (
 type MockStorage struct {
 	employee             *db.Employee
 	responsible          bool
+	platformRole         string
 	createTenderErr      error
-	GetTendersFunc       func(ctx context.Context, serviceTypes []string, limit, offset int) ([]db.Tender, error)
-	GetBidFunc           func(ctx context.Context, bidID int) (*db.Bid, error)
-	GetUserBidsFunc      func(ctx context.Context, username string, limit, offset int) ([]db.Bid, error)
-	GetBidsForTenderFunc func(ctx context.Context, tenderID int, username string, limit, offset int) ([]db.Bid, error)
+	policy               *db.ApprovalPolicy
+	GetTenderFunc        func(ctx context.Context, tenderID string) (*db.Tender, error)
+	GetTendersFunc       func(ctx context.Context, serviceTypes []string, page db.Page) ([]db.Tender, int, error)
+	GetBidFunc           func(ctx context.Context, bidID string) (*db.Bid, error)
+	GetUserBidsFunc      func(ctx context.Context, username string, page db.Page) ([]db.Bid, int, error)
+	GetBidsForTenderFunc func(ctx context.Context, tenderID string, username string, page db.Page) ([]db.Bid, int, error)
+	legacyIDs            map[int]string
+	purgedRetention      time.Duration
+}
+
+// testID возвращает UUID тендера, предложения или организации с номером n
+func testID(n int) string {
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", n)
+}
+
+func (m *MockStorage) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *MockStorage) ResolveLegacyID(ctx context.Context, table string, legacyID int) (string, error) {
+	id, ok := m.legacyIDs[legacyID]
+	if !ok {
+		return "", db.ErrNotFound
+	}
+	return id, nil
 }
 
 func (m *MockStorage) GetEmployeeByUsername(ctx context.Context, username string) (*db.Employee, error) {
-	if m.employee == nil {
-		return nil, errors.New("not found")
+	if m.employee == nil || m.employee.Username != username {
+		return nil, db.ErrNotFound
 	}
 	return m.employee, nil
 }
-func (m *MockStorage) IsUserResponsibleForOrganization(ctx context.Context, userID, organizationID int) (bool, error) {
+func (m *MockStorage) CreateEmployee(ctx context.Context, employee *db.Employee) error {
+	employee.ID = 100
+	return nil
+}
+func (m *MockStorage) ListEmployees(ctx context.Context, search string, includeInactive bool, page db.Page) ([]db.Employee, int, error) {
+	return []db.Employee{*m.employee}, 1, nil
+}
+func (m *MockStorage) UpdateEmployee(ctx context.Context, employee *db.Employee) error { return nil }
+func (m *MockStorage) DeactivateEmployee(ctx context.Context, username string) error {
+	if username != "ghost" {
+		return nil
+	}
+	return db.ErrNotFound
+}
+func (m *MockStorage) IsUserResponsibleForOrganization(ctx context.Context, userID int, organizationID string) (bool, error) {
+	return m.responsible, nil
+}
+func (m *MockStorage) IsUserMemberOfOrganization(ctx context.Context, userID int, organizationID string) (bool, error) {
 	return m.responsible, nil
 }
+func (m *MockStorage) GetPlatformRole(ctx context.Context, userID int) (string, error) {
+	return m.platformRole, nil
+}
 
 func (m *MockStorage) CreateTender(ctx context.Context, tender *db.Tender) error {
-	return m.createTenderErr
+	if m.createTenderErr != nil {
+		return m.createTenderErr
+	}
+	tender.ID = testID(1)
+	tender.Version = 1
+	return nil
+}
+
+func (m *MockStorage) GetTender(ctx context.Context, tenderID string) (*db.Tender, error) {
+	if m.GetTenderFunc != nil {
+		return m.GetTenderFunc(ctx, tenderID)
+	}
+	return &db.Tender{
+		ID:             tenderID,
+		Name:           "Test Tender",
+		Description:    "Tender Description",
+		ServiceType:    "Construction",
+		Status:         "Published",
+		OrganizationID: testID(1),
+		Version:        2,
+	}, nil
 }
 
-func (m *MockStorage) GetTender(ctx context.Context, tenderID int) (*db.Tender, error) {
-	return &db.Tender{ID: tenderID, Name: "Test Tender", OrganizationID: 1}, nil
+func (m *MockStorage) LockTender(ctx context.Context, tenderID string) (*db.Tender, error) {
+	return m.GetTender(ctx, tenderID)
 }
 
 func (m *MockStorage) UpdateTender(ctx context.Context, tender *db.Tender) error      { return nil }
 func (m *MockStorage) SaveTenderVersion(ctx context.Context, tender *db.Tender) error { return nil }
-func (m *MockStorage) GetTenderVersion(ctx context.Context, tenderID int, version int) (*db.Tender, error) {
-	return &db.Tender{ID: tenderID, Name: "Tender Version"}, nil
+func (m *MockStorage) GetTenderVersion(ctx context.Context, tenderID string, version int) (*db.Tender, error) {
+	if version > 2 {
+		return nil, db.ErrNotFound
+	}
+	return &db.Tender{ID: tenderID, Name: "Tender Version", Description: fmt.Sprintf("v%d", version), Status: "Published", Version: version}, nil
 }
-func (m *MockStorage) GetTenders(ctx context.Context, serviceTypes []string, limit, offset int) ([]db.Tender, error) {
+func (m *MockStorage) GetTenderVersions(ctx context.Context, tenderID string, page db.Page) ([]db.Version, int, error) {
+	return []db.Version{{ID: 2, Version: 2, Status: "Published"}, {ID: 1, Version: 1, Status: "Created"}}, 2, nil
+}
+func (m *MockStorage) GetTenders(ctx context.Context, serviceTypes []string, page db.Page) ([]db.Tender, int, error) {
 	if m.GetTendersFunc != nil {
-		return m.GetTendersFunc(ctx, serviceTypes, limit, offset)
+		return m.GetTendersFunc(ctx, serviceTypes, page)
 	}
-	return []db.Tender{{ID: 1, Name: "Sample Tender"}}, nil
+	return []db.Tender{{ID: testID(1), Name: "Sample Tender", Description: "Description", ServiceType: "Delivery", Status: "Published", OrganizationID: testID(1), Version: 1}}, 1, nil
 }
 
-func (m *MockStorage) GetUserTenders(ctx context.Context, username string, limit, offset int) ([]db.Tender, error) {
+func (m *MockStorage) GetUserTenders(ctx context.Context, username string, page db.Page) ([]db.Tender, int, error) {
 	return []db.Tender{
-		{ID: 1, Name: "User Tender"},
-	}, nil
+		{ID: testID(1), Name: "User Tender", Description: "Description", ServiceType: "Delivery", Status: "Created", OrganizationID: testID(1), Version: 1},
+	}, 1, nil
 }
 
 func (m *MockStorage) CreateBid(ctx context.Context, bid *db.Bid) error { return nil }
-func (m *MockStorage) GetBid(ctx context.Context, bidID int) (*db.Bid, error) {
+func (m *MockStorage) GetBid(ctx context.Context, bidID string) (*db.Bid, error) {
 	if m.GetBidFunc != nil {
 		return m.GetBidFunc(ctx, bidID)
 	}
@@ -72,73 +147,166 @@ func (m *MockStorage) GetBid(ctx context.Context, bidID int) (*db.Bid, error) {
 		Name:            "Test Bid",
 		Description:     "Bid Description",
 		Status:          "Created",
-		TenderID:        1,
-		OrganizationID:  1,
+		TenderID:        testID(1),
+		OrganizationID:  testID(1),
 		CreatorUsername: "user1",
 		Version:         1,
 	}, nil
 }
+func (m *MockStorage) LockBid(ctx context.Context, bidID string) (*db.Bid, error) {
+	return m.GetBid(ctx, bidID)
+}
 func (m *MockStorage) UpdateBid(ctx context.Context, bid *db.Bid) error { return nil }
-func (m *MockStorage) GetUserBids(ctx context.Context, username string, limit, offset int) ([]db.Bid, error) {
+func (m *MockStorage) GetUserBids(ctx context.Context, username string, page db.Page) ([]db.Bid, int, error) {
 	if m.GetUserBidsFunc != nil {
-		return m.GetUserBidsFunc(ctx, username, limit, offset)
+		return m.GetUserBidsFunc(ctx, username, page)
 	}
 	return []db.Bid{
 		{
-			ID:              1,
+			ID:              testID(1),
 			Name:            "User Bid",
 			Description:     "Description for user bid",
 			Status:          "Created",
-			TenderID:        1,
+			TenderID:        testID(1),
 			CreatorUsername: username,
 			Version:         1,
 		},
-	}, nil
+	}, 1, nil
 }
-func (m *MockStorage) GetBidsForTender(ctx context.Context, tenderID int, username string, limit, offset int) ([]db.Bid, error) {
+func (m *MockStorage) GetBidsForTender(ctx context.Context, tenderID string, username string, page db.Page) ([]db.Bid, int, error) {
 	if m.GetBidsForTenderFunc != nil {
-		return m.GetBidsForTenderFunc(ctx, tenderID, username, limit, offset)
+		return m.GetBidsForTenderFunc(ctx, tenderID, username, page)
 	}
 	return []db.Bid{
 		{
-			ID:          2,
+			ID:          testID(2),
 			Name:        "Tender Bid",
 			Description: "Description for tender bid",
 			Status:      "Published",
 			TenderID:    tenderID,
 			Version:     1,
 		},
-	}, nil
+	}, 1, nil
 }
-func (m *MockStorage) GetBidVersion(ctx context.Context, bidID, version int) (*db.Bid, error) {
+func (m *MockStorage) GetBidVersion(ctx context.Context, bidID string, version int) (*db.Bid, error) {
 	return &db.Bid{
 		ID:              bidID,
 		Name:            "Bid Version Name",
-		Description:     "Bid Version Description",
+		Description:     fmt.Sprintf("Bid Version Description v%d", version),
 		Status:          "Created",
-		TenderID:        1,
-		OrganizationID:  1,
+		TenderID:        testID(1),
+		OrganizationID:  testID(1),
 		CreatorUsername: "user1",
 		Version:         version,
 	}, nil
 }
 func (m *MockStorage) SaveBidVersion(ctx context.Context, bid *db.Bid) error { return nil }
+func (m *MockStorage) GetBidVersions(ctx context.Context, bidID string, page db.Page) ([]db.Version, int, error) {
+	return []db.Version{{ID: 3, Version: 1, Status: "Created"}}, 1, nil
+}
+
+func (m *MockStorage) AddBidDecision(ctx context.Context, bidID string, employeeID int, decision, comment string) error {
+	return nil
+}
+func (m *MockStorage) RevokeBidDecision(ctx context.Context, bidID string, employeeID int) error {
+	return nil
+}
+func (m *MockStorage) GetBidDecisionHistory(ctx context.Context, bidID string) ([]db.BidDecisionHistory, error) {
+	return []db.BidDecisionHistory{{BidID: bidID, UserID: 2, Decision: "Rejected", Action: db.DecisionOverwritten}}, nil
+}
+func (m *MockStorage) GetBidDecisions(ctx context.Context, bidID string) ([]db.BidDecision, error) {
+	if m.employee == nil {
+		return nil, nil
+	}
+	return []db.BidDecision{{BidID: bidID, UserID: m.employee.ID, Username: m.employee.Username, Decision: "Approved"}}, nil
+}
 
-func (m *MockStorage) AddBidDecision(ctx context.Context, bidID, employeeID int, decision string) error {
+func (m *MockStorage) CreateOrganization(ctx context.Context, organization *db.Organization) error {
+	organization.ID = testID(10)
 	return nil
 }
-func (m *MockStorage) GetBidDecisionsCount(ctx context.Context, bidID int) (int, int, error) {
-	return 1, 0, nil
+func (m *MockStorage) GetOrganization(ctx context.Context, id string) (*db.Organization, error) {
+	return &db.Organization{ID: id, Name: "Organization", Type: db.OrganizationLLC}, nil
+}
+func (m *MockStorage) LockOrganization(ctx context.Context, id string) (*db.Organization, error) {
+	return m.GetOrganization(ctx, id)
+}
+func (m *MockStorage) ListOrganizations(ctx context.Context, search string, page db.Page) ([]db.Organization, int, error) {
+	return []db.Organization{{ID: testID(1), Name: "Organization", Type: db.OrganizationLLC}}, 1, nil
+}
+func (m *MockStorage) UpdateOrganization(ctx context.Context, organization *db.Organization) error {
+	return nil
 }
-func (m *MockStorage) GetResponsibleCount(ctx context.Context, organizationID int) (int, error) {
-	return 1, nil
+func (m *MockStorage) DeleteOrganization(ctx context.Context, id string) error { return nil }
+func (m *MockStorage) AddResponsible(ctx context.Context, organizationID string, userID int, role string) error {
+	return nil
+}
+func (m *MockStorage) RemoveResponsible(ctx context.Context, organizationID string, userID int) error {
+	return nil
+}
+
+func (m *MockStorage) GetResponsibles(ctx context.Context, organizationID string) ([]db.Responsible, error) {
+	if m.employee == nil || !m.responsible {
+		return nil, nil
+	}
+	return []db.Responsible{{UserID: m.employee.ID, OrganizationID: organizationID, Role: "member"}}, nil
+}
+
+func (m *MockStorage) GetApprovalPolicy(ctx context.Context, organizationID string, tenderID *string) (*db.ApprovalPolicy, error) {
+	if m.policy == nil {
+		return nil, db.ErrNotFound
+	}
+	return m.policy, nil
+}
+func (m *MockStorage) SaveApprovalPolicy(ctx context.Context, policy *db.ApprovalPolicy) error {
+	m.policy = policy
+	return nil
+}
+func (m *MockStorage) DeleteApprovalPolicy(ctx context.Context, organizationID string, tenderID *string) error {
+	m.policy = nil
+	return nil
 }
 
-func (m *MockStorage) GetBidReviewsByAuthorForTender(ctx context.Context, authorUsername string, tenderID int) ([]db.BidReview, error) {
+func (m *MockStorage) GetBidReviewsByAuthorForTender(ctx context.Context, authorUsername string, tenderID string) ([]db.BidReview, error) {
 	return []db.BidReview{{ID: 1, Description: "Good"}}, nil
 }
 func (m *MockStorage) CreateBidReview(ctx context.Context, review *db.BidReview) error { return nil }
 
+func (m *MockStorage) ArchiveTender(ctx context.Context, tender *db.Tender) error {
+	if tender.DeletedAt != nil {
+		return db.ErrNotFound
+	}
+	deletedAt := time.Now()
+	tender.DeletedAt, tender.DeletedBy = &deletedAt, db.AuthorFrom(ctx)
+	return nil
+}
+func (m *MockStorage) RestoreTender(ctx context.Context, tender *db.Tender) error {
+	if tender.DeletedAt == nil {
+		return db.ErrNotFound
+	}
+	tender.DeletedAt, tender.DeletedBy = nil, nil
+	return nil
+}
+func (m *MockStorage) ArchiveBid(ctx context.Context, bid *db.Bid) error {
+	if bid.DeletedAt != nil {
+		return db.ErrNotFound
+	}
+	deletedAt := time.Now()
+	bid.DeletedAt, bid.DeletedBy = &deletedAt, db.AuthorFrom(ctx)
+	return nil
+}
+func (m *MockStorage) RestoreBid(ctx context.Context, bid *db.Bid) error {
+	if bid.DeletedAt == nil {
+		return db.ErrNotFound
+	}
+	bid.DeletedAt, bid.DeletedBy = nil, nil
+	return nil
+}
+func (m *MockStorage) PurgeArchived(ctx context.Context, retention time.Duration) (db.PurgeResult, error) {
+	m.purgedRetention = retention
+	return db.PurgeResult{Tenders: 1, Bids: 2, Reviews: 3}, nil
+}
+
 func TestGetTendersHandler(t *testing.T) {
 	mockStore := &MockStorage{}
 	handler := handlers.NewHandler(mockStore)
@@ -157,6 +325,65 @@ func TestGetTendersHandler(t *testing.T) {
 	require.Contains(t, string(body), "Sample Tender")
 }
 
+func TestGetTendersHandlerPagination(t *testing.T) {
+	var got db.Page
+	mockStore := &MockStorage{GetTendersFunc: func(ctx context.Context, serviceTypes []string, page db.Page) ([]db.Tender, int, error) {
+		got = page
+		// Хранилище возвращает на одну строку больше лимита: есть следующая страница
+		return []db.Tender{
+			{ID: testID(3), Name: "B", Description: "d", ServiceType: "Delivery", Status: "Published", OrganizationID: testID(1), Version: 1},
+			{ID: testID(1), Name: "C", Description: "d", ServiceType: "Delivery", Status: "Published", OrganizationID: testID(1), Version: 1},
+			{ID: testID(2), Name: "D", Description: "d", ServiceType: "Delivery", Status: "Published", OrganizationID: testID(1), Version: 1},
+		}, 7, nil
+	}}
+	router := handlers.NewRouter(handlers.NewHandler(mockStore))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := get("/api/tenders?service_type=Delivery&limit=2&offset=2")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, db.Page{Limit: 3, Offset: 2}, got)
+	require.Equal(t, "7", w.Header().Get("X-Total-Count"))
+	var tenders []db.Tender
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tenders))
+	require.Len(t, tenders, 2)
+
+	links := map[string]string{}
+	for _, link := range strings.Split(w.Header().Get("Link"), ", ") {
+		target, rel, ok := strings.Cut(link, "; ")
+		require.True(t, ok, link)
+		links[rel] = strings.Trim(target, "<>")
+	}
+	require.Equal(t, "/api/tenders?limit=2&service_type=Delivery", links[`rel="first"`])
+	require.Contains(t, links, `rel="prev"`)
+	require.Contains(t, links, `rel="next"`)
+
+	// Ссылка next продолжает выборку после последнего тендера страницы
+	require.Equal(t, http.StatusOK, get(links[`rel="next"`]).Code)
+	require.Equal(t, &db.Cursor{Name: "C", ID: testID(1)}, got.Cursor)
+	require.Zero(t, got.Offset)
+
+	require.Equal(t, http.StatusOK, get(links[`rel="prev"`]).Code)
+	require.Equal(t, &db.Cursor{Name: "B", ID: testID(3), Before: true}, got.Cursor)
+
+	for _, target := range []string{
+		"/api/tenders?limit=51",
+		"/api/tenders?limit=-1",
+		"/api/tenders?limit=five",
+		"/api/tenders?offset=-5",
+		"/api/tenders?cursor=not-a-cursor",
+		"/api/tenders?offset=0&" + strings.TrimPrefix(links[`rel="next"`], "/api/tenders?"),
+	} {
+		w := get(target)
+		require.Equal(t, http.StatusBadRequest, w.Code, target)
+		require.Contains(t, w.Body.String(), `"code":"invalid_request"`)
+	}
+}
+
 func TestCreateTenderHandler(t *testing.T) {
 	mockStore := &MockStorage{
 		employee:    &db.Employee{ID: 1},
@@ -168,11 +395,12 @@ func TestCreateTenderHandler(t *testing.T) {
         "name": "Test Tender",
         "description": "Desc",
         "serviceType": "Construction",
-        "organizationId": 1,
+        "organizationId": "00000000-0000-0000-0000-000000000001",
         "creatorUsername": "user1"
     }`
-	req := httptest.NewRequest(http.MethodPost, "/api/tenders/new?username=user1", strings.NewReader(reqBody))
+	req := httptest.NewRequest(http.MethodPost, "/api/tenders/new", strings.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
+	req = testutils.WithEmployee(req, mockStore.employee)
 	w := httptest.NewRecorder()
 
 	handler.CreateTenderHandler(w, req)
@@ -187,14 +415,56 @@ func TestCreateTenderHandler(t *testing.T) {
 	require.Contains(t, string(body), "Test Tender")
 }
 
+func TestCreateTenderValidation(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	// 100 кириллических символов - допустимое название
+	reqBody := fmt.Sprintf(`{"name": %q, "description": "Описание", "serviceType": "Construction", "organizationId": "00000000-0000-0000-0000-000000000001"}`,
+		strings.Repeat("Я", 100))
+	req := httptest.NewRequest(http.MethodPost, "/api/tenders/new", strings.NewReader(reqBody))
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.CreateTenderHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Все неверные поля перечислены в одном ответе
+	reqBody = `{"name": "", "description": "Desc", "serviceType": "Repair", "status": "Closed", "organizationId": "00000000-0000-0000-0000-000000000001"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/tenders/new", strings.NewReader(reqBody))
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.CreateTenderHandler(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp struct {
+		apierr.Response
+		Errors []struct {
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, apierr.ValidationFailed, resp.Code)
+	var fields []string
+	for _, e := range resp.Errors {
+		fields = append(fields, e.Field)
+	}
+	require.Equal(t, []string{"name", "serviceType", "status"}, fields)
+}
+
 func TestChangeTenderStatusHandler(t *testing.T) {
-	mockStore := &MockStorage{}
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1, Username: "user1"},
+		responsible: true,
+	}
 	handler := handlers.NewHandler(mockStore)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/tenders/123/status?username=user1", strings.NewReader(`{"status":"closed"}`))
-	req.Header.Set("Content-Type", "application/json")
-	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": "123"})
+	req := httptest.NewRequest(http.MethodPut, "/api/tenders/"+testID(123)+"/status?status=Closed", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
 
+	req = testutils.WithEmployee(req, mockStore.employee)
 	w := httptest.NewRecorder()
 
 	handler.ChangeTenderStatusHandler(w, req)
@@ -206,18 +476,118 @@ func TestChangeTenderStatusHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "status")
+	require.Contains(t, string(body), `"status":"Closed"`)
+
+	// Администратор площадки не ответственный за организацию, но закрывает
+	// тендер наравне с ответственным
+	mockStore.responsible = false
+	mockStore.platformRole = "admin"
+	req = httptest.NewRequest(http.MethodPut, "/api/tenders/"+testID(123)+"/status?status=Closed", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.ChangeTenderStatusHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// Без пользователя тендер не загружается, и ответ не выдает, есть ли он
+	mockStore.GetTenderFunc = func(ctx context.Context, tenderID string) (*db.Tender, error) {
+		t.Fatal("tender loaded before authentication")
+		return nil, nil
+	}
+	req = httptest.NewRequest(http.MethodPut, "/api/tenders/"+testID(123)+"/status?status=Closed", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
+	w = httptest.NewRecorder()
+	handler.ChangeTenderStatusHandler(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
-func TestUpdateTenderStatusHandler(t *testing.T) {
-	mockStore := &MockStorage{}
+func TestGetTenderStatusVisibility(t *testing.T) {
+	status := "Created"
+	mockStore := &MockStorage{
+		GetTenderFunc: func(ctx context.Context, tenderID string) (*db.Tender, error) {
+			return &db.Tender{ID: tenderID, Status: status, OrganizationID: testID(1)}, nil
+		},
+	}
 	handler := handlers.NewHandler(mockStore)
 
-	reqBody := `{"status":"closed"}`
-	req := httptest.NewRequest(http.MethodPut, "/api/tenders/123/status?username=user1", strings.NewReader(reqBody))
-	req.Header.Set("Content-Type", "application/json")
-	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": "123"})
+	get := func(employee *db.Employee) *httptest.ResponseRecorder {
+		mockStore.employee = employee
+		req := httptest.NewRequest(http.MethodGet, "/api/tenders/"+testID(1)+"/status", nil)
+		req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
+		req = testutils.WithEmployee(req, employee)
+		w := httptest.NewRecorder()
+		handler.GetTenderStatusHandler(w, req)
+		return w
+	}
+	outsider := &db.Employee{ID: 2, Username: "user2"}
+
+	// Неопубликованный тендер скрыт от посторонних
+	w := get(outsider)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, apierr.NotFound, decodeError(t, w).Code)
+
+	// Ответственный организации видит статус
+	mockStore.responsible = true
+	w = get(&db.Employee{ID: 1, Username: "user1"})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `"Created"`, w.Body.String())
+
+	// Опубликованный тендер виден всем
+	mockStore.responsible = false
+	status = "Published"
+	w = get(outsider)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `"Published"`, w.Body.String())
+}
+
+func TestGetBidStatusVisibility(t *testing.T) {
+	status := "Created"
+	mockStore := &MockStorage{
+		GetBidFunc: func(ctx context.Context, bidID string) (*db.Bid, error) {
+			return &db.Bid{ID: bidID, Status: status, TenderID: testID(1), OrganizationID: testID(1), CreatorUsername: "user1"}, nil
+		},
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	get := func(employee *db.Employee) *httptest.ResponseRecorder {
+		mockStore.employee = employee
+		req := httptest.NewRequest(http.MethodGet, "/api/bids/"+testID(1)+"/status", nil)
+		req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+		req = testutils.WithEmployee(req, employee)
+		w := httptest.NewRecorder()
+		handler.GetBidStatusHandler(w, req)
+		return w
+	}
+	author := &db.Employee{ID: 1, Username: "user1"}
+	outsider := &db.Employee{ID: 2, Username: "user2"}
+
+	// Автор видит свое неопубликованное предложение, посторонний - нет
+	w := get(author)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `"Created"`, w.Body.String())
+	require.Equal(t, http.StatusNotFound, get(outsider).Code)
+
+	// Опубликованное предложение постороннему запрещено, ответственному - видно
+	status = "Published"
+	require.Equal(t, http.StatusForbidden, get(outsider).Code)
+	mockStore.responsible = true
+	w = get(outsider)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `"Published"`, w.Body.String())
+}
+
+func TestChangeTenderStatusConflict(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1, Username: "user1"},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	// Опубликованный тендер нельзя вернуть в Created
+	req := httptest.NewRequest(http.MethodPut, "/api/tenders/"+testID(123)+"/status?status=Created", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
 
+	req = testutils.WithEmployee(req, mockStore.employee)
 	w := httptest.NewRecorder()
 	handler.ChangeTenderStatusHandler(w, req)
 
@@ -227,17 +597,18 @@ func TestUpdateTenderStatusHandler(t *testing.T) {
 	body, err := io.ReadAll(res.Body)
 	require.NoError(t, err)
 
-	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "status")
+	require.Equal(t, http.StatusConflict, res.StatusCode)
+	require.Contains(t, string(body), `"allowedStatuses":["Closed"]`)
 }
 
 func TestRollbackTenderHandler(t *testing.T) {
-	mockStore := &MockStorage{}
+	mockStore := &MockStorage{responsible: true}
 	handler := handlers.NewHandler(mockStore)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/tenders/123/rollback/1?username=user1", nil)
-	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": "123", "version": "1"})
+	req := httptest.NewRequest(http.MethodPut, "/api/tenders/"+testID(123)+"/rollback/1", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123), "version": "1"})
 
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
 	w := httptest.NewRecorder()
 	handler.RollbackTenderHandler(w, req)
 
@@ -248,7 +619,30 @@ func TestRollbackTenderHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "rollback")
+
+	var tender db.Tender
+	require.NoError(t, json.Unmarshal(body, &tender))
+	require.Equal(t, testID(123), tender.ID)
+	require.Equal(t, "v1", tender.Description)
+}
+
+func TestRollbackClosedTender(t *testing.T) {
+	mockStore := &MockStorage{
+		responsible: true,
+		GetTenderFunc: func(ctx context.Context, tenderID string) (*db.Tender, error) {
+			return &db.Tender{ID: tenderID, Status: "Closed", OrganizationID: testID(1), Version: 3}, nil
+		},
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/tenders/"+testID(123)+"/rollback/1", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123), "version": "1"})
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
+	w := httptest.NewRecorder()
+	handler.RollbackTenderHandler(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	require.Equal(t, apierr.InvalidState, decodeError(t, w).Code)
 }
 
 func TestEditTenderHandler(t *testing.T) {
@@ -259,10 +653,12 @@ func TestEditTenderHandler(t *testing.T) {
 	handler := handlers.NewHandler(mockStore)
 
 	reqBody := `{"name":"Updated Tender"}`
-	req := httptest.NewRequest(http.MethodPatch, "/api/tenders/123/edit?username=user1", strings.NewReader(reqBody))
+	req := httptest.NewRequest(http.MethodPatch, "/api/tenders/"+testID(123)+"/edit", strings.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
-	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": "123"})
+	req.Header.Set("If-Match", `"2"`)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
 
+	req = testutils.WithEmployee(req, mockStore.employee)
 	w := httptest.NewRecorder()
 	handler.EditTenderHandler(w, req)
 
@@ -276,17 +672,95 @@ func TestEditTenderHandler(t *testing.T) {
 	require.Contains(t, string(body), "Updated Tender")
 }
 
+func TestEditHandlersRequireVersion(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1, Username: "user1"},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	// Правка без If-Match и version могла бы затереть чужие изменения
+	req := httptest.NewRequest(http.MethodPatch, "/api/tenders/"+testID(123)+"/edit", strings.NewReader(`{"name":"Updated Tender"}`))
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.EditTenderHandler(w, req)
+	require.Equal(t, http.StatusPreconditionRequired, w.Code)
+	require.Equal(t, apierr.PreconditionRequired, decodeError(t, w).Code)
+
+	req = httptest.NewRequest(http.MethodPatch, "/api/bids/"+testID(1)+"/edit", strings.NewReader(`{"name":"Updated Bid"}`))
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.EditBidHandler(w, req)
+	require.Equal(t, http.StatusPreconditionRequired, w.Code)
+
+	// Устаревшая версия предложения - конфликт
+	req = httptest.NewRequest(http.MethodPatch, "/api/bids/"+testID(1)+"/edit", strings.NewReader(`{"name":"Updated Bid","version":5}`))
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.EditBidHandler(w, req)
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Equal(t, apierr.VersionConflict, decodeError(t, w).Code)
+}
+
+func TestEditTenderHandlerVersionConflict(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/tenders/"+testID(123)+"/edit", strings.NewReader(`{"name":"Updated Tender"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"1"`)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.EditTenderHandler(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusConflict, res.StatusCode)
+}
+
+func TestEditTenderHandlerValidation(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/tenders/"+testID(123)+"/edit", strings.NewReader(`{"serviceType":"Cleaning","version":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.EditTenderHandler(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
 func TestCreateBidHandler(t *testing.T) {
-	mockStore := &MockStorage{}
+	mockStore := &MockStorage{responsible: true}
 	handler := handlers.NewHandler(mockStore)
 
 	reqBody := `{
-        "tenderId": 1,
-        "bidderUsername": "user1",
-        "name": "Bid Name"
+        "tenderId": "00000000-0000-0000-0000-000000000001",
+        "organizationId": "00000000-0000-0000-0000-000000000001",
+        "name": "Bid Name",
+        "description": "Bid Description"
     }`
-	req := httptest.NewRequest(http.MethodPost, "/api/bids/new?username=user1", strings.NewReader(reqBody))
+	req := httptest.NewRequest(http.MethodPost, "/api/bids/new", strings.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
 	w := httptest.NewRecorder()
 
 	handler.CreateBidHandler(w, req)
@@ -296,14 +770,19 @@ func TestCreateBidHandler(t *testing.T) {
 
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "bid")
+
+	var bid db.Bid
+	require.NoError(t, json.Unmarshal(body, &bid))
+	require.Equal(t, "Bid Name", bid.Name)
+	require.Equal(t, "user1", bid.CreatorUsername)
 }
 
 func TestGetUserBidsHandler(t *testing.T) {
 	mockStore := &MockStorage{}
 	handler := handlers.NewHandler(mockStore)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/bids/my?username=user1", nil)
+	req := httptest.NewRequest(http.MethodGet, "/api/bids/my", nil)
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
 	w := httptest.NewRecorder()
 
 	handler.GetUserBidsHandler(w, req)
@@ -313,16 +792,21 @@ func TestGetUserBidsHandler(t *testing.T) {
 
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "bids")
+
+	var bids []db.Bid
+	require.NoError(t, json.Unmarshal(body, &bids))
+	require.Len(t, bids, 1)
+	require.Equal(t, "User Bid", bids[0].Name)
 }
 
 func TestGetBidsForTenderHandler(t *testing.T) {
 	mockStore := &MockStorage{}
 	handler := handlers.NewHandler(mockStore)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/bids/1/list?username=user1", nil)
-	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": "1"})
+	req := httptest.NewRequest(http.MethodGet, "/api/bids/"+testID(1)+"/list", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
 
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
 	w := httptest.NewRecorder()
 
 	handler.GetBidsForTenderHandler(w, req)
@@ -332,18 +816,23 @@ func TestGetBidsForTenderHandler(t *testing.T) {
 
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "bids")
+
+	var bids []db.Bid
+	require.NoError(t, json.Unmarshal(body, &bids))
+	require.Len(t, bids, 1)
+	require.Equal(t, "Tender Bid", bids[0].Name)
 }
 
 func TestEditBidHandler(t *testing.T) {
 	mockStore := &MockStorage{}
 	handler := handlers.NewHandler(mockStore)
 
-	reqBody := `{"name": "Updated Bid"}`
-	req := httptest.NewRequest(http.MethodPatch, "/api/bids/1/edit?username=user1", strings.NewReader(reqBody))
+	reqBody := `{"name": "Updated Bid", "version": 1}`
+	req := httptest.NewRequest(http.MethodPatch, "/api/bids/"+testID(1)+"/edit", strings.NewReader(reqBody))
 	req.Header.Set("Content-Type", "application/json")
-	req = testutils.WithChiURLParams(req, map[string]string{"bidId": "1"})
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
 
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
 	w := httptest.NewRecorder()
 
 	handler.EditBidHandler(w, req)
@@ -354,17 +843,28 @@ func TestEditBidHandler(t *testing.T) {
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
 	require.Contains(t, string(body), "Updated Bid")
+
+	// Статус через правку не меняется
+	req = httptest.NewRequest(http.MethodPatch, "/api/bids/"+testID(1)+"/edit", strings.NewReader(`{"status":"Approved","version":1}`))
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
+	w = httptest.NewRecorder()
+	handler.EditBidHandler(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"field":"status"`)
+	require.Equal(t, apierr.ValidationFailed, decodeError(t, w).Code)
 }
 
 func TestUpdateBidStatusHandler(t *testing.T) {
-	mockStore := &MockStorage{}
+	mockStore := &MockStorage{
+		employee: &db.Employee{ID: 1, Username: "user1"},
+	}
 	handler := handlers.NewHandler(mockStore)
 
-	reqBody := `{"status":"accepted"}`
-	req := httptest.NewRequest(http.MethodPut, "/api/bids/1/status?username=user1", strings.NewReader(reqBody))
-	req.Header.Set("Content-Type", "application/json")
-	req = testutils.WithChiURLParams(req, map[string]string{"bidId": "1"})
+	req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(1)+"/status?status=Published", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
 
+	req = testutils.WithEmployee(req, mockStore.employee)
 	w := httptest.NewRecorder()
 
 	handler.UpdateBidStatusHandler(w, req)
@@ -374,16 +874,41 @@ func TestUpdateBidStatusHandler(t *testing.T) {
 
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "status")
+	require.Contains(t, string(body), `"status":"Published"`)
+}
+
+func TestUpdateBidStatusHandlerRejectsApproval(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 2, Username: "boss"},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	// Approved выставляется только голосованием
+	req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(1)+"/status?status=Approved", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+
+	handler.UpdateBidStatusHandler(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	require.Equal(t, http.StatusConflict, res.StatusCode)
+	require.Contains(t, string(body), `"allowedStatuses":["Published","Canceled"]`)
 }
 
 func TestRollbackBidHandler(t *testing.T) {
-	mockStore := &MockStorage{}
+	mockStore := &MockStorage{responsible: true}
 	handler := handlers.NewHandler(mockStore)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/bids/1/rollback/1?username=user1", nil)
-	req = testutils.WithChiURLParams(req, map[string]string{"bidId": "1", "version": "1"})
+	req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(1)+"/rollback/1", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1), "version": "1"})
 
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
 	w := httptest.NewRecorder()
 
 	handler.RollbackBidHandler(w, req)
@@ -393,18 +918,46 @@ func TestRollbackBidHandler(t *testing.T) {
 
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "rollback")
+
+	var bid db.Bid
+	require.NoError(t, json.Unmarshal(body, &bid))
+	require.Equal(t, testID(1), bid.ID)
+	require.Equal(t, "Bid Version Description v1", bid.Description)
+}
+
+func TestRollbackDecidedBid(t *testing.T) {
+	mockStore := &MockStorage{
+		responsible: true,
+		GetBidFunc: func(ctx context.Context, bidID string) (*db.Bid, error) {
+			return &db.Bid{ID: bidID, Status: "Approved", TenderID: testID(1), OrganizationID: testID(1), CreatorUsername: "user1", Version: 2}, nil
+		},
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(1)+"/rollback/1", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1), "version": "1"})
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
+	w := httptest.NewRecorder()
+	handler.RollbackBidHandler(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+	require.Equal(t, apierr.InvalidState, decodeError(t, w).Code)
 }
 
 func TestSubmitBidDecisionHandler(t *testing.T) {
-	mockStore := &MockStorage{}
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 2, Username: "boss"},
+		responsible: true,
+		GetBidFunc: func(ctx context.Context, bidID string) (*db.Bid, error) {
+			return &db.Bid{ID: bidID, Status: "Published", TenderID: testID(1), OrganizationID: testID(1)}, nil
+		},
+	}
 	handler := handlers.NewHandler(mockStore)
 
-	reqBody := `{"decision":"accept"}`
-	req := httptest.NewRequest(http.MethodPut, "/api/bids/1/submit_decision?username=user1", strings.NewReader(reqBody))
-	req.Header.Set("Content-Type", "application/json")
-	req = testutils.WithChiURLParams(req, map[string]string{"bidId": "1"})
+	req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(1)+"/submit_decision?decision=Approved", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
 
+	req = testutils.WithEmployee(req, mockStore.employee)
 	w := httptest.NewRecorder()
 
 	handler.SubmitBidDecisionHandler(w, req)
@@ -414,16 +967,17 @@ func TestSubmitBidDecisionHandler(t *testing.T) {
 
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "decision")
+	require.Contains(t, string(body), `"status":"Approved"`)
 }
 
 func TestGetBidReviewsHandler(t *testing.T) {
-	mockStore := &MockStorage{}
+	mockStore := &MockStorage{responsible: true}
 	handler := handlers.NewHandler(mockStore)
 
-	req := httptest.NewRequest(http.MethodGet, "/api/bids/1/reviews?username=user1", nil)
-	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": "1"})
+	req := httptest.NewRequest(http.MethodGet, "/api/bids/"+testID(1)+"/reviews?authorUsername=user2", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
 
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
 	w := httptest.NewRecorder()
 
 	handler.GetBidReviewsHandler(w, req)
@@ -433,18 +987,24 @@ func TestGetBidReviewsHandler(t *testing.T) {
 
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "reviews")
+
+	var reviews []db.BidReview
+	require.NoError(t, json.Unmarshal(body, &reviews))
+	require.Len(t, reviews, 1)
+	require.Equal(t, "Good", reviews[0].Description)
 }
 
 func TestCreateBidFeedbackHandler(t *testing.T) {
 	mockStore := &MockStorage{
-		employee: &db.Employee{ID: 1}, // Эмуляция успешного поиска пользователя
+		employee:    &db.Employee{ID: 1}, // Эмуляция успешного поиска пользователя
+		responsible: true,
 	}
 	handler := handlers.NewHandler(mockStore)
 
-	req := httptest.NewRequest(http.MethodPut, "/api/bids/1/feedback?username=user1&bidFeedback=good", nil)
-	req = testutils.WithChiURLParams(req, map[string]string{"bidId": "1"})
+	req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(1)+"/feedback?bidFeedback=good", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
 
+	req = testutils.WithEmployee(req, mockStore.employee)
 	w := httptest.NewRecorder()
 
 	handler.CreateBidFeedbackHandler(w, req)
@@ -454,5 +1014,655 @@ func TestCreateBidFeedbackHandler(t *testing.T) {
 
 	body, _ := io.ReadAll(res.Body)
 	require.Equal(t, http.StatusOK, res.StatusCode)
-	require.Contains(t, string(body), "feedback")
+
+	var review db.BidReview
+	require.NoError(t, json.Unmarshal(body, &review))
+	require.Equal(t, testID(1), review.BidID)
+	require.Equal(t, "good", review.Description)
+}
+
+func TestPutApprovalPolicyHandler(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	reqBody := `{"kind":"weighted","required":4,"roleWeights":{"head":3}}`
+	req := httptest.NewRequest(http.MethodPut, "/api/organizations/"+testID(1)+"/approval_policy", strings.NewReader(reqBody))
+	req = testutils.WithChiURLParams(req, map[string]string{"organizationId": testID(1)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.PutApprovalPolicyHandler(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Contains(t, string(body), `"rejectThreshold":1`)
+	require.NotNil(t, mockStore.policy)
+	require.Equal(t, 3, mockStore.policy.RoleWeights["head"])
+}
+
+func TestPutApprovalPolicyHandlerInvalid(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/organizations/"+testID(1)+"/approval_policy", strings.NewReader(`{"kind":"fixed"}`))
+	req = testutils.WithChiURLParams(req, map[string]string{"organizationId": testID(1)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.PutApprovalPolicyHandler(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSubmitBidDecisionHandlerUsesPolicy(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 2, Username: "boss"},
+		responsible: true,
+		// Голос роли member ничего не весит, одобрение недостижимо
+		policy: &db.ApprovalPolicy{Kind: "weighted", Required: 1, RejectThreshold: 1, RoleWeights: db.RoleWeights{"member": 0}},
+		GetBidFunc: func(ctx context.Context, bidID string) (*db.Bid, error) {
+			return &db.Bid{ID: bidID, Status: "Published", TenderID: testID(1), OrganizationID: testID(1)}, nil
+		},
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(1)+"/submit_decision?decision=Approved", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.SubmitBidDecisionHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"status":"Rejected"`)
+}
+
+func TestGetBidDecisionsHandler(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 2, Username: "boss"},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/bids/"+testID(1)+"/decisions", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.GetBidDecisionsHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"username":"boss"`)
+}
+
+func TestRevokeBidDecisionHandlerDecidedBid(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 2, Username: "boss"},
+		responsible: true,
+		GetBidFunc: func(ctx context.Context, bidID string) (*db.Bid, error) {
+			return &db.Bid{ID: bidID, Status: "Approved", TenderID: testID(1), OrganizationID: testID(1)}, nil
+		},
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/bids/"+testID(1)+"/decisions", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.RevokeBidDecisionHandler(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestGetTenderVersionsHandler(t *testing.T) {
+	mockStore := &MockStorage{employee: &db.Employee{ID: 1, Username: "test_user"}, responsible: true}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders/"+testID(1)+"/versions", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
+
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
+	w := httptest.NewRecorder()
+	handler.GetTenderVersionsHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var versions []db.Version
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &versions))
+	require.Len(t, versions, 2)
+	require.Equal(t, 2, versions[0].Version)
+}
+
+func TestGetTenderVersionsHandlerPagination(t *testing.T) {
+	mockStore := &MockStorage{employee: &db.Employee{ID: 1, Username: "test_user"}, responsible: true}
+	handler := handlers.NewHandler(mockStore)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/tenders/"+testID(1)+"/versions?"+query, nil)
+		req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
+		req = testutils.WithEmployee(req, mockStore.employee)
+		w := httptest.NewRecorder()
+		handler.GetTenderVersionsHandler(w, req)
+		return w
+	}
+
+	w := get("limit=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "2", w.Header().Get("X-Total-Count"))
+	require.Contains(t, w.Header().Get("Link"), `rel="next"`)
+	var versions []db.Version
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &versions))
+	require.Len(t, versions, 1)
+	require.Equal(t, 2, versions[0].Version)
+
+	// Курсор списка тендеров с UUID не подходит истории версий
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(`{"n":"Tender","i":"` + testID(1) + `"}`))
+	w = get("cursor=" + cursor)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"invalid_request"`)
+}
+
+func TestGetTenderVersionsDiffHandler(t *testing.T) {
+	mockStore := &MockStorage{employee: &db.Employee{ID: 1, Username: "test_user"}, responsible: true}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders/"+testID(1)+"/versions/diff?from=1&to=2", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
+
+	req = testutils.WithEmployee(req, &db.Employee{ID: 1, Username: "user1"})
+	w := httptest.NewRecorder()
+	handler.GetTenderVersionsDiffHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var diff models.VersionDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	require.Equal(t, []models.FieldChange{{Field: "description", From: "v1", To: "v2"}}, diff.Changes)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/tenders/"+testID(1)+"/versions/diff?from=1&to=5", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.GetTenderVersionsDiffHandler(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// Сотрудник организации видит историю опубликованного тендера, но не черновика
+func TestTenderHistoryHidesDrafts(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	org := &db.Organization{Name: "Org", Type: db.OrganizationLLC}
+	require.NoError(t, store.CreateOrganization(ctx, org))
+	member := &db.Employee{Username: "member"}
+	require.NoError(t, store.CreateEmployee(ctx, member))
+	require.NoError(t, store.AddMember(ctx, org.ID, member.ID))
+	tender := &db.Tender{Name: "Tender", Description: "v1", ServiceType: "Delivery", Status: "Created", OrganizationID: org.ID}
+	require.NoError(t, store.CreateTender(ctx, tender))
+	tender.Description = "v2"
+	require.NoError(t, store.UpdateTender(ctx, tender))
+	handler := handlers.NewHandler(store)
+
+	get := func(url string, serve http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req = testutils.WithChiURLParams(req, map[string]string{"tenderId": tender.ID})
+		req = testutils.WithEmployee(req, member)
+		w := httptest.NewRecorder()
+		serve(w, req)
+		return w
+	}
+	versions := "/api/tenders/" + tender.ID + "/versions"
+	diff := "/api/tenders/" + tender.ID + "/versions/diff?from=1&to=2"
+
+	require.Equal(t, http.StatusNotFound, get(versions, handler.GetTenderVersionsHandler).Code)
+	require.Equal(t, http.StatusNotFound, get(diff, handler.GetTenderVersionsDiffHandler).Code)
+
+	tender.Status = "Published"
+	require.NoError(t, store.UpdateTender(ctx, tender))
+	require.Equal(t, http.StatusOK, get(versions, handler.GetTenderVersionsHandler).Code)
+	require.Equal(t, http.StatusOK, get(diff, handler.GetTenderVersionsDiffHandler).Code)
+}
+
+func TestGetBidVersionsDiffHandler(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 2, Username: "boss"},
+		responsible: true,
+		GetBidFunc: func(ctx context.Context, bidID string) (*db.Bid, error) {
+			return &db.Bid{ID: bidID, Status: "Published", TenderID: testID(1), OrganizationID: testID(1), Version: 3}, nil
+		},
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/bids/"+testID(1)+"/versions/diff?from=1&to=3", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(1)})
+
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.GetBidVersionsDiffHandler(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var diff models.VersionDiff
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &diff))
+	require.Equal(t, []models.FieldChange{{
+		Field: "description",
+		From:  "Bid Version Description v1",
+		To:    "Bid Version Description v3",
+	}}, diff.Changes)
+}
+
+func TestEditTenderHandlerPlatformAdmin(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:     &db.Employee{ID: 9, Username: "admin"},
+		platformRole: "admin",
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/tenders/"+testID(123)+"/edit", strings.NewReader(`{"name":"Moderated","version":2}`))
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+
+	w := httptest.NewRecorder()
+	handler.EditTenderHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Аудитор только читает
+	mockStore.platformRole = "auditor"
+	req = httptest.NewRequest(http.MethodPatch, "/api/tenders/"+testID(123)+"/edit", strings.NewReader(`{"name":"Moderated","version":2}`))
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(123)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+
+	w = httptest.NewRecorder()
+	handler.EditTenderHandler(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreateEmployeeHandler(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:     &db.Employee{ID: 9, Username: "admin"},
+		platformRole: "admin",
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	cases := []struct {
+		body string
+		code int
+	}{
+		{`{"username":"newbie","firstName":"Иван","lastName":"Петров"}`, http.StatusOK},
+		{`{"username":"admin"}`, http.StatusConflict},
+		{`{"username":"bad name"}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/employees", strings.NewReader(c.body))
+		req = testutils.WithEmployee(req, mockStore.employee)
+		w := httptest.NewRecorder()
+		handler.CreateEmployeeHandler(w, req)
+		require.Equal(t, c.code, w.Code, c.body)
+	}
+
+	// Все неверные поля перечислены в одном ответе
+	body := fmt.Sprintf(`{"username":"bad name","firstName":%q}`, strings.Repeat("Я", 51))
+	req := httptest.NewRequest(http.MethodPost, "/api/employees", strings.NewReader(body))
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.CreateEmployeeHandler(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, []string{"firstName", "username"}, validationFields(t, w))
+
+	// Создавать сотрудников может только администратор площадки
+	mockStore.platformRole = ""
+	req = httptest.NewRequest(http.MethodPost, "/api/employees", strings.NewReader(`{"username":"newbie"}`))
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.CreateEmployeeHandler(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDeactivateEmployeeHandler(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:     &db.Employee{ID: 9, Username: "admin"},
+		platformRole: "admin",
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/employees/ghost/deactivate", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"username": "ghost"})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.DeactivateEmployeeHandler(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/employees/admin/deactivate", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"username": "admin"})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.DeactivateEmployeeHandler(w, req)
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestCreateOrganizationHandler(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:     &db.Employee{ID: 9, Username: "admin"},
+		platformRole: "admin",
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	cases := []struct {
+		body string
+		code int
+	}{
+		{`{"name": "Org", "type": "LLC", "responsibles": ["admin"]}`, http.StatusOK},
+		{`{"name": "Org", "type": "LLC"}`, http.StatusBadRequest},
+		{`{"name": "Org", "type": "Corp", "responsibles": ["admin"]}`, http.StatusBadRequest},
+		{`{"name": "Org", "type": "IE", "responsibles": ["ghost"]}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/organizations", strings.NewReader(c.body))
+		req = testutils.WithEmployee(req, mockStore.employee)
+		w := httptest.NewRecorder()
+		handler.CreateOrganizationHandler(w, req)
+		require.Equal(t, c.code, w.Code, c.body)
+	}
+
+	// Все неверные поля перечислены в одном ответе
+	req := httptest.NewRequest(http.MethodPost, "/api/organizations", strings.NewReader(`{"name": " ", "type": "Corp"}`))
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.CreateOrganizationHandler(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, []string{"name", "type", "responsibles"}, validationFields(t, w))
+
+	// Создавать организации может только администратор площадки
+	mockStore.platformRole = ""
+	req = httptest.NewRequest(http.MethodPost, "/api/organizations", strings.NewReader(cases[0].body))
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.CreateOrganizationHandler(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRemoveLastResponsible(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 3, Username: "resp"},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/organizations/"+testID(1)+"/responsibles/resp", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"organizationId": testID(1), "username": "resp"})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.RemoveResponsibleHandler(w, req)
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+// failingStorage возвращает заданную ошибку из GetTender и UpdateTender
+type failingStorage struct {
+	MockStorage
+	getErr    error
+	updateErr error
+}
+
+func (s *failingStorage) GetTender(ctx context.Context, tenderID string) (*db.Tender, error) {
+	if s.getErr != nil {
+		return nil, s.getErr
+	}
+	return s.MockStorage.GetTender(ctx, tenderID)
+}
+
+func (s *failingStorage) UpdateTender(ctx context.Context, tender *db.Tender) error {
+	return s.updateErr
+}
+
+func TestStorageErrorMapping(t *testing.T) {
+	cases := []struct {
+		name      string
+		getErr    error
+		updateErr error
+		code      int
+		errCode   apierr.Code
+	}{
+		{"not found", db.ErrNotFound, nil, http.StatusNotFound, apierr.NotFound},
+		{"connection lost", errors.New("driver: bad connection"), nil, http.StatusInternalServerError, apierr.Internal},
+		{"version conflict", nil, db.ErrVersionConflict, http.StatusConflict, apierr.VersionConflict},
+		{"serialization failure", nil, fmt.Errorf("%w: pq: could not serialize access", db.ErrConflict), http.StatusConflict, apierr.Conflict},
+		{"foreign key", nil, fmt.Errorf("%w: pq: violates foreign key constraint", db.ErrForeignKey), http.StatusUnprocessableEntity, apierr.InvalidReference},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store := &failingStorage{
+				MockStorage: MockStorage{employee: &db.Employee{ID: 1, Username: "user1"}, responsible: true},
+				getErr:      c.getErr,
+				updateErr:   c.updateErr,
+			}
+			handler := handlers.NewHandler(store)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/tenders/"+testID(1)+"/edit", strings.NewReader(`{"name":"Renamed","version":2}`))
+			req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
+			req = testutils.WithEmployee(req, store.employee)
+			w := httptest.NewRecorder()
+			handler.EditTenderHandler(w, req)
+			require.Equal(t, c.code, w.Code)
+			require.Equal(t, c.errCode, decodeError(t, w).Code)
+			// Подробности сбоя не уходят клиенту
+			require.NotContains(t, w.Body.String(), "driver")
+		})
+	}
+}
+
+// decodeError разбирает тело ответа с ошибкой по схеме errorResponse
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) apierr.Response {
+	t.Helper()
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var resp apierr.Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.NotEmpty(t, resp.Reason)
+	return resp
+}
+
+// validationFields проверяет, что ответ - ошибка validation_failed, и
+// возвращает неверные поля в порядке ответа
+func validationFields(t *testing.T, w *httptest.ResponseRecorder) []string {
+	t.Helper()
+	var resp struct {
+		apierr.Response
+		Errors []struct {
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, apierr.ValidationFailed, resp.Code)
+	var fields []string
+	for _, e := range resp.Errors {
+		fields = append(fields, e.Field)
+	}
+	return fields
+}
+
+func TestErrorResponseFormat(t *testing.T) {
+	handler := handlers.NewHandler(&MockStorage{})
+
+	// Без пользователя - 401
+	req := httptest.NewRequest(http.MethodGet, "/api/tenders/my", nil)
+	w := httptest.NewRecorder()
+	handler.GetUserTendersHandler(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Equal(t, apierr.Unauthorized, decodeError(t, w).Code)
+
+	// Недопустимый переход статуса - 409 с подробностями перехода
+	mockStore := &MockStorage{employee: &db.Employee{ID: 1, Username: "user1"}, responsible: true}
+	handler = handlers.NewHandler(mockStore)
+	req = httptest.NewRequest(http.MethodPut, "/api/tenders/"+testID(1)+"/status?status=Created", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(1)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.ChangeTenderStatusHandler(w, req)
+	require.Equal(t, http.StatusConflict, w.Code)
+	var transition struct {
+		apierr.Response
+		AllowedStatuses []string `json:"allowedStatuses"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&transition))
+	require.Equal(t, apierr.InvalidTransition, transition.Code)
+	require.NotEmpty(t, transition.AllowedStatuses)
+}
+
+func TestLegacyIDs(t *testing.T) {
+	var requested string
+	mockStore := &MockStorage{
+		employee:  &db.Employee{ID: 1, Username: "user1"},
+		legacyIDs: map[int]string{42: testID(7)},
+		GetTenderFunc: func(ctx context.Context, tenderID string) (*db.Tender, error) {
+			requested = tenderID
+			return &db.Tender{ID: tenderID, Status: "Published", OrganizationID: testID(1)}, nil
+		},
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	get := func(tenderID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/tenders/"+tenderID+"/status", nil)
+		req = testutils.WithChiURLParams(req, map[string]string{"tenderId": tenderID})
+		req = testutils.WithEmployee(req, mockStore.employee)
+		w := httptest.NewRecorder()
+		handler.GetTenderStatusHandler(w, req)
+		return w
+	}
+
+	// Целый идентификатор, выданный до миграции, находит тендер по UUID
+	w := get("42")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, testID(7), requested)
+
+	// UUID приводится к каноническому виду
+	w = get("550E8400-E29B-41D4-A716-446655440000")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "550e8400-e29b-41d4-a716-446655440000", requested)
+
+	w = get("43")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, apierr.NotFound, decodeError(t, w).Code)
+
+	for _, id := range []string{"0", "-1", "not-an-id"} {
+		w = get(id)
+		require.Equal(t, http.StatusBadRequest, w.Code, id)
+	}
+}
+
+func TestArchiveTenderHandler(t *testing.T) {
+	var archived *time.Time
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1, Username: "user1"},
+		responsible: true,
+		GetTenderFunc: func(ctx context.Context, tenderID string) (*db.Tender, error) {
+			return &db.Tender{ID: tenderID, Status: "Published", OrganizationID: testID(1), Version: 1, DeletedAt: archived}, nil
+		},
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	call := func(fn http.HandlerFunc, method, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = testutils.WithChiURLParams(req, map[string]string{"tenderId": testID(5)})
+		req = testutils.WithEmployee(req, mockStore.employee)
+		w := httptest.NewRecorder()
+		fn(w, req)
+		return w
+	}
+
+	w := call(handler.ArchiveTenderHandler, http.MethodPut, "/api/tenders/"+testID(5)+"/archive", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var tender db.Tender
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&tender))
+	require.NotNil(t, tender.DeletedAt)
+	require.Equal(t, 1, *tender.DeletedBy)
+
+	// Тендер из архива нельзя архивировать повторно и менять
+	archived = tender.DeletedAt
+	w = call(handler.ArchiveTenderHandler, http.MethodPut, "/api/tenders/"+testID(5)+"/archive", "")
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Equal(t, apierr.InvalidState, decodeError(t, w).Code)
+
+	w = call(handler.EditTenderHandler, http.MethodPatch, "/api/tenders/"+testID(5)+"/edit", `{"name":"Updated Tender","version":2}`)
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Equal(t, "Tender is archived", decodeError(t, w).Reason)
+
+	// Опубликованный тендер из архива скрыт от посторонних
+	mockStore.responsible = false
+	w = call(handler.GetTenderStatusHandler, http.MethodGet, "/api/tenders/"+testID(5)+"/status", "")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = call(handler.RestoreTenderHandler, http.MethodPut, "/api/tenders/"+testID(5)+"/restore", "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	mockStore.responsible = true
+	w = call(handler.RestoreTenderHandler, http.MethodPut, "/api/tenders/"+testID(5)+"/restore", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "deletedAt")
+}
+
+func TestArchiveBidHandler(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:    &db.Employee{ID: 1, Username: "user1"},
+		responsible: true,
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(3)+"/archive", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(3)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w := httptest.NewRecorder()
+	handler.ArchiveBidHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "deletedAt")
+
+	// Восстановить можно только предложение из архива
+	req = httptest.NewRequest(http.MethodPut, "/api/bids/"+testID(3)+"/restore", nil)
+	req = testutils.WithChiURLParams(req, map[string]string{"bidId": testID(3)})
+	req = testutils.WithEmployee(req, mockStore.employee)
+	w = httptest.NewRecorder()
+	handler.RestoreBidHandler(w, req)
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Equal(t, apierr.InvalidState, decodeError(t, w).Code)
+}
+
+func TestPurgeArchiveHandler(t *testing.T) {
+	mockStore := &MockStorage{
+		employee:     &db.Employee{ID: 9, Username: "admin"},
+		platformRole: "admin",
+	}
+	handler := handlers.NewHandler(mockStore)
+
+	purge := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodDelete, "/api/archive"+query, nil)
+		req = testutils.WithEmployee(req, mockStore.employee)
+		w := httptest.NewRecorder()
+		handler.PurgeArchiveHandler(w, req)
+		return w
+	}
+
+	w := purge("")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, 365*24*time.Hour, mockStore.purgedRetention)
+	var result db.PurgeResult
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+	require.Equal(t, db.PurgeResult{Tenders: 1, Bids: 2, Reviews: 3}, result)
+
+	w = purge("?retentionDays=0")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Zero(t, mockStore.purgedRetention)
+
+	for _, q := range []string{"?retentionDays=-1", "?retentionDays=week"} {
+		require.Equal(t, http.StatusBadRequest, purge(q).Code, q)
+	}
+
+	// Очистка архива доступна только администратору площадки
+	mockStore.platformRole = ""
+	mockStore.responsible = true
+	w = purge("?retentionDays=30")
+	require.Equal(t, http.StatusForbidden, w.Code)
 }
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// idParams - параметры с идентификаторами: таблица, в которой ищется прежний
+// целый идентификатор, и название записи для ответа 404
+var idParams = map[string]struct{ table, subject string }{
+	"organizationId": {db.OrganizationTable, "Organization"},
+	"tenderId":       {db.TenderTable, "Tender"},
+	"bidId":          {db.BidTable, "Bid"},
+}
+
+// parseID разбирает значение raw параметра param (organizationId, tenderId,
+// bidId) и возвращает UUID в каноническом виде. На время перехода принимает и
+// прежний целый идентификатор записи, созданной до миграции на UUID. Если
+// значение неверное или записи с таким прежним идентификатором нет, отвечает
+// 400 или 404 и возвращает false.
+func (h *Handler) parseID(w http.ResponseWriter, r *http.Request, param, raw string) (string, bool) {
+	if id, err := uuid.Parse(raw); err == nil {
+		return id.String(), true
+	}
+
+	legacyID, err := strconv.Atoi(raw)
+	if err != nil || legacyID <= 0 {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid "+param)
+		return "", false
+	}
+	p := idParams[param]
+	id, err := h.Store.ResolveLegacyID(r.Context(), p.table, legacyID)
+	if err != nil {
+		storageError(w, r, err, p.subject)
+		return "", false
+	}
+	return id, true
+}
+
+// pathID - parseID для параметра пути param
+func (h *Handler) pathID(w http.ResponseWriter, r *http.Request, param string) (string, bool) {
+	return h.parseID(w, r, param, chi.URLParam(r, param))
+}
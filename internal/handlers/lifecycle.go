@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/authz"
+	"tenders/internal/lifecycle"
+)
+
+// transitionErrorResponse - тело ответа 409 при недопустимой смене статуса
+type transitionErrorResponse struct {
+	apierr.Response
+	Entity          string   `json:"entity"`
+	From            string   `json:"from"`
+	To              string   `json:"to"`
+	AllowedStatuses []string `json:"allowedStatuses"`
+}
+
+// writeTransitionError отвечает 400 на неизвестный статус и 409 на запрещенный переход
+func writeTransitionError(w http.ResponseWriter, err error) {
+	var te *lifecycle.TransitionError
+	switch {
+	case errors.As(err, &te):
+		apierr.WriteJSON(w, http.StatusConflict, transitionErrorResponse{
+			Response:        apierr.Response{Reason: te.Error(), Code: apierr.InvalidTransition},
+			Entity:          te.Entity,
+			From:            te.From,
+			To:              te.To,
+			AllowedStatuses: te.Allowed,
+		})
+	case errors.Is(err, lifecycle.ErrUnknownStatus):
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid status value")
+	default:
+		apierr.Write(w, http.StatusInternalServerError, apierr.Internal, "Failed to change status")
+	}
+}
+
+// bidActorRoles определяет, в каком качестве сотрудник действует над предложением
+func (h *Handler) bidActorRoles(ctx context.Context, employee *db.Employee, bid *db.Bid) (lifecycle.Role, error) {
+	var roles lifecycle.Role
+	if employee.Username == bid.CreatorUsername {
+		roles |= lifecycle.RoleAuthor
+	}
+	isResponsible, err := h.Store.IsUserResponsibleForOrganization(ctx, employee.ID, bid.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	if isResponsible {
+		roles |= lifecycle.RoleResponsible
+	}
+	return roles, nil
+}
+
+// tenderActorRoles определяет, в каком качестве сотрудник действует над
+// тендером. Администратор площадки управляет любыми тендерами и меняет их
+// статус наравне с ответственным за организацию.
+func (h *Handler) tenderActorRoles(ctx context.Context, employee *db.Employee, tender *db.Tender) (lifecycle.Role, error) {
+	roles, err := h.authz.Roles(ctx, employee, authz.Tender(tender))
+	if err != nil {
+		return 0, err
+	}
+	var actor lifecycle.Role
+	for _, role := range roles {
+		if role == authz.RolePlatformAdmin || role == authz.RoleResponsible {
+			actor |= lifecycle.RoleResponsible
+		}
+	}
+	return actor, nil
+}
+
+// closeTenderOnApproval - хук перехода предложения в Approved: закрывает тендер
+func (h *Handler) closeTenderOnApproval(ctx context.Context, bid *db.Bid) error {
+	tender, err := h.Store.GetTender(ctx, bid.TenderID)
+	if err != nil {
+		return fmt.Errorf("get tender %s: %w", bid.TenderID, err)
+	}
+	err = h.tenders.Check(lifecycle.Request{
+		From:         tender.Status,
+		To:           lifecycle.TenderClosed,
+		Actor:        lifecycle.RoleSystem,
+		TenderStatus: tender.Status,
+	})
+	if err != nil {
+		return err
+	}
+	tender.Status = lifecycle.TenderClosed
+	if err := h.Store.UpdateTender(ctx, tender); err != nil {
+		return err
+	}
+	return h.tenders.AfterTransition(ctx, tender.Status, tender)
+}
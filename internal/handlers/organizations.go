@@ -0,0 +1,359 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/authz"
+	"tenders/internal/validate"
+)
+
+// defaultResponsibleRole - роль ответственного, если она не указана (см. миграцию 0006)
+const defaultResponsibleRole = "member"
+
+// errLastResponsible - попытка снять последнего ответственного организации
+var errLastResponsible = errors.New("organization must have at least one responsible")
+
+// organizationFromPath загружает организацию из пути и проверяет право action на нее
+func (h *Handler) organizationFromPath(w http.ResponseWriter, r *http.Request, action authz.Action) (*db.Employee, *db.Organization, bool) {
+	orgID, ok := h.pathID(w, r, "organizationId")
+	if !ok {
+		return nil, nil, false
+	}
+
+	employee, ok := caller(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	organization, err := h.Store.GetOrganization(r.Context(), orgID)
+	if err != nil {
+		storageError(w, r, err, "Organization")
+		return nil, nil, false
+	}
+
+	if !h.authorize(w, r, employee, action, authz.Organization(organization.ID)) {
+		return nil, nil, false
+	}
+	return employee, organization, true
+}
+
+// CreateOrganizationHandler заводит организацию вместе с ее ответственными.
+// Без хотя бы одного ответственного организацию создать нельзя.
+func (h *Handler) CreateOrganizationHandler(w http.ResponseWriter, r *http.Request) {
+	admin, ok := caller(w, r)
+	if !ok || !h.authorize(w, r, admin, authz.OrganizationCreate, authz.Platform()) {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	var input struct {
+		Name         string   `json:"name"`
+		Description  string   `json:"description"`
+		Type         string   `json:"type"`
+		Responsibles []string `json:"responsibles"`
+	}
+	if err := json.Unmarshal(body, &input); err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON format")
+		return
+	}
+
+	organization := db.Organization{
+		Name:        strings.TrimSpace(input.Name),
+		Description: input.Description,
+		Type:        input.Type,
+	}
+	// Валидация полей по правилам models.Organization
+	err = organizationRules.Validate(&organization)
+	if len(input.Responsibles) == 0 {
+		err = withFieldError(err, validate.FieldError{
+			Field: "responsibles", Rule: "required", Message: errLastResponsible.Error(),
+		})
+	}
+	if err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	responsibles := make([]*db.Employee, 0, len(input.Responsibles))
+	seen := map[string]bool{}
+	for _, username := range input.Responsibles {
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
+		if errors.Is(err, db.ErrNotFound) {
+			apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, fmt.Sprintf("Employee %q not found", username))
+			return
+		}
+		if err != nil {
+			storageError(w, r, err, "Employee")
+			return
+		}
+		if !employee.Active() {
+			apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, fmt.Sprintf("Employee %q is deactivated", username))
+			return
+		}
+		responsibles = append(responsibles, employee)
+	}
+
+	err = h.Store.WithTx(r.Context(), func(ctx context.Context) error {
+		if err := h.Store.CreateOrganization(ctx, &organization); err != nil {
+			return err
+		}
+		for _, employee := range responsibles {
+			if err := h.Store.AddResponsible(ctx, organization.ID, employee.ID, defaultResponsibleRole); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		storageError(w, r, err, "Organization")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(organization)
+}
+
+// ListOrganizationsHandler ищет организации по подстроке search в названии
+func (h *Handler) ListOrganizationsHandler(w http.ResponseWriter, r *http.Request) {
+	viewer, ok := caller(w, r)
+	if !ok || !h.authorize(w, r, viewer, authz.OrganizationView, authz.Platform()) {
+		return
+	}
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
+	search := strings.TrimSpace(r.URL.Query().Get("search"))
+
+	organizations, total, err := h.Store.ListOrganizations(r.Context(), search, fetch(page))
+	if err != nil {
+		storageError(w, r, err, "Organizations")
+		return
+	}
+
+	writePage(w, r, page, organizations, total, db.OrganizationCursor)
+}
+
+// GetOrganizationHandler возвращает организацию по id
+func (h *Handler) GetOrganizationHandler(w http.ResponseWriter, r *http.Request) {
+	_, organization, ok := h.organizationFromPath(w, r, authz.OrganizationView)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(organization)
+}
+
+// UpdateOrganizationHandler частично обновляет название, описание и тип организации
+func (h *Handler) UpdateOrganizationHandler(w http.ResponseWriter, r *http.Request) {
+	_, organization, ok := h.organizationFromPath(w, r, authz.OrganizationManage)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Cannot read body")
+		return
+	}
+	defer r.Body.Close()
+
+	var input struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Type        *string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &input); err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON")
+		return
+	}
+	if input.Name != nil {
+		organization.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Description != nil {
+		organization.Description = *input.Description
+	}
+	if input.Type != nil {
+		organization.Type = *input.Type
+	}
+	if err := organizationRules.Validate(organization); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	if err := h.Store.UpdateOrganization(r.Context(), organization); err != nil {
+		storageError(w, r, err, "Organization")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(organization)
+}
+
+// DeleteOrganizationHandler удаляет организацию вместе с ответственными и политиками
+func (h *Handler) DeleteOrganizationHandler(w http.ResponseWriter, r *http.Request) {
+	_, organization, ok := h.organizationFromPath(w, r, authz.OrganizationDelete)
+	if !ok {
+		return
+	}
+
+	err := h.Store.DeleteOrganization(r.Context(), organization.ID)
+	if errors.Is(err, db.ErrForeignKey) {
+		// Записи закупок хранятся до очистки архива
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, "Organization has tenders or bids")
+		return
+	}
+	if err != nil {
+		storageError(w, r, err, "Organization")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetResponsiblesHandler возвращает ответственных за организацию
+func (h *Handler) GetResponsiblesHandler(w http.ResponseWriter, r *http.Request) {
+	_, organization, ok := h.organizationFromPath(w, r, authz.OrganizationView)
+	if !ok {
+		return
+	}
+
+	responsibles, err := h.Store.GetResponsibles(r.Context(), organization.ID)
+	if err != nil {
+		storageError(w, r, err, "Responsibles")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(responsibles)
+}
+
+// AddResponsibleHandler назначает сотрудника ответственным за организацию.
+// Повторное назначение меняет роль ответственного.
+func (h *Handler) AddResponsibleHandler(w http.ResponseWriter, r *http.Request) {
+	_, organization, ok := h.organizationFromPath(w, r, authz.OrganizationManage)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Cannot read body")
+		return
+	}
+	defer r.Body.Close()
+
+	var input struct {
+		Username string `json:"username"`
+		Role     string `json:"role"`
+	}
+	if err := json.Unmarshal(body, &input); err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON")
+		return
+	}
+	if input.Role == "" {
+		input.Role = defaultResponsibleRole
+	}
+	if err := responsibleRules.Validate(&input, "role"); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	employee, err := h.Store.GetEmployeeByUsername(r.Context(), input.Username)
+	if err != nil {
+		storageError(w, r, err, "Employee")
+		return
+	}
+	if !employee.Active() {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, "Employee is deactivated")
+		return
+	}
+
+	if err := h.Store.AddResponsible(r.Context(), organization.ID, employee.ID, input.Role); err != nil {
+		storageError(w, r, err, "Responsible")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(db.Responsible{
+		UserID:         employee.ID,
+		Username:       employee.Username,
+		OrganizationID: organization.ID,
+		Role:           input.Role,
+	})
+}
+
+// RemoveResponsibleHandler снимает сотрудника с ответственности. Последнего
+// ответственного снять нельзя: строка организации блокируется, чтобы два
+// параллельных запроса не сняли двух последних ответственных одновременно.
+func (h *Handler) RemoveResponsibleHandler(w http.ResponseWriter, r *http.Request) {
+	_, organization, ok := h.organizationFromPath(w, r, authz.OrganizationManage)
+	if !ok {
+		return
+	}
+
+	employee, ok := h.employeeFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.Store.WithTx(r.Context(), func(ctx context.Context) error {
+		if _, err := h.Store.LockOrganization(ctx, organization.ID); err != nil {
+			return err
+		}
+		responsibles, err := h.Store.GetResponsibles(ctx, organization.ID)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, resp := range responsibles {
+			if resp.UserID == employee.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return db.ErrNotFound
+		}
+		if len(responsibles) == 1 {
+			return errLastResponsible
+		}
+		return h.Store.RemoveResponsible(ctx, organization.ID, employee.ID)
+	})
+	if errors.Is(err, db.ErrNotFound) {
+		apierr.Write(w, http.StatusNotFound, apierr.NotFound, "Employee is not responsible for the organization")
+		return
+	}
+	if errors.Is(err, errLastResponsible) {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, err.Error())
+		return
+	}
+	if err != nil {
+		storageError(w, r, err, "Responsible")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
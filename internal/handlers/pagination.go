@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+)
+
+const (
+	defaultLimit = 5
+	maxLimit     = 50
+)
+
+type PaginationParams struct {
+	Limit  int
+	Offset int
+}
+
+// parsePaginationParams парсит limit и offset из query. Неверные значения
+// не заменяются значениями по умолчанию: клиент получает 400. limit=0 означает
+// значение по умолчанию.
+func parsePaginationParams(w http.ResponseWriter, r *http.Request) (PaginationParams, bool) {
+	params := PaginationParams{Limit: defaultLimit}
+	query := r.URL.Query()
+
+	if s := query.Get("limit"); s != "" {
+		l, err := strconv.Atoi(s)
+		if err != nil || l < 0 || l > maxLimit {
+			apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest,
+				fmt.Sprintf("limit must be an integer from 0 to %d", maxLimit))
+			return params, false
+		}
+		if l > 0 {
+			params.Limit = l
+		}
+	}
+	if s := query.Get("offset"); s != "" {
+		o, err := strconv.Atoi(s)
+		if err != nil || o < 0 {
+			apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "offset must be a non-negative integer")
+			return params, false
+		}
+		params.Offset = o
+	}
+	return params, true
+}
+
+// parsePage парсит параметры страницы для списков с курсорами: limit, offset
+// и cursor. Курсор и offset взаимоисключающие.
+func parsePage(w http.ResponseWriter, r *http.Request) (db.Page, bool) {
+	params, ok := parsePaginationParams(w, r)
+	if !ok {
+		return db.Page{}, false
+	}
+	page := db.Page{Limit: params.Limit, Offset: params.Offset}
+
+	if s := r.URL.Query().Get("cursor"); s != "" {
+		if r.URL.Query().Has("offset") {
+			apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "cursor and offset cannot be combined")
+			return page, false
+		}
+		cursor, err := decodeCursor(s)
+		if err != nil {
+			apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid cursor")
+			return page, false
+		}
+		page.Cursor = cursor
+	}
+	return page, true
+}
+
+// parseIntKeyPage - parsePage для списков, строки которых идентифицируются
+// целым id (сотрудники, история версий): курсор другого списка отклоняется
+func parseIntKeyPage(w http.ResponseWriter, r *http.Request) (db.Page, bool) {
+	page, ok := parsePage(w, r)
+	if !ok || page.Cursor == nil {
+		return page, ok
+	}
+	if _, err := strconv.Atoi(page.Cursor.ID); err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid cursor")
+		return page, false
+	}
+	return page, true
+}
+
+// cursorToken - содержимое курсора. Клиент получает его в base64 и не
+// разбирает: формат может меняться.
+type cursorToken struct {
+	Name      string    `json:"n,omitempty"`
+	CreatedAt time.Time `json:"c,omitzero"`
+	Version   int       `json:"v,omitempty"`
+	ID        string    `json:"i"`
+	Before    bool      `json:"b,omitempty"`
+}
+
+func encodeCursor(c db.Cursor) string {
+	data, _ := json.Marshal(cursorToken(c))
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (*db.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var token cursorToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	if token.ID == "" {
+		return nil, fmt.Errorf("cursor without id")
+	}
+	c := db.Cursor(token)
+	return &c, nil
+}
+
+// fetch возвращает параметры выборки из хранилища: на одну строку больше
+// запрошенного, чтобы writePage узнал, есть ли строки за пределами страницы
+func fetch(page db.Page) db.Page {
+	page.Limit++
+	return page
+}
+
+// writePage отвечает страницей списка, выбранной с параметрами fetch(page),
+// и пишет заголовки X-Total-Count и Link (RFC 8288) со ссылками first, prev
+// и next. Ссылки prev и next содержат курсоры, так что следующие страницы
+// выбираются по ключу сортировки, даже если первая запрошена через offset.
+func writePage[T any](w http.ResponseWriter, r *http.Request, page db.Page, items []T, total int, cursor func(T) db.Cursor) {
+	before := page.Cursor != nil && page.Cursor.Before
+	more := len(items) > page.Limit
+	if more {
+		// Лишняя строка - самая дальняя от курсора
+		if before {
+			items = items[1:]
+		} else {
+			items = items[:page.Limit]
+		}
+	}
+	hasPrev := page.Offset > 0 || (page.Cursor != nil && !page.Cursor.Before) || (before && more)
+	hasNext := before || more
+
+	links := []string{pageLink(r, page.Limit, "", "first")}
+	if len(items) > 0 {
+		if hasPrev {
+			c := cursor(items[0])
+			c.Before = true
+			links = append(links, pageLink(r, page.Limit, encodeCursor(c), "prev"))
+		}
+		if hasNext {
+			links = append(links, pageLink(r, page.Limit, encodeCursor(cursor(items[len(items)-1])), "next"))
+		}
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	w.Header().Set("Link", strings.Join(links, ", "))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// pageLink возвращает элемент заголовка Link: адрес текущего запроса с
+// курсором cursor (без курсора - первая страница) вместо offset
+func pageLink(r *http.Request, limit int, cursor, rel string) string {
+	query := r.URL.Query()
+	query.Del("offset")
+	query.Del("cursor")
+	query.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	target := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+}
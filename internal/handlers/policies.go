@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/approval"
+	"tenders/internal/authz"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// approvalPolicy возвращает действующую политику: тендера, организации или по умолчанию
+func (h *Handler) approvalPolicy(ctx context.Context, organizationID string, tenderID *string) (*db.ApprovalPolicy, error) {
+	policy, err := h.Store.GetApprovalPolicy(ctx, organizationID, tenderID)
+	if errors.Is(err, db.ErrNotFound) {
+		def := approval.Default()
+		return &db.ApprovalPolicy{
+			OrganizationID:  organizationID,
+			Kind:            string(def.Kind),
+			Required:        def.Required,
+			RejectThreshold: def.RejectThreshold,
+			RoleWeights:     db.RoleWeights{},
+		}, nil
+	}
+	return policy, err
+}
+
+func toApprovalPolicy(p *db.ApprovalPolicy) approval.Policy {
+	return approval.Policy{
+		Kind:            approval.Kind(p.Kind),
+		Required:        p.Required,
+		RejectThreshold: p.RejectThreshold,
+		RoleWeights:     p.RoleWeights,
+	}
+}
+
+// evaluateDecisions подводит итог голосования по предложению. Предложение
+// решает организация тендера: голосуют ее ответственные, а действует политика
+// тендера, иначе политика организации тендера или политика по умолчанию.
+// Голоса сотрудников, которые больше не отвечают за организацию, не учитываются.
+func (h *Handler) evaluateDecisions(ctx context.Context, bid *db.Bid, tender *db.Tender) (approval.Outcome, error) {
+	policy, err := h.approvalPolicy(ctx, tender.OrganizationID, &tender.ID)
+	if err != nil {
+		return approval.Pending, err
+	}
+	responsibles, err := h.Store.GetResponsibles(ctx, tender.OrganizationID)
+	if err != nil {
+		return approval.Pending, err
+	}
+	decisions, err := h.Store.GetBidDecisions(ctx, bid.ID)
+	if err != nil {
+		return approval.Pending, err
+	}
+
+	roles := make(map[int]string, len(responsibles))
+	electorate := make([]string, 0, len(responsibles))
+	for _, r := range responsibles {
+		roles[r.UserID] = r.Role
+		electorate = append(electorate, r.Role)
+	}
+	votes := make([]approval.Vote, 0, len(decisions))
+	for _, d := range decisions {
+		role, ok := roles[d.UserID]
+		if !ok {
+			continue
+		}
+		votes = append(votes, approval.Vote{Role: role, Approve: d.Decision == "Approved"})
+	}
+	return toApprovalPolicy(policy).Evaluate(electorate, votes), nil
+}
+
+// policyInput - тело запроса на сохранение политики
+type policyInput struct {
+	Kind            string         `json:"kind"`
+	Required        int            `json:"required"`
+	RejectThreshold int            `json:"rejectThreshold"`
+	RoleWeights     map[string]int `json:"roleWeights"`
+}
+
+// authorizePolicy проверяет право вызывающего на действие с политикой организации.
+// Пишет ошибку в ответ и возвращает false, если права нет.
+func (h *Handler) authorizePolicy(w http.ResponseWriter, r *http.Request, action authz.Action, organizationID string) bool {
+	employee, ok := caller(w, r)
+	if !ok {
+		return false
+	}
+	return h.authorize(w, r, employee, action, authz.Organization(organizationID))
+}
+
+// policyScope извлекает из пути организацию и, для /tenders/{tenderId}/..., тендер
+func (h *Handler) policyScope(w http.ResponseWriter, r *http.Request) (organizationID string, tenderID *string, ok bool) {
+	if chi.URLParam(r, "tenderId") != "" {
+		id, ok := h.pathID(w, r, "tenderId")
+		if !ok {
+			return "", nil, false
+		}
+		tender, err := h.Store.GetTender(r.Context(), id)
+		if err != nil {
+			storageError(w, r, err, "Tender")
+			return "", nil, false
+		}
+		return tender.OrganizationID, &tender.ID, true
+	}
+
+	id, ok := h.pathID(w, r, "organizationId")
+	if !ok {
+		return "", nil, false
+	}
+	return id, nil, true
+}
+
+// GetApprovalPolicyHandler возвращает действующую политику организации или
+// тендера. Для тендера это политика, по которой решаются его предложения:
+// политика тендера, иначе политика его организации или политика по умолчанию.
+func (h *Handler) GetApprovalPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	organizationID, tenderID, ok := h.policyScope(w, r)
+	if !ok || !h.authorizePolicy(w, r, authz.PolicyView, organizationID) {
+		return
+	}
+
+	policy, err := h.approvalPolicy(r.Context(), organizationID, tenderID)
+	if err != nil {
+		storageError(w, r, err, "Approval policy")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// PutApprovalPolicyHandler создает или заменяет политику организации или тендера
+func (h *Handler) PutApprovalPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	organizationID, tenderID, ok := h.policyScope(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	var input policyInput
+	if err := json.Unmarshal(body, &input); err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON format")
+		return
+	}
+
+	policy := &db.ApprovalPolicy{
+		OrganizationID:  organizationID,
+		TenderID:        tenderID,
+		Kind:            input.Kind,
+		Required:        input.Required,
+		RejectThreshold: input.RejectThreshold,
+		RoleWeights:     input.RoleWeights,
+	}
+	if policy.RejectThreshold == 0 {
+		policy.RejectThreshold = 1
+	}
+	if policy.RoleWeights == nil {
+		policy.RoleWeights = db.RoleWeights{}
+	}
+	if err := toApprovalPolicy(policy).Validate(); err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, err.Error())
+		return
+	}
+
+	if !h.authorizePolicy(w, r, authz.PolicyManage, organizationID) {
+		return
+	}
+
+	if err := h.Store.SaveApprovalPolicy(r.Context(), policy); err != nil {
+		storageError(w, r, err, "Approval policy")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policy)
+}
+
+// DeleteApprovalPolicyHandler удаляет политику, после чего действует политика
+// организации (для тендера) или политика по умолчанию
+func (h *Handler) DeleteApprovalPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	organizationID, tenderID, ok := h.policyScope(w, r)
+	if !ok || !h.authorizePolicy(w, r, authz.PolicyManage, organizationID) {
+		return
+	}
+
+	if err := h.Store.DeleteApprovalPolicy(r.Context(), organizationID, tenderID); err != nil {
+		storageError(w, r, err, "Approval policy")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewRouter регистрирует все операции openapi.yml под префиксом /api.
+// middlewares (логирование, аутентификация и т.д.) применяются ко всем маршрутам.
+// Соответствие маршрутов спецификации проверяет TestRouterMatchesOpenAPI.
+func NewRouter(h *Handler, middlewares ...func(http.Handler) http.Handler) chi.Router {
+	r := chi.NewRouter()
+	r.Use(middlewares...)
+
+	r.Route("/api", func(r chi.Router) {
+		r.Get("/ping", h.PingHandler)
+		// сотрудники
+		r.Post("/employees", h.CreateEmployeeHandler)
+		r.Get("/employees", h.ListEmployeesHandler)
+		r.Get("/employees/{username}", h.GetEmployeeHandler)
+		r.Patch("/employees/{username}", h.UpdateEmployeeHandler)
+		r.Post("/employees/{username}/deactivate", h.DeactivateEmployeeHandler)
+		// организации
+		r.Post("/organizations", h.CreateOrganizationHandler)
+		r.Get("/organizations", h.ListOrganizationsHandler)
+		r.Get("/organizations/{organizationId}", h.GetOrganizationHandler)
+		r.Patch("/organizations/{organizationId}", h.UpdateOrganizationHandler)
+		r.Delete("/organizations/{organizationId}", h.DeleteOrganizationHandler)
+		r.Get("/organizations/{organizationId}/responsibles", h.GetResponsiblesHandler)
+		r.Post("/organizations/{organizationId}/responsibles", h.AddResponsibleHandler)
+		r.Delete("/organizations/{organizationId}/responsibles/{username}", h.RemoveResponsibleHandler)
+		// тендеры
+		r.Post("/tenders/new", h.CreateTenderHandler)
+		r.Get("/tenders", h.GetTendersHandler)
+		r.Get("/tenders/my", h.GetUserTendersHandler)
+		r.Get("/tenders/{tenderId}/status", h.GetTenderStatusHandler)
+		r.Put("/tenders/{tenderId}/status", h.ChangeTenderStatusHandler)
+		r.Patch("/tenders/{tenderId}/edit", h.EditTenderHandler)
+		r.Get("/tenders/{tenderId}/versions", h.GetTenderVersionsHandler)
+		r.Get("/tenders/{tenderId}/versions/diff", h.GetTenderVersionsDiffHandler)
+		r.Put("/tenders/{tenderId}/rollback/{version}", h.RollbackTenderHandler)
+		r.Put("/tenders/{tenderId}/archive", h.ArchiveTenderHandler)
+		r.Put("/tenders/{tenderId}/restore", h.RestoreTenderHandler)
+		// политики принятия решений
+		r.Get("/organizations/{organizationId}/approval_policy", h.GetApprovalPolicyHandler)
+		r.Put("/organizations/{organizationId}/approval_policy", h.PutApprovalPolicyHandler)
+		r.Delete("/organizations/{organizationId}/approval_policy", h.DeleteApprovalPolicyHandler)
+		r.Get("/tenders/{tenderId}/approval_policy", h.GetApprovalPolicyHandler)
+		r.Put("/tenders/{tenderId}/approval_policy", h.PutApprovalPolicyHandler)
+		r.Delete("/tenders/{tenderId}/approval_policy", h.DeleteApprovalPolicyHandler)
+		// предложения (bids)
+		r.Post("/bids/new", h.CreateBidHandler)
+		r.Get("/bids/my", h.GetUserBidsHandler)
+		r.Get("/bids/{tenderId}/list", h.GetBidsForTenderHandler)
+		r.Patch("/bids/{bidId}/edit", h.EditBidHandler)
+		r.Get("/bids/{bidId}/status", h.GetBidStatusHandler)
+		r.Put("/bids/{bidId}/status", h.UpdateBidStatusHandler)
+		r.Put("/bids/{bidId}/rollback/{version}", h.RollbackBidHandler)
+		r.Put("/bids/{bidId}/submit_decision", h.SubmitBidDecisionHandler)
+		r.Get("/bids/{bidId}/versions", h.GetBidVersionsHandler)
+		r.Get("/bids/{bidId}/versions/diff", h.GetBidVersionsDiffHandler)
+		r.Get("/bids/{bidId}/decisions", h.GetBidDecisionsHandler)
+		r.Get("/bids/{bidId}/decisions/history", h.GetBidDecisionHistoryHandler)
+		r.Delete("/bids/{bidId}/decisions", h.RevokeBidDecisionHandler)
+		r.Get("/bids/{tenderId}/reviews", h.GetBidReviewsHandler)
+		r.Put("/bids/{bidId}/feedback", h.CreateBidFeedbackHandler)
+		r.Put("/bids/{bidId}/archive", h.ArchiveBidHandler)
+		r.Put("/bids/{bidId}/restore", h.RestoreBidHandler)
+		// архив
+		r.Delete("/archive", h.PurgeArchiveHandler)
+	})
+	return r
+}
@@ -0,0 +1,123 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"tenders"
+	"tenders/db"
+	"tenders/internal/handlers"
+	"tenders/internal/handlers/testutils"
+	"tenders/internal/openapi"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// specOperations читает openapi.yml и возвращает операции в виде "GET /api/tenders"
+func specOperations(t *testing.T) []string {
+	t.Helper()
+	data, err := os.ReadFile("../../openapi.yml")
+	require.NoError(t, err)
+
+	var spec struct {
+		Paths map[string]map[string]yaml.Node `yaml:"paths"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &spec))
+
+	var ops []string
+	for path, item := range spec.Paths {
+		for method := range item {
+			switch method {
+			case "get", "put", "post", "patch", "delete":
+				ops = append(ops, strings.ToUpper(method)+" /api"+path)
+			}
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// TestRouterMatchesOpenAPI проверяет, что каждая операция спецификации
+// зарегистрирована с тем же методом и что в роутере нет недокументированных маршрутов
+func TestRouterMatchesOpenAPI(t *testing.T) {
+	ops := specOperations(t)
+	require.NotEmpty(t, ops)
+
+	var routes []string
+	router := handlers.NewRouter(handlers.NewHandler(&MockStorage{}))
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+strings.TrimSuffix(route, "/"))
+		return nil
+	})
+	require.NoError(t, err)
+	sort.Strings(routes)
+
+	require.Equal(t, ops, routes)
+}
+
+// TestHandlersMatchOpenAPI прогоняет запросы через роутер со строгой проверкой
+// спецификации: обработчик, ответ которого расходится с openapi.yml, получает 500
+func TestHandlersMatchOpenAPI(t *testing.T) {
+	var mismatches []string
+	validator, err := openapi.New(tenders.OpenAPISpec, openapi.Options{
+		Strict: true,
+		Logf: func(format string, args ...interface{}) {
+			mismatches = append(mismatches, fmt.Sprintf(format, args...))
+		},
+	})
+	require.NoError(t, err)
+
+	employee := &db.Employee{ID: 1, Username: "user1"}
+	mockStore := &MockStorage{employee: employee, responsible: true, platformRole: "admin"}
+	router := handlers.NewRouter(handlers.NewHandler(mockStore), validator.Middleware)
+
+	cases := []struct {
+		method, target, body string
+		status               int
+	}{
+		{http.MethodGet, "/api/ping", "", http.StatusOK},
+		{http.MethodGet, "/api/tenders?service_type=Delivery&limit=5", "", http.StatusOK},
+		{http.MethodGet, "/api/tenders/my", "", http.StatusOK},
+		{http.MethodPost, "/api/tenders/new", `{"name":"Tender","description":"Desc","serviceType":"Delivery","organizationId":"00000000-0000-0000-0000-000000000001"}`, http.StatusOK},
+		{http.MethodGet, "/api/tenders/" + testID(1) + "/status", "", http.StatusOK},
+		{http.MethodPut, "/api/tenders/" + testID(1) + "/status?status=Closed", "", http.StatusOK},
+		{http.MethodPatch, "/api/tenders/" + testID(1) + "/edit", `{"name":"New name","version":2}`, http.StatusOK},
+		{http.MethodGet, "/api/tenders/" + testID(1) + "/versions", "", http.StatusOK},
+		{http.MethodGet, "/api/bids/" + testID(1) + "/status", "", http.StatusOK},
+		{http.MethodPatch, "/api/bids/" + testID(1) + "/edit", `{"name":"New name","version":1}`, http.StatusOK},
+		{http.MethodGet, "/api/bids/" + testID(1) + "/decisions", "", http.StatusOK},
+		{http.MethodGet, "/api/organizations/" + testID(1), "", http.StatusOK},
+		{http.MethodGet, "/api/employees", "", http.StatusOK},
+		{http.MethodPut, "/api/tenders/" + testID(1) + "/archive", "", http.StatusOK},
+		{http.MethodPut, "/api/tenders/" + testID(1) + "/restore", "", http.StatusConflict},
+		{http.MethodPut, "/api/bids/" + testID(1) + "/archive", "", http.StatusOK},
+		{http.MethodDelete, "/api/archive?retentionDays=30", "", http.StatusOK},
+		// Запросы, противоречащие спецификации, отклоняются до обработчика
+		{http.MethodGet, "/api/tenders?service_type=Repair", "", http.StatusBadRequest},
+		{http.MethodPut, "/api/tenders/" + testID(1) + "/status?status=Archived", "", http.StatusBadRequest},
+		{http.MethodGet, "/api/tenders?limit=500", "", http.StatusBadRequest},
+		{http.MethodDelete, "/api/archive?retentionDays=-1", "", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.method+" "+c.target, func(t *testing.T) {
+			mismatches = nil
+			req := httptest.NewRequest(c.method, c.target, strings.NewReader(c.body))
+			if c.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req = testutils.WithEmployee(req, employee)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Empty(t, mismatches)
+			require.Equal(t, c.status, w.Code, w.Body.String())
+		})
+	}
+}
@@ -2,33 +2,81 @@ package handlers
 
 import (
 	"context"
+	"time"
+
 	"tenders/db"
 )
 
 type StorageInterface interface {
+	// WithTx выполняет fn атомарно: вызовы хранилища с контекстом,
+	// переданным в fn, фиксируются или откатываются вместе.
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// ResolveLegacyID находит UUID организации, тендера или предложения
+	// (таблица db.OrganizationTable, db.TenderTable, db.BidTable) по целому
+	// идентификатору, выданному до перехода на UUID
+	ResolveLegacyID(ctx context.Context, table string, legacyID int) (string, error)
+
+	CreateEmployee(ctx context.Context, employee *db.Employee) error
 	GetEmployeeByUsername(ctx context.Context, username string) (*db.Employee, error)
-	IsUserResponsibleForOrganization(ctx context.Context, userID, organizationID int) (bool, error)
+	ListEmployees(ctx context.Context, search string, includeInactive bool, page db.Page) ([]db.Employee, int, error)
+	UpdateEmployee(ctx context.Context, employee *db.Employee) error
+	DeactivateEmployee(ctx context.Context, username string) error
+	IsUserResponsibleForOrganization(ctx context.Context, userID int, organizationID string) (bool, error)
+	IsUserMemberOfOrganization(ctx context.Context, userID int, organizationID string) (bool, error)
+	GetPlatformRole(ctx context.Context, userID int) (string, error)
+	CreateOrganization(ctx context.Context, organization *db.Organization) error
+	GetOrganization(ctx context.Context, id string) (*db.Organization, error)
+	LockOrganization(ctx context.Context, id string) (*db.Organization, error)
+	ListOrganizations(ctx context.Context, search string, page db.Page) ([]db.Organization, int, error)
+	UpdateOrganization(ctx context.Context, organization *db.Organization) error
+	DeleteOrganization(ctx context.Context, id string) error
+	AddResponsible(ctx context.Context, organizationID string, userID int, role string) error
+	RemoveResponsible(ctx context.Context, organizationID string, userID int) error
+	GetResponsibles(ctx context.Context, organizationID string) ([]db.Responsible, error)
+
+	GetApprovalPolicy(ctx context.Context, organizationID string, tenderID *string) (*db.ApprovalPolicy, error)
+	SaveApprovalPolicy(ctx context.Context, policy *db.ApprovalPolicy) error
+	DeleteApprovalPolicy(ctx context.Context, organizationID string, tenderID *string) error
 
 	CreateTender(ctx context.Context, tender *db.Tender) error
-	GetTender(ctx context.Context, tenderID int) (*db.Tender, error)
+	GetTender(ctx context.Context, tenderID string) (*db.Tender, error)
+	// LockTender читает тендер с блокировкой строки до конца транзакции WithTx
+	LockTender(ctx context.Context, tenderID string) (*db.Tender, error)
 	UpdateTender(ctx context.Context, tender *db.Tender) error
 	SaveTenderVersion(ctx context.Context, tender *db.Tender) error
-	GetTenderVersion(ctx context.Context, tenderID int, version int) (*db.Tender, error)
-	GetTenders(ctx context.Context, serviceTypes []string, limit, offset int) ([]db.Tender, error)
-	GetUserTenders(ctx context.Context, username string, limit, offset int) ([]db.Tender, error)
+	GetTenderVersion(ctx context.Context, tenderID string, version int) (*db.Tender, error)
+	GetTenderVersions(ctx context.Context, tenderID string, page db.Page) ([]db.Version, int, error)
+	// Списки возвращают страницу и общее число строк под фильтром
+	GetTenders(ctx context.Context, serviceTypes []string, page db.Page) ([]db.Tender, int, error)
+	GetUserTenders(ctx context.Context, username string, page db.Page) ([]db.Tender, int, error)
+	// ArchiveTender и RestoreTender помещают тендер в архив и возвращают из
+	// него, заполняя DeletedAt и DeletedBy. ErrNotFound, если тендер уже в
+	// архиве (не в архиве).
+	ArchiveTender(ctx context.Context, tender *db.Tender) error
+	RestoreTender(ctx context.Context, tender *db.Tender) error
 
 	CreateBid(ctx context.Context, bid *db.Bid) error
-	GetBid(ctx context.Context, bidID int) (*db.Bid, error)
+	GetBid(ctx context.Context, bidID string) (*db.Bid, error)
+	// LockBid читает предложение с блокировкой строки до конца транзакции WithTx
+	LockBid(ctx context.Context, bidID string) (*db.Bid, error)
 	UpdateBid(ctx context.Context, bid *db.Bid) error
-	GetUserBids(ctx context.Context, username string, limit, offset int) ([]db.Bid, error)
-	GetBidsForTender(ctx context.Context, tenderID int, username string, limit, offset int) ([]db.Bid, error)
-	GetBidVersion(ctx context.Context, bidID, version int) (*db.Bid, error)
+	GetUserBids(ctx context.Context, username string, page db.Page) ([]db.Bid, int, error)
+	GetBidsForTender(ctx context.Context, tenderID string, username string, page db.Page) ([]db.Bid, int, error)
+	GetBidVersion(ctx context.Context, bidID string, version int) (*db.Bid, error)
 	SaveBidVersion(ctx context.Context, bid *db.Bid) error
+	GetBidVersions(ctx context.Context, bidID string, page db.Page) ([]db.Version, int, error)
+	ArchiveBid(ctx context.Context, bid *db.Bid) error
+	RestoreBid(ctx context.Context, bid *db.Bid) error
 
-	AddBidDecision(ctx context.Context, bidID, employeeID int, decision string) error
-	GetBidDecisionsCount(ctx context.Context, bidID int) (accepts int, rejects int, err error)
-	GetResponsibleCount(ctx context.Context, organizationID int) (int, error)
+	AddBidDecision(ctx context.Context, bidID string, employeeID int, decision, comment string) error
+	RevokeBidDecision(ctx context.Context, bidID string, employeeID int) error
+	GetBidDecisions(ctx context.Context, bidID string) ([]db.BidDecision, error)
+	GetBidDecisionHistory(ctx context.Context, bidID string) ([]db.BidDecisionHistory, error)
 
-	GetBidReviewsByAuthorForTender(ctx context.Context, authorUsername string, tenderID int) ([]db.BidReview, error)
+	GetBidReviewsByAuthorForTender(ctx context.Context, authorUsername string, tenderID string) ([]db.BidReview, error)
 	CreateBidReview(ctx context.Context, review *db.BidReview) error
+
+	// PurgeArchived окончательно удаляет записи, которые находятся в архиве
+	// дольше retention
+	PurgeArchived(ctx context.Context, retention time.Duration) (db.PurgeResult, error)
 }
@@ -1,45 +1,29 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/authz"
+	"tenders/internal/lifecycle"
+
 	"github.com/go-chi/chi/v5"
 )
 
-type PaginationParams struct {
-	Limit  int
-	Offset int
-}
-
-// parsePaginationParams парсит limit и offset из query, с дефолтами и ограничениями
-func parsePaginationParams(r *http.Request) PaginationParams {
-	var params PaginationParams
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
-
-	params.Limit = 5 // дефолт
-	params.Offset = 0
-
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 {
-			params.Limit = l
-		}
-	}
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			params.Offset = o
-		}
-	}
-	return params
-}
-
 // GetTendersHandler возвращает список тендеров с фильтрами по типу serviceType
 func (h *Handler) GetTendersHandler(w http.ResponseWriter, r *http.Request) {
-	params := parsePaginationParams(r)
+	page, ok := parsePage(w, r)
+	if !ok {
+		return
+	}
 
 	// Фильтр service_type - может быть несколько через query param
 	serviceTypes := r.URL.Query()["service_type"]
@@ -52,99 +36,54 @@ func (h *Handler) GetTendersHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	tenders, err := h.Store.GetTenders(r.Context(), filteredTypes, params.Limit, params.Offset)
+	tenders, total, err := h.Store.GetTenders(r.Context(), filteredTypes, fetch(page))
 	if err != nil {
-		http.Error(w, "Failed to get tenders", http.StatusInternalServerError)
+		storageError(w, r, err, "Tenders")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tenders)
+	writePage(w, r, page, tenders, total, db.TenderCursor)
 }
 
-// GetUserTendersHandler возвращает список тендеров для пользователя username
+// GetUserTendersHandler возвращает список тендеров вызывающего пользователя
 func (h *Handler) GetUserTendersHandler(w http.ResponseWriter, r *http.Request) {
-	params := parsePaginationParams(r)
-
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "Missing username parameter", http.StatusBadRequest)
-		return
-	}
-	username = strings.TrimSpace(username)
-
-	tenders, err := h.Store.GetUserTenders(r.Context(), username, params.Limit, params.Offset)
-	if err != nil {
-		http.Error(w, "Failed to get user tenders", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tenders)
-}
-func (h *Handler) UpdateTenderStatusHandler(w http.ResponseWriter, r *http.Request) {
-	tenderIDStr := chi.URLParam(r, "tenderId")
-	tenderID, err := strconv.Atoi(tenderIDStr)
-	if err != nil || tenderID <= 0 {
-		http.Error(w, "Invalid tenderId", http.StatusBadRequest)
-		return
-	}
-
-	status := r.URL.Query().Get("status")
-	username := r.URL.Query().Get("username")
-	if status == "" || username == "" {
-		http.Error(w, "Missing status or username", http.StatusBadRequest)
+	page, ok := parsePage(w, r)
+	if !ok {
 		return
 	}
 
-	// Проверить права пользователя (ответственный за организацию)
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
-	tender, err := h.Store.GetTender(r.Context(), tenderID)
+	tenders, total, err := h.Store.GetUserTenders(r.Context(), employee.Username, fetch(page))
 	if err != nil {
-		http.Error(w, "Tender not found", http.StatusNotFound)
-		return
-	}
-
-	// Проверка, что пользователь ответственность за организацию этого тендера
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, tender.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+		storageError(w, r, err, "Tenders")
 		return
 	}
 
-	// Обновить статус тендера
-	tender.Status = status
-	err = h.Store.UpdateTender(r.Context(), tender)
-	if err != nil {
-		http.Error(w, "Failed to update status", http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tender)
+	writePage(w, r, page, tenders, total, db.TenderCursor)
 }
+
+// EditTenderHandler частично обновляет тендер. Клиент обязан передать версию,
+// которую он редактировал, в заголовке If-Match или в поле version тела:
+// без версии возвращается 428, а если тендер успел измениться - 409 Conflict.
 func (h *Handler) EditTenderHandler(w http.ResponseWriter, r *http.Request) {
-	tenderIDStr := chi.URLParam(r, "tenderId")
-	tenderID, err := strconv.Atoi(tenderIDStr)
-	if err != nil || tenderID <= 0 {
-		http.Error(w, "Invalid tenderId", http.StatusBadRequest)
+	tenderID, ok := h.pathID(w, r, "tenderId")
+	if !ok {
 		return
 	}
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "Missing username", http.StatusBadRequest)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
+	r.Body = http.MaxBytesReader(w, r.Body, 1048576)
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Cannot read body", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Cannot read body")
 		return
 	}
 	defer r.Body.Close()
@@ -153,28 +92,40 @@ func (h *Handler) EditTenderHandler(w http.ResponseWriter, r *http.Request) {
 		Name        *string `json:"name"`
 		Description *string `json:"description"`
 		ServiceType *string `json:"serviceType"`
+		Version     *int    `json:"version"`
 	}
 
 	if err := json.Unmarshal(body, &input); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid JSON")
+		return
+	}
+	if err := tenderRules.Validate(&input, tenderEditableFields...); err != nil {
+		writeValidationError(w, err)
 		return
 	}
 
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	expectedVersion, ok := editVersion(w, r, input.Version)
+	if !ok {
 		return
 	}
 
 	tender, err := h.Store.GetTender(r.Context(), tenderID)
 	if err != nil {
-		http.Error(w, "Tender not found", http.StatusNotFound)
+		storageError(w, r, err, "Tender")
 		return
 	}
 
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, tender.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	if !h.authorize(w, r, employee, authz.TenderEdit, authz.Tender(tender)) || !tenderWritable(w, tender) {
+		return
+	}
+
+	if err := h.tenders.CheckEdit(tender.Status); err != nil {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, err.Error())
+		return
+	}
+
+	if expectedVersion != tender.Version {
+		apierr.Write(w, http.StatusConflict, apierr.VersionConflict, fmt.Sprintf("Tender was modified: current version is %d", tender.Version))
 		return
 	}
 
@@ -188,149 +139,238 @@ func (h *Handler) EditTenderHandler(w http.ResponseWriter, r *http.Request) {
 		tender.ServiceType = *input.ServiceType
 	}
 
-	if err := h.Store.UpdateTender(r.Context(), tender); err != nil {
-		http.Error(w, "Failed to update tender", http.StatusInternalServerError)
+	if err := h.Store.UpdateTender(db.WithAuthor(r.Context(), employee.ID), tender); err != nil {
+		storageError(w, r, err, "Tender")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(tender.Version)))
 	json.NewEncoder(w).Encode(tender)
 }
+
+// editVersion возвращает версию, которую редактировал клиент: из заголовка
+// If-Match или, если его нет, из поля version тела. Правка без версии может
+// затереть чужие изменения, поэтому на нее отвечает 428 и возвращает false.
+func editVersion(w http.ResponseWriter, r *http.Request, bodyVersion *int) (int, bool) {
+	version, err := parseIfMatchVersion(r)
+	if err != nil {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid If-Match header")
+		return 0, false
+	}
+	if version == 0 && bodyVersion != nil {
+		version = *bodyVersion
+	}
+	if version < 1 {
+		apierr.Write(w, http.StatusPreconditionRequired, apierr.PreconditionRequired, "If-Match header or version is required")
+		return 0, false
+	}
+	return version, true
+}
+
+// parseIfMatchVersion читает номер версии из заголовка If-Match ("3", W/"3" или 3).
+// Возвращает 0, если заголовок не передан.
+func parseIfMatchVersion(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" {
+		return 0, nil
+	}
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, errors.New("invalid version")
+	}
+	return version, nil
+}
+
 func (h *Handler) RollbackTenderHandler(w http.ResponseWriter, r *http.Request) {
-	tenderIDStr := chi.URLParam(r, "tenderId")
 	versionStr := chi.URLParam(r, "version")
 
-	tenderID, err := strconv.Atoi(tenderIDStr)
-	if err != nil {
-		http.Error(w, "invalid tender ID", http.StatusBadRequest)
+	tenderID, ok := h.pathID(w, r, "tenderId")
+	if !ok {
 		return
 	}
 	version, err := strconv.Atoi(versionStr)
 	if err != nil || version < 1 {
-		http.Error(w, "invalid version number", http.StatusBadRequest)
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "invalid version number")
 		return
 	}
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "missing username", http.StatusBadRequest)
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
+	currentTender, err := h.Store.GetTender(r.Context(), tenderID)
 	if err != nil {
-		http.Error(w, "user not found", http.StatusUnauthorized)
+		storageError(w, r, err, "Tender")
 		return
 	}
 
-	currentTender, err := h.Store.GetTender(r.Context(), tenderID)
-	if err != nil {
-		http.Error(w, "tender not found", http.StatusNotFound)
+	if !h.authorize(w, r, employee, authz.TenderRollback, authz.Tender(currentTender)) || !tenderWritable(w, currentTender) {
 		return
 	}
 
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, currentTender.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "forbidden", http.StatusForbidden)
+	// Откат - такая же правка, как PATCH /edit: закрытый тендер не меняется
+	if err := h.tenders.CheckEdit(currentTender.Status); err != nil {
+		apierr.Write(w, http.StatusConflict, apierr.InvalidState, err.Error())
 		return
 	}
 
 	versionTender, err := h.Store.GetTenderVersion(r.Context(), tenderID, version)
 	if err != nil {
-		http.Error(w, "version not found", http.StatusNotFound)
+		storageError(w, r, err, "Version")
 		return
 	}
 
+	// Восстановление статуса из версии должно быть допустимым переходом
+	statusChanged := versionTender.Status != currentTender.Status
+	if statusChanged {
+		roles, err := h.tenderActorRoles(r.Context(), employee, currentTender)
+		if err != nil {
+			storageError(w, r, err, "Permissions")
+			return
+		}
+		err = h.tenders.Check(lifecycle.Request{
+			From:         currentTender.Status,
+			To:           versionTender.Status,
+			Actor:        roles,
+			TenderStatus: currentTender.Status,
+		})
+		if err != nil {
+			writeTransitionError(w, err)
+			return
+		}
+	}
+
 	// Обновляем текущий тендер данными версии с инкрементом версии
 	currentTender.Name = versionTender.Name
 	currentTender.Description = versionTender.Description
 	currentTender.ServiceType = versionTender.ServiceType
 	currentTender.Status = versionTender.Status
-	currentTender.Version++
-	// Организация и CreatedAt менять не нужно
+	// Организация и CreatedAt менять не нужно, версию увеличит UpdateTender
 
-	err = h.Store.UpdateTender(r.Context(), currentTender)
+	err = h.Store.WithTx(db.WithAuthor(r.Context(), employee.ID), func(ctx context.Context) error {
+		if err := h.Store.UpdateTender(ctx, currentTender); err != nil {
+			return err
+		}
+		if statusChanged {
+			return h.tenders.AfterTransition(ctx, currentTender.Status, currentTender)
+		}
+		return nil
+	})
 	if err != nil {
-		http.Error(w, "failed to update tender", http.StatusInternalServerError)
+		storageError(w, r, err, "Tender")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(currentTender)
+}
+
+// GetTenderStatusHandler возвращает текущий статус тендера, если тендер виден вызывающему
+func (h *Handler) GetTenderStatusHandler(w http.ResponseWriter, r *http.Request) {
+	tenderID, ok := h.pathID(w, r, "tenderId")
+	if !ok {
+		return
+	}
+
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
-	// Сохраняем новую версию после отката
-	err = h.Store.SaveTenderVersion(r.Context(), currentTender)
+	tender, err := h.Store.GetTender(r.Context(), tenderID)
 	if err != nil {
-		// Логируем ошибку, но не прерываем
-		// log.Printf("failed to save tender version: %v", err)
+		storageError(w, r, err, "Tender")
+		return
+	}
+
+	if !h.tenderVisible(w, r, employee, tender) {
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(currentTender)
+	json.NewEncoder(w).Encode(tender.Status)
 }
+
+// ChangeTenderStatusHandler переводит тендер в статус из параметра status.
+// Допустимость перехода проверяет машина состояний.
 func (h *Handler) ChangeTenderStatusHandler(w http.ResponseWriter, r *http.Request) {
 	tenderIDStr := chi.URLParam(r, "tenderId")
-	username := r.URL.Query().Get("username")
 	newStatus := r.URL.Query().Get("status")
 
-	if tenderIDStr == "" || username == "" || newStatus == "" {
-		http.Error(w, "Missing required parameters", http.StatusBadRequest)
+	if tenderIDStr == "" || newStatus == "" {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Missing required parameters")
 		return
 	}
 
-	tenderID, err := strconv.Atoi(tenderIDStr)
-	if err != nil {
-		http.Error(w, "Invalid tenderId", http.StatusBadRequest)
+	tenderID, ok := h.parseID(w, r, "tenderId", tenderIDStr)
+	if !ok {
 		return
 	}
 
-	// Проверка статуса
-	allowedStatuses := map[string]bool{"CREATED": true, "PUBLISHED": true, "CLOSED": true}
-	if !allowedStatuses[newStatus] {
-		http.Error(w, "Invalid status value", http.StatusBadRequest)
+	// Без пользователя тендер не загружается: ответ не выдает, существует ли он
+	employee, ok := caller(w, r)
+	if !ok {
 		return
 	}
 
 	tender, err := h.Store.GetTender(r.Context(), tenderID)
 	if err != nil {
-		http.Error(w, "Tender not found", http.StatusNotFound)
+		storageError(w, r, err, "Tender")
 		return
 	}
 
-	employee, err := h.Store.GetEmployeeByUsername(r.Context(), username)
-	if err != nil {
-		http.Error(w, "User not found", http.StatusUnauthorized)
+	if !h.authorize(w, r, employee, tenderStatusAction(newStatus), authz.Tender(tender)) || !tenderWritable(w, tender) {
 		return
 	}
 
-	// Проверка прав ответственного
-	isResponsible, err := h.Store.IsUserResponsibleForOrganization(r.Context(), employee.ID, tender.OrganizationID)
-	if err != nil || !isResponsible {
-		http.Error(w, "Forbidden", http.StatusForbidden)
+	// В каком качестве сотрудник меняет статус - решает машина состояний
+	roles, err := h.tenderActorRoles(r.Context(), employee, tender)
+	if err != nil {
+		storageError(w, r, err, "Permissions")
 		return
 	}
 
 	// Проверка возможности перехода статуса
-	switch tender.Status {
-	case "CREATED":
-		if newStatus != "PUBLISHED" {
-			http.Error(w, "Invalid status transition", http.StatusBadRequest)
-			return
-		}
-	case "PUBLISHED":
-		if newStatus != "CLOSED" {
-			http.Error(w, "Invalid status transition", http.StatusBadRequest)
-			return
-		}
-	case "CLOSED":
-		http.Error(w, "Tender is already closed", http.StatusBadRequest)
+	err = h.tenders.Check(lifecycle.Request{
+		From:         tender.Status,
+		To:           newStatus,
+		Actor:        roles,
+		TenderStatus: tender.Status,
+	})
+	if err != nil {
+		writeTransitionError(w, err)
 		return
 	}
 
 	tender.Status = newStatus
-	err = h.Store.UpdateTender(r.Context(), tender)
+	err = h.Store.WithTx(db.WithAuthor(r.Context(), employee.ID), func(ctx context.Context) error {
+		if err := h.Store.UpdateTender(ctx, tender); err != nil {
+			return err
+		}
+		return h.tenders.AfterTransition(ctx, tender.Status, tender)
+	})
 	if err != nil {
-		http.Error(w, "Failed to update tender status", http.StatusInternalServerError)
+		storageError(w, r, err, "Tender")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(tender)
 }
+
+// tenderStatusAction - право, нужное для перевода тендера в статус
+func tenderStatusAction(status string) authz.Action {
+	switch status {
+	case lifecycle.TenderPublished:
+		return authz.TenderPublish
+	case lifecycle.TenderClosed:
+		return authz.TenderClose
+	default:
+		// Остальные переходы отклонит машина состояний
+		return authz.TenderEdit
+	}
+}
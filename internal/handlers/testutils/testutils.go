@@ -4,6 +4,9 @@ import (
 	"context"
 	"net/http"
 
+	"tenders/db"
+	"tenders/internal/auth"
+
 	"github.com/go-chi/chi/v5"
 )
 
@@ -15,3 +18,8 @@ func WithChiURLParams(req *http.Request, params map[string]string) *http.Request
 	}
 	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, chiCtx))
 }
+
+// WithEmployee подставляет аутентифицированного сотрудника, как это делает auth.Middleware.
+func WithEmployee(req *http.Request, e *db.Employee) *http.Request {
+	return req.WithContext(auth.WithEmployee(req.Context(), e))
+}
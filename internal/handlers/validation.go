@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"tenders/internal/apierr"
+	"tenders/internal/validate"
+	"tenders/models"
+)
+
+// Правила проверки тел запросов берутся из тегов validate пакета models
+var (
+	tenderRules       = validate.MustCompile(models.Tender{})
+	bidRules          = validate.MustCompile(models.Bid{})
+	reviewRules       = validate.MustCompile(models.BidReview{})
+	employeeRules     = validate.MustCompile(models.Employee{})
+	organizationRules = validate.MustCompile(models.Organization{})
+	responsibleRules  = validate.MustCompile(models.Responsible{})
+)
+
+// Поля, которые клиент задает при создании и правке
+var (
+	tenderEditableFields = []string{"name", "description", "serviceType"}
+	tenderCreateFields   = append([]string{"organizationId"}, tenderEditableFields...)
+	bidEditableFields    = []string{"name", "description"}
+	bidCreateFields      = append([]string{"tenderId", "organizationId", "creatorUsername"}, bidEditableFields...)
+)
+
+// validationErrorResponse - тело ответа 400 со списком неверных полей
+type validationErrorResponse struct {
+	apierr.Response
+	Errors validate.Errors `json:"errors"`
+}
+
+// writeValidationError отвечает 400 со всеми ошибками проверки полей
+func writeValidationError(w http.ResponseWriter, err error) {
+	var fieldErrs validate.Errors
+	if !errors.As(err, &fieldErrs) {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, err.Error())
+		return
+	}
+	apierr.WriteJSON(w, http.StatusBadRequest, validationErrorResponse{
+		Response: apierr.Response{Reason: "Invalid fields: " + fieldErrs.Error(), Code: apierr.ValidationFailed},
+		Errors:   fieldErrs,
+	})
+}
+
+// withFieldError добавляет ошибку поля к результату validate
+func withFieldError(err error, fe validate.FieldError) error {
+	var fieldErrs validate.Errors
+	errors.As(err, &fieldErrs)
+	return append(fieldErrs, fe)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/authz"
+	"tenders/models"
+)
+
+// diffIgnored - служебные поля, которые меняются с каждой версией и в diff не нужны
+var diffIgnored = map[string]bool{"id": true, "version": true, "createdAt": true}
+
+// diffFields сравнивает два снимка одной структуры поле за полем.
+// Поля называются по json-тегам, чтобы совпадать с тем, что видит клиент.
+func diffFields(from, to interface{}) []models.FieldChange {
+	a := reflect.Indirect(reflect.ValueOf(from))
+	b := reflect.Indirect(reflect.ValueOf(to))
+	changes := []models.FieldChange{}
+	for i := 0; i < a.NumField(); i++ {
+		name, _, _ := strings.Cut(a.Type().Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" || diffIgnored[name] {
+			continue
+		}
+		av, bv := a.Field(i).Interface(), b.Field(i).Interface()
+		if !reflect.DeepEqual(av, bv) {
+			changes = append(changes, models.FieldChange{Field: name, From: av, To: bv})
+		}
+	}
+	return changes
+}
+
+// parseDiffRange читает обязательные query параметры from и to
+func parseDiffRange(w http.ResponseWriter, r *http.Request) (from, to int, ok bool) {
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil || from < 1 {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid from version")
+		return 0, 0, false
+	}
+	to, err = strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil || to < 1 {
+		apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Invalid to version")
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
+// tenderFromPath загружает тендер из пути и проверяет право вызывающего на
+// action. История неопубликованного тендера видна только тем, кто видит сам
+// тендер: остальным отвечаем 404, как и на запрос тендера.
+func (h *Handler) tenderFromPath(w http.ResponseWriter, r *http.Request, action authz.Action) (tenderID string, ok bool) {
+	tenderID, ok = h.pathID(w, r, "tenderId")
+	if !ok {
+		return "", false
+	}
+	employee, ok := caller(w, r)
+	if !ok {
+		return "", false
+	}
+	tender, err := h.Store.GetTender(r.Context(), tenderID)
+	if err != nil {
+		storageError(w, r, err, "Tender")
+		return "", false
+	}
+	if !h.tenderVisible(w, r, employee, tender) || !h.authorize(w, r, employee, action, authz.Tender(tender)) {
+		return "", false
+	}
+	return tender.ID, true
+}
+
+// GetTenderVersionsHandler возвращает историю версий тендера, от последней к первой
+func (h *Handler) GetTenderVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	tenderID, ok := h.tenderFromPath(w, r, authz.TenderHistory)
+	if !ok {
+		return
+	}
+	page, ok := parseIntKeyPage(w, r)
+	if !ok {
+		return
+	}
+
+	versions, total, err := h.Store.GetTenderVersions(r.Context(), tenderID, fetch(page))
+	if err != nil {
+		storageError(w, r, err, "Versions")
+		return
+	}
+
+	writePage(w, r, page, versions, total, db.VersionCursor)
+}
+
+// GetTenderVersionsDiffHandler возвращает поля, которые отличаются между версиями from и to
+func (h *Handler) GetTenderVersionsDiffHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseDiffRange(w, r)
+	if !ok {
+		return
+	}
+	tenderID, ok := h.tenderFromPath(w, r, authz.TenderHistory)
+	if !ok {
+		return
+	}
+
+	fromTender, err := h.Store.GetTenderVersion(r.Context(), tenderID, from)
+	if err != nil {
+		storageError(w, r, err, "Version")
+		return
+	}
+	toTender, err := h.Store.GetTenderVersion(r.Context(), tenderID, to)
+	if err != nil {
+		storageError(w, r, err, "Version")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.VersionDiff{From: from, To: to, Changes: diffFields(fromTender, toTender)})
+}
+
+// GetBidVersionsHandler возвращает историю версий предложения, от последней к первой
+func (h *Handler) GetBidVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	bid, _ := h.bidAccess(w, r, authz.BidHistory)
+	if bid == nil {
+		return
+	}
+	page, ok := parseIntKeyPage(w, r)
+	if !ok {
+		return
+	}
+
+	versions, total, err := h.Store.GetBidVersions(r.Context(), bid.ID, fetch(page))
+	if err != nil {
+		storageError(w, r, err, "Versions")
+		return
+	}
+
+	writePage(w, r, page, versions, total, db.VersionCursor)
+}
+
+// GetBidVersionsDiffHandler возвращает поля, которые отличаются между версиями from и to
+func (h *Handler) GetBidVersionsDiffHandler(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseDiffRange(w, r)
+	if !ok {
+		return
+	}
+	bid, _ := h.bidAccess(w, r, authz.BidHistory)
+	if bid == nil {
+		return
+	}
+
+	fromBid, err := h.Store.GetBidVersion(r.Context(), bid.ID, from)
+	if err != nil {
+		storageError(w, r, err, "Version")
+		return
+	}
+	toBid, err := h.Store.GetBidVersion(r.Context(), bid.ID, to)
+	if err != nil {
+		storageError(w, r, err, "Version")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.VersionDiff{From: from, To: to, Changes: diffFields(fromBid, toBid)})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"tenders/db"
+	"tenders/internal/apierr"
+	"tenders/internal/authz"
+	"tenders/internal/lifecycle"
+)
+
+// Видимость тендеров и предложений:
+//   - опубликованный тендер видит любой пользователь;
+//   - неопубликованный тендер - только ответственные его организации;
+//   - неопубликованное предложение - только автор и ответственные его организации;
+//   - опубликованное предложение - еще и ответственные организации тендера;
+//   - тендер или предложение из архива - как неопубликованные.
+//
+// Администраторы и аудиторы площадки видят все. Тем, кому неопубликованный
+// ресурс не виден, отвечаем 404, чтобы не раскрывать его существование.
+
+// can возвращает true, если у сотрудника есть право на action, и ошибку,
+// только если роли не удалось загрузить
+func (h *Handler) can(ctx context.Context, employee *db.Employee, action authz.Action, res authz.Resource) (bool, error) {
+	err := h.authz.Authorize(ctx, employee, action, res)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, authz.ErrForbidden):
+		return false, nil
+	}
+	return false, err
+}
+
+// tenderVisible проверяет, что сотрудник видит тендер. Если не видит,
+// отвечает клиенту 404 и возвращает false.
+func (h *Handler) tenderVisible(w http.ResponseWriter, r *http.Request, employee *db.Employee, tender *db.Tender) bool {
+	if tender.Status == lifecycle.TenderPublished && tender.DeletedAt == nil {
+		return true
+	}
+	visible, err := h.can(r.Context(), employee, authz.TenderViewDraft, authz.Tender(tender))
+	if err != nil {
+		storageError(w, r, err, "Permissions")
+		return false
+	}
+	if !visible {
+		apierr.Write(w, http.StatusNotFound, apierr.NotFound, "Tender not found")
+	}
+	return visible
+}
+
+// bidVisible проверяет, что сотрудник видит предложение. Неопубликованное
+// или архивное чужое предложение скрывается ответом 404, опубликованное - 403.
+func (h *Handler) bidVisible(w http.ResponseWriter, r *http.Request, employee *db.Employee, bid *db.Bid) bool {
+	visible, err := h.can(r.Context(), employee, authz.BidView, authz.Bid(bid))
+	if err != nil {
+		storageError(w, r, err, "Permissions")
+		return false
+	}
+	if visible {
+		return true
+	}
+	if bid.Status != lifecycle.BidPublished || bid.DeletedAt != nil {
+		apierr.Write(w, http.StatusNotFound, apierr.NotFound, "Bid not found")
+		return false
+	}
+
+	tender, err := h.Store.GetTender(r.Context(), bid.TenderID)
+	if err != nil {
+		storageError(w, r, err, "Tender")
+		return false
+	}
+	return h.authorize(w, r, employee, authz.TenderBids, authz.Tender(tender))
+}
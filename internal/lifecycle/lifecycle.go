@@ -0,0 +1,203 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Статусы тендера (совпадают с enum tender_status в БД)
+const (
+	TenderCreated   = "Created"
+	TenderPublished = "Published"
+	TenderClosed    = "Closed"
+)
+
+// Статусы предложения
+const (
+	BidCreated   = "Created"
+	BidPublished = "Published"
+	BidCanceled  = "Canceled"
+	BidApproved  = "Approved"
+	BidRejected  = "Rejected"
+)
+
+// Role описывает, в каком качестве инициатор выполняет переход.
+// Роли комбинируются: автор предложения может одновременно быть ответственным.
+type Role uint8
+
+const (
+	RoleResponsible Role = 1 << iota // ответственный за организацию
+	RoleAuthor                       // автор предложения
+	RoleSystem                       // переход по итогам голосования, без участия пользователя
+)
+
+// Has сообщает, содержит ли набор ролей хотя бы одну из other.
+func (r Role) Has(other Role) bool {
+	return r&other != 0
+}
+
+// ErrUnknownStatus возвращается для значения, которого нет в списке статусов.
+var ErrUnknownStatus = errors.New("unknown status")
+
+// ErrNotEditable возвращается при попытке редактировать сущность
+// в статусе, который не допускает правок.
+var ErrNotEditable = errors.New("not editable in current status")
+
+// Request описывает запрошенный переход.
+type Request struct {
+	From  string
+	To    string
+	Actor Role
+	// TenderStatus - текущий статус тендера, к которому относится сущность.
+	// Для переходов тендера совпадает с From.
+	TenderStatus string
+}
+
+// Transition - разрешенный переход между статусами.
+type Transition struct {
+	From string
+	To   string
+	// Roles - кто может инициировать переход
+	Roles Role
+	// TenderStatuses - в каких статусах тендера переход допустим (пусто - в любых)
+	TenderStatuses []string
+}
+
+func (t Transition) permits(req Request) bool {
+	if !req.Actor.Has(t.Roles) {
+		return false
+	}
+	if len(t.TenderStatuses) == 0 {
+		return true
+	}
+	for _, s := range t.TenderStatuses {
+		if s == req.TenderStatus {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError - недопустимый переход. Содержит список статусов,
+// в которые инициатор может перевести сущность из текущего состояния.
+type TransitionError struct {
+	Entity  string
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s cannot move from %s to %s (allowed: %s)",
+		e.Entity, e.From, e.To, strings.Join(e.Allowed, ", "))
+}
+
+// Hook вызывается после успешного перехода в статус.
+type Hook[T any] func(ctx context.Context, subject T) error
+
+// Machine хранит статусы, переходы и хуки для одного типа сущности.
+type Machine[T any] struct {
+	entity      string
+	statuses    []string
+	transitions []Transition
+	editable    []string
+	hooks       map[string][]Hook[T]
+}
+
+// NewMachine создает машину состояний для сущности entity.
+func NewMachine[T any](entity string, statuses []string, transitions []Transition) *Machine[T] {
+	return &Machine[T]{
+		entity:      entity,
+		statuses:    statuses,
+		transitions: transitions,
+		hooks:       make(map[string][]Hook[T]),
+	}
+}
+
+// Entity возвращает название сущности (tender, bid).
+func (m *Machine[T]) Entity() string {
+	return m.entity
+}
+
+// Statuses возвращает все известные статусы.
+func (m *Machine[T]) Statuses() []string {
+	return append([]string(nil), m.statuses...)
+}
+
+// Valid проверяет, что статус известен машине.
+func (m *Machine[T]) Valid(status string) bool {
+	for _, s := range m.statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed возвращает статусы, доступные инициатору из req.From.
+// Поле req.To игнорируется.
+func (m *Machine[T]) Allowed(req Request) []string {
+	allowed := []string{}
+	for _, t := range m.transitions {
+		if t.From == req.From && t.permits(req) {
+			allowed = append(allowed, t.To)
+		}
+	}
+	return allowed
+}
+
+// Check проверяет переход req.From -> req.To с учетом роли инициатора
+// и статуса тендера. Возвращает ErrUnknownStatus или *TransitionError.
+func (m *Machine[T]) Check(req Request) error {
+	if !m.Valid(req.To) {
+		return fmt.Errorf("%w: %q", ErrUnknownStatus, req.To)
+	}
+	for _, t := range m.transitions {
+		if t.From == req.From && t.To == req.To && t.permits(req) {
+			return nil
+		}
+	}
+	return &TransitionError{
+		Entity:  m.entity,
+		From:    req.From,
+		To:      req.To,
+		Allowed: m.Allowed(req),
+	}
+}
+
+// SetEditable задает статусы, в которых разрешено редактирование полей.
+// Пока список не задан, редактирование разрешено в любом статусе.
+func (m *Machine[T]) SetEditable(statuses ...string) {
+	m.editable = statuses
+}
+
+// CheckEdit возвращает ErrNotEditable, если в статусе status правки запрещены.
+func (m *Machine[T]) CheckEdit(status string) error {
+	if len(m.editable) == 0 {
+		return nil
+	}
+	for _, s := range m.editable {
+		if s == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is %s (editable in: %s)",
+		ErrNotEditable, m.entity, status, strings.Join(m.editable, ", "))
+}
+
+// OnEnter регистрирует хук, выполняемый после перехода в статус.
+func (m *Machine[T]) OnEnter(status string, hook Hook[T]) {
+	m.hooks[status] = append(m.hooks[status], hook)
+}
+
+// AfterTransition выполняет хуки статуса, в который перешла сущность.
+func (m *Machine[T]) AfterTransition(ctx context.Context, status string, subject T) error {
+	for _, hook := range m.hooks[status] {
+		if err := hook(ctx, subject); err != nil {
+			return err
+		}
+	}
+	return nil
+}
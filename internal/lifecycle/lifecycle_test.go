@@ -0,0 +1,112 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tenders/db"
+	"tenders/internal/lifecycle"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenderTransitions(t *testing.T) {
+	m := lifecycle.NewTenderMachine()
+
+	cases := []struct {
+		from, to string
+		actor    lifecycle.Role
+		ok       bool
+	}{
+		{lifecycle.TenderCreated, lifecycle.TenderPublished, lifecycle.RoleResponsible, true},
+		{lifecycle.TenderPublished, lifecycle.TenderClosed, lifecycle.RoleResponsible, true},
+		{lifecycle.TenderPublished, lifecycle.TenderClosed, lifecycle.RoleSystem, true},
+		{lifecycle.TenderCreated, lifecycle.TenderClosed, lifecycle.RoleResponsible, false},
+		{lifecycle.TenderClosed, lifecycle.TenderPublished, lifecycle.RoleResponsible, false},
+		{lifecycle.TenderCreated, lifecycle.TenderPublished, lifecycle.RoleSystem, false},
+	}
+	for _, c := range cases {
+		err := m.Check(lifecycle.Request{From: c.from, To: c.to, Actor: c.actor, TenderStatus: c.from})
+		if c.ok {
+			require.NoError(t, err, "%s -> %s", c.from, c.to)
+		} else {
+			var te *lifecycle.TransitionError
+			require.ErrorAs(t, err, &te, "%s -> %s", c.from, c.to)
+		}
+	}
+}
+
+func TestBidTransitionsDependOnTender(t *testing.T) {
+	m := lifecycle.NewBidMachine()
+
+	req := lifecycle.Request{
+		From:         lifecycle.BidCreated,
+		To:           lifecycle.BidPublished,
+		Actor:        lifecycle.RoleAuthor,
+		TenderStatus: lifecycle.TenderCreated,
+	}
+	var te *lifecycle.TransitionError
+	require.ErrorAs(t, m.Check(req), &te)
+	require.Equal(t, []string{lifecycle.BidCanceled}, te.Allowed)
+
+	req.TenderStatus = lifecycle.TenderPublished
+	require.NoError(t, m.Check(req))
+}
+
+func TestBidApprovalOnlyBySystem(t *testing.T) {
+	m := lifecycle.NewBidMachine()
+
+	req := lifecycle.Request{
+		From:         lifecycle.BidPublished,
+		To:           lifecycle.BidApproved,
+		Actor:        lifecycle.RoleResponsible | lifecycle.RoleAuthor,
+		TenderStatus: lifecycle.TenderPublished,
+	}
+	var te *lifecycle.TransitionError
+	require.ErrorAs(t, m.Check(req), &te)
+	require.Equal(t, []string{lifecycle.BidCanceled}, te.Allowed)
+
+	req.Actor = lifecycle.RoleSystem
+	require.NoError(t, m.Check(req))
+}
+
+func TestUnknownStatus(t *testing.T) {
+	m := lifecycle.NewTenderMachine()
+	err := m.Check(lifecycle.Request{From: lifecycle.TenderCreated, To: "PUBLISHED", Actor: lifecycle.RoleResponsible})
+	require.ErrorIs(t, err, lifecycle.ErrUnknownStatus)
+}
+
+func TestHooks(t *testing.T) {
+	m := lifecycle.NewBidMachine()
+	var called []string
+	m.OnEnter(lifecycle.BidApproved, func(ctx context.Context, b *db.Bid) error {
+		called = append(called, b.ID)
+		return nil
+	})
+	failure := errors.New("boom")
+	m.OnEnter(lifecycle.BidRejected, func(ctx context.Context, b *db.Bid) error {
+		return failure
+	})
+
+	require.NoError(t, m.AfterTransition(context.Background(), lifecycle.BidApproved, &db.Bid{ID: "7"}))
+	require.NoError(t, m.AfterTransition(context.Background(), lifecycle.BidCanceled, &db.Bid{ID: "8"}))
+	require.ErrorIs(t, m.AfterTransition(context.Background(), lifecycle.BidRejected, &db.Bid{ID: "9"}), failure)
+	require.Equal(t, []string{"7"}, called)
+}
+
+func TestTenderEditableStatuses(t *testing.T) {
+	m := lifecycle.NewTenderMachine()
+	require.NoError(t, m.CheckEdit(lifecycle.TenderCreated))
+	require.NoError(t, m.CheckEdit(lifecycle.TenderPublished))
+	require.ErrorIs(t, m.CheckEdit(lifecycle.TenderClosed), lifecycle.ErrNotEditable)
+}
+
+func TestBidEditableStatuses(t *testing.T) {
+	m := lifecycle.NewBidMachine()
+	require.NoError(t, m.CheckEdit(lifecycle.BidCreated))
+	require.NoError(t, m.CheckEdit(lifecycle.BidPublished))
+	for _, status := range []string{lifecycle.BidCanceled, lifecycle.BidApproved, lifecycle.BidRejected} {
+		require.ErrorIs(t, m.CheckEdit(status), lifecycle.ErrNotEditable, status)
+	}
+}
@@ -0,0 +1,39 @@
+package lifecycle
+
+import "tenders/db"
+
+// NewTenderMachine описывает жизненный цикл тендера:
+// Created -> Published -> Closed. Закрыть опубликованный тендер может
+// ответственный или система (при одобрении предложения).
+// Закрытый тендер редактировать нельзя.
+func NewTenderMachine() *Machine[*db.Tender] {
+	m := NewMachine[*db.Tender]("tender",
+		[]string{TenderCreated, TenderPublished, TenderClosed},
+		[]Transition{
+			{From: TenderCreated, To: TenderPublished, Roles: RoleResponsible},
+			{From: TenderPublished, To: TenderClosed, Roles: RoleResponsible | RoleSystem},
+		},
+	)
+	m.SetEditable(TenderCreated, TenderPublished)
+	return m
+}
+
+// NewBidMachine описывает жизненный цикл предложения.
+// Публиковать можно только предложения к опубликованному тендеру,
+// а Approved/Rejected выставляются только по итогам голосования.
+// Решенное или отмененное предложение редактировать нельзя.
+func NewBidMachine() *Machine[*db.Bid] {
+	people := RoleAuthor | RoleResponsible
+	m := NewMachine[*db.Bid]("bid",
+		[]string{BidCreated, BidPublished, BidCanceled, BidApproved, BidRejected},
+		[]Transition{
+			{From: BidCreated, To: BidPublished, Roles: people, TenderStatuses: []string{TenderPublished}},
+			{From: BidCreated, To: BidCanceled, Roles: people},
+			{From: BidPublished, To: BidCanceled, Roles: people},
+			{From: BidPublished, To: BidApproved, Roles: RoleSystem, TenderStatuses: []string{TenderPublished}},
+			{From: BidPublished, To: BidRejected, Roles: RoleSystem, TenderStatuses: []string{TenderPublished}},
+		},
+	)
+	m.SetEditable(BidCreated, BidPublished)
+	return m
+}
@@ -0,0 +1,159 @@
+// Package openapi проверяет запросы и ответы API по спецификации openapi.yml.
+// Спецификация ведется вручную, и middleware ловит расхождения между ней и
+// обработчиками: запрос, не соответствующий спецификации (параметры пути и
+// query, тело, значения перечислений), отклоняется до вызова обработчика, а в
+// строгом режиме проверяется и ответ.
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"tenders/internal/apierr"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// BasePath - префикс, под которым роутер регистрирует пути спецификации
+const BasePath = "/api"
+
+// Options - режим проверки
+type Options struct {
+	// Responses включает проверку ответов: несоответствия пишутся в лог,
+	// ответ уходит клиенту без изменений
+	Responses bool
+	// Strict - ответ, не соответствующий спецификации, заменяется ответом 500.
+	// Включает проверку ответов. Режим для тестов и стендов.
+	Strict bool
+	// Logf пишет найденные несоответствия, по умолчанию log.Printf
+	Logf func(format string, args ...interface{})
+}
+
+// Validator проверяет запросы и ответы по загруженной спецификации
+type Validator struct {
+	router routers.Router
+	opts   Options
+}
+
+// New разбирает и проверяет спецификацию. Ошибка означает, что сама
+// спецификация некорректна, и сервер не должен стартовать.
+func New(spec []byte, opts Options) (*Validator, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	// Пути считаются от BasePath, какой бы адрес ни был указан в servers
+	doc.Servers = openapi3.Servers{{URL: BasePath}}
+
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build openapi router: %w", err)
+	}
+	if opts.Logf == nil {
+		opts.Logf = log.Printf
+	}
+	return &Validator{router: router, opts: opts}, nil
+}
+
+// filterOptions - настройки kin-openapi
+func filterOptions() *openapi3filter.Options {
+	opts := &openapi3filter.Options{
+		// Токен проверяет auth.Middleware, здесь проверяется только формат запроса
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		MultiError:         true,
+		// Обработчик должен видеть тело таким, каким его прислал клиент
+		SkipSettingDefaults: true,
+	}
+	opts.WithCustomSchemaErrorFunc(func(err *openapi3.SchemaError) string {
+		if path := err.JSONPointer(); len(path) > 0 {
+			return strings.Join(path, ".") + ": " + err.Reason
+		}
+		return err.Reason
+	})
+	return opts
+}
+
+// Middleware проверяет запрос перед обработчиком и, если включено, ответ
+// после него. Запросы к путям, которых нет в спецификации, пропускаются
+// без проверки: на них ответит роутер.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    filterOptions(),
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			apierr.Write(w, http.StatusBadRequest, apierr.InvalidRequest, "Request does not match API spec: "+err.Error())
+			return
+		}
+
+		if !v.opts.Responses && !v.opts.Strict {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recorder{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if err := v.validateResponse(r.Context(), input, rec); err != nil {
+			v.opts.Logf("openapi: %s %s: response %d does not match spec: %v", r.Method, r.URL.Path, rec.status, err)
+			if v.opts.Strict {
+				apierr.Write(w, http.StatusInternalServerError, apierr.Internal, "Response does not match API spec: "+err.Error())
+				return
+			}
+		}
+		rec.flush(w)
+	})
+}
+
+func (v *Validator) validateResponse(ctx context.Context, input *openapi3filter.RequestValidationInput, rec *recorder) error {
+	out := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 rec.status,
+		Header:                 rec.header,
+		Options:                filterOptions(),
+	}
+	out.SetBodyBytes(rec.body.Bytes())
+	return openapi3filter.ValidateResponse(ctx, out)
+}
+
+// recorder задерживает ответ обработчика до окончания проверки
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *recorder) Header() http.Header { return rec.header }
+
+func (rec *recorder) WriteHeader(status int) { rec.status = status }
+
+func (rec *recorder) Write(p []byte) (int, error) { return rec.body.Write(p) }
+
+// flush отправляет задержанный ответ клиенту
+func (rec *recorder) flush(w http.ResponseWriter) {
+	for key, values := range rec.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(rec.status)
+	w.Write(rec.body.Bytes())
+}
@@ -0,0 +1,92 @@
+package openapi_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tenders/internal/openapi"
+
+	"github.com/stretchr/testify/require"
+)
+
+const spec = `
+openapi: 3.0.0
+info:
+  title: test
+  version: "1"
+paths:
+  /items/{itemId}:
+    get:
+      parameters:
+        - name: itemId
+          in: path
+          required: true
+          schema:
+            type: integer
+            minimum: 1
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id:
+                    type: integer
+                required:
+                  - id
+`
+
+func serve(t *testing.T, opts openapi.Options, target string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	v, err := openapi.New([]byte(spec), opts)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	v.Middleware(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestRequestValidation(t *testing.T) {
+	called := false
+	handler := func(w http.ResponseWriter, r *http.Request) { called = true }
+
+	// Неверный параметр пути отклоняется до обработчика
+	w := serve(t, openapi.Options{}, "/api/items/0", handler)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"invalid_request"`)
+	require.False(t, called)
+
+	// Путей вне спецификации middleware не касается
+	w = serve(t, openapi.Options{}, "/api/other", handler)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.True(t, called)
+}
+
+func TestResponseValidation(t *testing.T) {
+	badResponse := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"no id"}`))
+	}
+	var logged []string
+	logf := func(format string, args ...interface{}) { logged = append(logged, fmt.Sprintf(format, args...)) }
+
+	// Без проверки ответов ответ не трогается
+	w := serve(t, openapi.Options{Logf: logf}, "/api/items/1", badResponse)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Empty(t, logged)
+
+	// Responses: несоответствие в логе, клиент получает ответ обработчика
+	w = serve(t, openapi.Options{Responses: true, Logf: logf}, "/api/items/1", badResponse)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"name":"no id"}`, w.Body.String())
+	require.Len(t, logged, 1)
+	require.Contains(t, logged[0], `"id" is missing`)
+
+	// Strict: несоответствие превращается в 500
+	w = serve(t, openapi.Options{Strict: true, Logf: logf}, "/api/items/1", badResponse)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"internal_error"`)
+}
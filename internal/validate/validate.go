@@ -0,0 +1,231 @@
+// Package validate проверяет данные по тегам `validate:"..."` структур пакета models.
+// Правила берутся из модели, а проверяется любая структура с теми же json именами
+// полей (db.Tender, тело запроса на правку и т.д.), поэтому ограничения описаны
+// в одном месте.
+//
+// Поддерживаемые правила:
+//   - required - поле не пустое (строка не из одних пробелов, число не 0)
+//   - max=N, min=N - длина строки в символах (рунах) или значение числа
+//   - oneof=a b c - значение из списка
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError - нарушенное правило одного поля
+type FieldError struct {
+	// Field - имя поля, как в json
+	Field string `json:"field"`
+	// Rule - нарушенное правило: required, max, min, oneof или правило
+	// обработчика, которого нет в тегах (например, pattern у username)
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors - все ошибки проверки. Пустой Errors не возвращается как error.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// rule - одно правило из тега
+type rule struct {
+	name  string
+	param string
+	limit int
+	oneOf []string
+}
+
+// Rules - правила полей модели по json именам
+type Rules struct {
+	fields map[string][]rule
+	// order - порядок полей модели, чтобы ошибки шли в порядке объявления
+	order []string
+}
+
+// MustCompile разбирает теги модели и паникует на неизвестном правиле.
+// Вызывается при инициализации пакета.
+func MustCompile(model interface{}) *Rules {
+	rules, err := Compile(model)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// Compile разбирает теги validate модели
+func Compile(model interface{}) (*Rules, error) {
+	t := reflect.TypeOf(model)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validate: %s is not a struct", t)
+	}
+
+	rules := &Rules{fields: map[string][]rule{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		name := jsonName(f)
+		for _, part := range strings.Split(tag, ",") {
+			key, param, _ := strings.Cut(part, "=")
+			r := rule{name: key, param: param}
+			switch key {
+			case "required":
+			case "max", "min":
+				limit, err := strconv.Atoi(param)
+				if err != nil {
+					return nil, fmt.Errorf("validate: %s.%s: bad %s=%q", t.Name(), f.Name, key, param)
+				}
+				r.limit = limit
+			case "oneof":
+				r.oneOf = strings.Fields(param)
+			default:
+				return nil, fmt.Errorf("validate: %s.%s: unknown rule %q", t.Name(), f.Name, key)
+			}
+			rules.fields[name] = append(rules.fields[name], r)
+		}
+		rules.order = append(rules.order, name)
+	}
+	return rules, nil
+}
+
+// Validate проверяет поля v, у которых есть правила в модели. Если переданы
+// fields, проверяются только они. Поле-указатель, равное nil, считается не
+// переданным и не проверяется: так описываются тела запросов на частичную правку.
+// Возвращает Errors со всеми нарушениями или nil.
+func (rs *Rules) Validate(v interface{}, fields ...string) error {
+	values := map[string]reflect.Value{}
+	collect(reflect.ValueOf(v), values)
+
+	only := map[string]bool{}
+	for _, f := range fields {
+		only[f] = true
+	}
+
+	var errs Errors
+	for _, name := range rs.order {
+		if len(only) > 0 && !only[name] {
+			continue
+		}
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		for _, r := range rs.fields[name] {
+			if fe := r.check(name, value); fe != nil {
+				errs = append(errs, *fe)
+				// Остальные правила поля после первой ошибки не сообщают ничего нового
+				break
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// collect собирает значения полей структуры по json именам
+func collect(v reflect.Value, out map[string]reflect.Value) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous {
+			collect(v.Field(i), out)
+			continue
+		}
+		out[jsonName(f)] = v.Field(i)
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+func (r rule) check(field string, v reflect.Value) *FieldError {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	fail := func(format string, args ...interface{}) *FieldError {
+		return &FieldError{Field: field, Rule: r.name, Message: field + " " + fmt.Sprintf(format, args...)}
+	}
+
+	switch r.name {
+	case "required":
+		if v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "" || v.IsZero() {
+			return fail("is required")
+		}
+	case "max":
+		if n, ok := size(v); ok && n > r.limit {
+			return fail("must be at most %d%s", r.limit, unit(v))
+		}
+	case "min":
+		if n, ok := size(v); ok && n < r.limit {
+			return fail("must be at least %d%s", r.limit, unit(v))
+		}
+	case "oneof":
+		s := fmt.Sprint(v.Interface())
+		for _, allowed := range r.oneOf {
+			if s == allowed {
+				return nil
+			}
+		}
+		return fail("must be one of: %s", strings.Join(r.oneOf, ", "))
+	}
+	return nil
+}
+
+// size - длина строки в рунах или значение целого числа
+func size(v reflect.Value) (int, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(v.String()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), true
+	case reflect.Slice, reflect.Map:
+		return v.Len(), true
+	}
+	return 0, false
+}
+
+func unit(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return " characters"
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package validate_test
+
+import (
+	"strings"
+	"testing"
+
+	"tenders/internal/validate"
+	"tenders/models"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fieldsOf(t *testing.T, err error) []string {
+	t.Helper()
+	require.Error(t, err)
+	errs, ok := err.(validate.Errors)
+	require.True(t, ok, "expected validate.Errors, got %T", err)
+	fields := make([]string, len(errs))
+	for i, fe := range errs {
+		fields[i] = fe.Field
+	}
+	return fields
+}
+
+func TestTenderRules(t *testing.T) {
+	rules := validate.MustCompile(models.Tender{})
+
+	valid := models.Tender{
+		Name:           strings.Repeat("т", 100),
+		Description:    strings.Repeat("д", 500),
+		ServiceType:    "Delivery",
+		Status:         "Created",
+		OrganizationID: "550e8400-e29b-41d4-a716-446655440000",
+	}
+	// Длина считается в символах: 100 кириллических букв - это 200 байт
+	require.NoError(t, rules.Validate(valid))
+
+	invalid := valid
+	invalid.Name = strings.Repeat("т", 101)
+	invalid.Description = "   "
+	invalid.ServiceType = "Repair"
+	invalid.OrganizationID = ""
+	// Все ошибки собираются сразу, в порядке полей модели
+	require.Equal(t, []string{"name", "description", "serviceType", "organizationId"}, fieldsOf(t, rules.Validate(invalid)))
+
+	// Проверяются только перечисленные поля
+	require.Equal(t, []string{"serviceType"}, fieldsOf(t, rules.Validate(invalid, "serviceType", "status")))
+}
+
+func TestPartialInput(t *testing.T) {
+	rules := validate.MustCompile(models.Bid{})
+
+	// Тело частичной правки: не переданные поля не проверяются
+	var input struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+	}
+	require.NoError(t, rules.Validate(&input))
+
+	empty := ""
+	input.Description = &empty
+	errs := rules.Validate(&input)
+	require.Equal(t, []string{"description"}, fieldsOf(t, errs))
+	require.Equal(t, "required", errs.(validate.Errors)[0].Rule)
+}
+
+func TestUnknownRule(t *testing.T) {
+	_, err := validate.Compile(struct {
+		Name string `validate:"email"`
+	}{})
+	require.ErrorContains(t, err, "unknown rule")
+}
@@ -4,55 +4,138 @@ import "time"
 
 // Сущность Тендера
 type Tender struct {
-	ID             int       `db:"id" json:"id"`
-	Name           string    `db:"name" json:"name" validate:"required,max=100"`
-	Description    string    `db:"description" json:"description" validate:"required,max=500"`
-	ServiceType    string    `db:"service_type" json:"serviceType" validate:"required,oneof=Construction Delivery Manufacture"`
-	Status         string    `db:"status" json:"status" validate:"required,oneof=Created Published Closed"`
-	OrganizationID int       `db:"organization_id" json:"organizationId" validate:"required"`
-	Version        int       `db:"version" json:"version"`
-	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt      time.Time `db:"updated_at" json:"-"`
+	ID             string     `db:"id" json:"id"`
+	Name           string     `db:"name" json:"name" validate:"required,max=100"`
+	Description    string     `db:"description" json:"description" validate:"required,max=500"`
+	ServiceType    string     `db:"service_type" json:"serviceType" validate:"required,oneof=Construction Delivery Manufacture"`
+	Status         string     `db:"status" json:"status" validate:"required,oneof=Created Published Closed"`
+	OrganizationID string     `db:"organization_id" json:"organizationId" validate:"required,max=100"`
+	Version        int        `db:"version" json:"version"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"-"`
+	DeletedAt      *time.Time `db:"deleted_at" json:"deletedAt,omitempty"`
+	DeletedBy      *int       `db:"deleted_by" json:"deletedBy,omitempty"`
 }
 
-// Сущность Предложения
+// Сущность Предложения. В API автор задается полями OrganizationID и
+// CreatorUsername, AuthorType и AuthorID оставлены для совместимости со схемой.
 type Bid struct {
-	ID          int       `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name" validate:"required,max=100"`
-	Description string    `db:"description" json:"description" validate:"required,max=500"`
-	Status      string    `db:"status" json:"status" validate:"required,oneof=Created Published Canceled Approved Rejected"`
-	TenderID    int       `db:"tender_id" json:"tenderId" validate:"required"`
-	AuthorType  string    `db:"author_type" json:"authorType" validate:"required,oneof=Organization User"`
-	AuthorID    int       `db:"author_id" json:"authorId" validate:"required"`
-	Version     int       `db:"version" json:"version"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time `db:"updated_at" json:"-"`
+	ID              string     `db:"id" json:"id"`
+	Name            string     `db:"name" json:"name" validate:"required,max=100"`
+	Description     string     `db:"description" json:"description" validate:"required,max=500"`
+	Status          string     `db:"status" json:"status" validate:"required,oneof=Created Published Canceled Approved Rejected"`
+	TenderID        string     `db:"tender_id" json:"tenderId" validate:"required,max=100"`
+	AuthorType      string     `db:"author_type" json:"authorType" validate:"required,oneof=Organization User"`
+	AuthorID        int        `db:"author_id" json:"authorId" validate:"required"`
+	OrganizationID  string     `db:"organization_id" json:"organizationId" validate:"required,max=100"`
+	CreatorUsername string     `db:"creator_username" json:"creatorUsername" validate:"required,max=50"`
+	Version         int        `db:"version" json:"version"`
+	CreatedAt       time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"-"`
+	DeletedAt       *time.Time `db:"deleted_at" json:"deletedAt,omitempty"`
+	DeletedBy       *int       `db:"deleted_by" json:"deletedBy,omitempty"`
 }
 
 // Сущность Отзыва
 type BidReview struct {
 	ID          int       `db:"id" json:"id"`
 	Description string    `db:"description" json:"description" validate:"required,max=1000"`
-	BidID       int       `db:"bid_id" json:"bidId"`
+	BidID       string    `db:"bid_id" json:"bidId"`
 	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
 }
 
 // Сущность Пользователя (из БД, для связи)
 type Employee struct {
-	ID        int       `db:"id" json:"id"`
-	Username  string    `db:"username" json:"username"`
-	FirstName string    `db:"first_name" json:"firstName"`
-	LastName  string    `db:"last_name" json:"lastName"`
-	CreatedAt time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt time.Time `db:"updated_at" json:"-"`
+	ID            int        `db:"id" json:"id"`
+	Username      string     `db:"username" json:"username" validate:"required,max=50"`
+	FirstName     string     `db:"first_name" json:"firstName" validate:"max=50"`
+	LastName      string     `db:"last_name" json:"lastName" validate:"max=50"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updatedAt"`
+	DeactivatedAt *time.Time `db:"deactivated_at" json:"deactivatedAt,omitempty"`
 }
 
 // Сущность Организации (из БД, для связи)
 type Organization struct {
-	ID          int       `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Description string    `db:"description" json:"description"`
-	Type        string    `db:"type" json:"type"`
+	ID          string    `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name" validate:"required,max=100"`
+	Description string    `db:"description" json:"description" validate:"max=500"`
+	Type        string    `db:"type" json:"type" validate:"required,oneof=IE LLC JSC"`
 	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt   time.Time `db:"updated_at" json:"-"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// Ответственный за организацию
+type Responsible struct {
+	UserID         int    `db:"user_id" json:"userId"`
+	Username       string `db:"username" json:"username"`
+	OrganizationID string `db:"organization_id" json:"organizationId"`
+	Role           string `db:"role" json:"role" validate:"required,max=50"`
+}
+
+// Запись истории версий тендера или предложения
+type Version struct {
+	Version        int       `db:"version" json:"version"`
+	Status         string    `db:"status" json:"status"`
+	AuthorID       *int      `db:"author_id" json:"authorId"`
+	AuthorUsername *string   `db:"author_username" json:"authorUsername"`
+	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Изменение одного поля между версиями
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Различия между версиями from и to
+type VersionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// Действующий голос ответственного по предложению
+type BidDecision struct {
+	ID        int       `db:"id" json:"id"`
+	BidID     string    `db:"bid_id" json:"bidId"`
+	UserID    int       `db:"user_id" json:"userId"`
+	Username  string    `db:"username" json:"username"`
+	Decision  string    `db:"decision" json:"decision"`
+	Comment   string    `db:"comment" json:"comment"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Переписанный или отозванный голос
+type BidDecisionHistory struct {
+	ID        int        `db:"id" json:"id"`
+	BidID     string     `db:"bid_id" json:"bidId"`
+	UserID    int        `db:"user_id" json:"userId"`
+	Username  string     `db:"username" json:"username"`
+	Decision  string     `db:"decision" json:"decision"`
+	Comment   string     `db:"comment" json:"comment"`
+	DecidedAt *time.Time `db:"decided_at" json:"decidedAt"`
+	Action    string     `db:"action" json:"action"`
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
+}
+
+// Политика принятия решений организации или тендера
+type ApprovalPolicy struct {
+	ID              int            `db:"id" json:"id"`
+	OrganizationID  string         `db:"organization_id" json:"organizationId"`
+	TenderID        *string        `db:"tender_id" json:"tenderId"`
+	Kind            string         `db:"kind" json:"kind"`
+	Required        int            `db:"required" json:"required"`
+	RejectThreshold int            `db:"reject_threshold" json:"rejectThreshold"`
+	RoleWeights     map[string]int `db:"role_weights" json:"roleWeights"`
+	CreatedAt       time.Time      `db:"created_at" json:"createdAt"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updatedAt"`
+}
+
+// Сколько записей удалила очистка архива
+type PurgeResult struct {
+	Tenders int `json:"tenders"`
+	Bids    int `json:"bids"`
+	Reviews int `json:"reviews"`
 }
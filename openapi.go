@@ -0,0 +1,11 @@
+// Package tenders встраивает спецификацию API openapi.yml в бинарник, чтобы
+// сервер и тесты проверяли запросы и ответы по той же спецификации, что
+// лежит в репозитории.
+package tenders
+
+import _ "embed"
+
+// OpenAPISpec - содержимое openapi.yml
+//
+//go:embed openapi.yml
+var OpenAPISpec []byte